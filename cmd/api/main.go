@@ -2,17 +2,32 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"golang-connect-marketplace/config"
 	authHndl "golang-connect-marketplace/internal/auth/http/handlers"
 	authRoutes "golang-connect-marketplace/internal/auth/http/routes"
 	authRepo "golang-connect-marketplace/internal/auth/repo"
 	authSvc "golang-connect-marketplace/internal/auth/service"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/internal/marketplace/events"
+	pgEvents "golang-connect-marketplace/internal/marketplace/events/postgres"
 	marketHndl "golang-connect-marketplace/internal/marketplace/http/handlers"
 	marketRoutes "golang-connect-marketplace/internal/marketplace/http/routes"
+	"golang-connect-marketplace/internal/marketplace/jobs"
+	"golang-connect-marketplace/internal/marketplace/notifications"
+	loggerNotifier "golang-connect-marketplace/internal/marketplace/notifications/logger"
 	"golang-connect-marketplace/internal/marketplace/paymentproviders"
 	marketRepos "golang-connect-marketplace/internal/marketplace/repos"
+	"golang-connect-marketplace/internal/marketplace/screening"
 	marketSvc "golang-connect-marketplace/internal/marketplace/services"
+	marketStorage "golang-connect-marketplace/internal/marketplace/storage"
 	localStorage "golang-connect-marketplace/internal/marketplace/storage/local"
+	s3Storage "golang-connect-marketplace/internal/marketplace/storage/s3"
+	"golang-connect-marketplace/internal/marketplace/tracking"
+	fakeTracking "golang-connect-marketplace/internal/marketplace/tracking/fake"
+	"golang-connect-marketplace/pkg/imaging"
 	"golang-connect-marketplace/pkg/middleware"
 	"log/slog"
 	"os"
@@ -26,6 +41,25 @@ import (
 	_ "github.com/lib/pq"
 )
 
+const (
+	storageBackendLocal  = "local"
+	storageBackendS3     = "s3"
+	eventsBackendMemory  = "memory"
+	eventsBackendPG      = "postgres"
+	trackingProviderFake = "fake"
+)
+
+var (
+	errUnknownStorageBackend   = errors.New("unknown storage backend")
+	errInvalidImageURLTTL      = errors.New("image url ttl must be positive")
+	errInvalidListingDuration  = errors.New("default listing duration must be positive")
+	errInvalidImportStaleAfter = errors.New("import stale period must be positive")
+	errUnknownEventsBackend    = errors.New("unknown events backend")
+	errUnknownTrackingProvider = errors.New("unknown tracking provider")
+	errMissingTrackingProvider = errors.New("tracking provider must be set")
+	errInvalidDeliveryDelay    = errors.New("fake delivery delay must be positive")
+)
+
 func main() {
 	var cfg config.AppConfig
 
@@ -53,14 +87,43 @@ func main() {
 	logger.Info("connected to database")
 
 	e := echo.New()
-	e.Static(cfg.StorageConfig.UploadDir, cfg.StorageConfig.UploadDir)
 
 	e.Use(middleware.RequestLogger(logger))
 	e.Use(echoMiddleware.BodyLimit(cfg.APIConfig.MaxPayloadSize))
 
+	ctx := context.Background()
+	notifier := loggerNotifier.NewLoggerNotifier(logger)
+
 	authSvc := setupAuth(e, db, &cfg.AuthConfig)
-	listingsRepo := setupListings(e, db, authSvc, &cfg.StorageConfig)
-	setupPayments(e, db, authSvc, listingsRepo, &cfg.PaymentsConfig)
+	publisher := setupEvents(ctx, e, db, authSvc, logger, &cfg)
+	paymentProvider := paymentproviders.NewStripePaymentProvider(
+		cfg.PaymentsConfig.StripeSecretKey,
+		cfg.PaymentsConfig.StripeWebhookSecret,
+	)
+	listingsRepo, listingsSvc := setupListings(e, db, authSvc, notifier, paymentProvider, &cfg)
+	setupPayments(
+		e,
+		db,
+		authSvc,
+		listingsRepo,
+		paymentProvider,
+		notifier,
+		publisher,
+		&cfg.PaymentsConfig,
+	)
+	setupMessages(e, db, authSvc, listingsRepo, notifier, publisher)
+	setupModeration(e, db, authSvc, listingsRepo, listingsSvc, notifier)
+	shipmentsSvc := setupShipments(e, db, authSvc, notifier, publisher, &cfg.ShipmentsConfig)
+
+	startListingsJobs(ctx, logger, listingsSvc, &cfg)
+
+	go jobs.RunPeriodically(
+		ctx,
+		logger,
+		"track_shipments",
+		cfg.ShipmentsConfig.CheckInterval,
+		shipmentsSvc.TrackShipments,
+	)
 
 	err = e.Start("0.0.0.0:6767")
 	if err != nil {
@@ -77,19 +140,210 @@ func setupAuth(e *echo.Echo, db *sqlx.DB, cfg *config.AuthConfig) *authSvc.Servi
 	return svc
 }
 
+func setupEvents( //nolint:ireturn
+	ctx context.Context,
+	e *echo.Echo,
+	db *sqlx.DB,
+	authSvc *authSvc.Service,
+	logger *slog.Logger,
+	cfg *config.AppConfig,
+) events.Publisher {
+	hub := events.NewHub(cfg.EventsConfig.ReplayBufferSize)
+	hndl := marketHndl.NewEventsHandler(hub, cfg.EventsConfig.HeartbeatInterval)
+	marketRoutes.RegisterEventsRoutes(e, hndl, authSvc)
+
+	switch cfg.EventsConfig.Backend {
+	case eventsBackendMemory, "":
+		return hub
+	case eventsBackendPG:
+		publisher := pgEvents.NewPostgresPublisher(db, cfg.DBConfig.URI, hub, logger)
+
+		go func() {
+			err := publisher.Listen(ctx)
+			if err != nil {
+				logger.Error("events listener stopped", "error", err)
+			}
+		}()
+
+		return publisher
+	default:
+		err := fmt.Errorf("%w: %s", errUnknownEventsBackend, cfg.EventsConfig.Backend)
+		log.Panic("failed to create events publisher: %w", err)
+
+		return nil
+	}
+}
+
 func setupListings( //nolint:ireturn
 	e *echo.Echo,
 	db *sqlx.DB,
 	authSvc *authSvc.Service,
-	cfg *config.StorageConfig,
-) marketRepos.ListingsRepo {
+	notifier notifications.Notifier,
+	paymentProvider paymentproviders.PaymentProvider,
+	cfg *config.AppConfig,
+) (marketRepos.ListingsRepo, *marketSvc.ListingsService) {
 	repo := marketRepos.NewListingsRepo(db)
-	storage := localStorage.NewLocalStorage(cfg.UploadDir)
-	svc := marketSvc.NewListingsService(repo, storage, cfg)
+	processor := imaging.NewProcessor(imaging.Options{
+		MaxBytes:           cfg.StorageConfig.MaxImageFileSize,
+		MinDimension:       cfg.StorageConfig.MinImageDimension,
+		MaxSourceDimension: cfg.StorageConfig.MaxSourceDimension,
+		MaxDimension:       cfg.StorageConfig.MaxImageDimension,
+		JPEGQuality:        cfg.StorageConfig.JPEGQuality,
+		Variants: []imaging.Variant{
+			{Name: "thumb", MaxSize: cfg.StorageConfig.ThumbSize},
+			{Name: "medium", MaxSize: cfg.StorageConfig.MediumSize},
+			{Name: "large", MaxSize: cfg.StorageConfig.LargeSize},
+		},
+	})
+
+	storage, err := newStorage(&cfg.StorageConfig, processor)
+	if err != nil {
+		log.Panic("failed to create storage: %w", err)
+	}
+
+	verifier, ok := storage.(marketStorage.SignedURLVerifier)
+	if ok {
+		marketRoutes.RegisterImagesRoutes(e, marketHndl.NewImagesHandler(verifier))
+	}
+
+	screener, err := newScreener(&cfg.ScreeningConfig, repo)
+	if err != nil {
+		log.Panic("failed to create listing screener: %w", err)
+	}
+
+	if cfg.ListingsConfig.DefaultDurationDays <= 0 {
+		log.Panic("failed to create listings service: %w", errInvalidListingDuration)
+	}
+
+	if cfg.ListingsConfig.ImportStaleAfter <= 0 {
+		log.Panic("failed to create listings service: %w", errInvalidImportStaleAfter)
+	}
+
+	svc := marketSvc.NewListingsService(
+		repo,
+		storage,
+		notifier,
+		screener,
+		paymentProvider,
+		&cfg.StorageConfig,
+		&cfg.ListingsConfig,
+	)
 	hndl := marketHndl.NewListingsHandler(svc)
 	marketRoutes.RegisterListingsRoutes(e, hndl, authSvc)
 
-	return repo
+	return repo, svc
+}
+
+func newStorage( //nolint:ireturn
+	cfg *config.StorageConfig,
+	processor *imaging.Processor,
+) (marketStorage.Storage, error) {
+	if cfg.URLTTL <= 0 {
+		return nil, fmt.Errorf("%w: %s", errInvalidImageURLTTL, cfg.URLTTL)
+	}
+
+	switch cfg.Backend {
+	case storageBackendLocal, "":
+		return localStorage.NewLocalStorage(
+			cfg.UploadDir,
+			marketRoutes.ImagesBasePath,
+			cfg.URLSigningSecret,
+			processor,
+		), nil
+	case storageBackendS3:
+		s3, err := s3Storage.NewS3Storage(&cfg.S3Config, processor)
+		if err != nil {
+			return nil, fmt.Errorf("creating s3 storage: %w", err)
+		}
+
+		return s3, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownStorageBackend, cfg.Backend)
+	}
+}
+
+// newScreener builds the chain of listing screeners enabled in config.
+func newScreener(
+	cfg *config.ScreeningConfig,
+	repo marketRepos.ListingsRepo,
+) (screening.Chain, error) {
+	var chain screening.Chain
+
+	if len(cfg.BannedWords) > 0 || len(cfg.BannedPatterns) > 0 {
+		verdict := dto.ScreeningVerdict(cfg.BannedWordsVerdict)
+		if verdict == "" {
+			verdict = dto.ScreeningVerdictBlock
+		}
+
+		words, err := screening.NewWordsScreener(cfg.BannedWords, cfg.BannedPatterns, verdict)
+		if err != nil {
+			return nil, fmt.Errorf("creating banned words screener: %w", err)
+		}
+
+		chain = append(chain, words)
+	}
+
+	if cfg.PriceMinRatio > 0 {
+		chain = append(chain,
+			screening.NewPriceAnomalyScreener(repo, cfg.PriceMinRatio, cfg.PriceMinSamples),
+		)
+	}
+
+	if cfg.DuplicateSimilarity > 0 {
+		chain = append(chain, screening.NewDuplicatesScreener(repo, cfg.DuplicateSimilarity))
+	}
+
+	return chain, nil
+}
+
+func startListingsJobs(
+	ctx context.Context,
+	logger *slog.Logger,
+	svc *marketSvc.ListingsService,
+	cfg *config.AppConfig,
+) {
+	go jobs.RunPeriodically(
+		ctx,
+		logger,
+		"notify_expiring_listings",
+		cfg.ListingsConfig.ExpiryCheckInterval,
+		svc.NotifyExpiringListings,
+	)
+	go jobs.RunPeriodically(
+		ctx,
+		logger,
+		"expire_listings",
+		cfg.ListingsConfig.ExpiryCheckInterval,
+		svc.ExpireListings,
+	)
+	go jobs.RunPeriodically(
+		ctx,
+		logger,
+		"collect_orphaned_images",
+		cfg.StorageConfig.OrphanCheckInterval,
+		svc.CollectOrphanedImages,
+	)
+	go jobs.RunPeriodically(
+		ctx,
+		logger,
+		"match_saved_searches",
+		cfg.ListingsConfig.SavedSearchMatchInterval,
+		svc.MatchSavedSearches,
+	)
+	go jobs.RunPeriodically(
+		ctx,
+		logger,
+		"send_saved_search_digests",
+		cfg.ListingsConfig.SavedSearchDigestInterval,
+		svc.SendSavedSearchDigests,
+	)
+	go jobs.RunPeriodically(
+		ctx,
+		logger,
+		"process_listing_imports",
+		cfg.ListingsConfig.ImportCheckInterval,
+		svc.ProcessImports,
+	)
 }
 
 func setupPayments(
@@ -97,14 +351,88 @@ func setupPayments(
 	db *sqlx.DB,
 	authSvc *authSvc.Service,
 	listingsRepo marketRepos.ListingsRepo,
+	paymentProvider paymentproviders.PaymentProvider,
+	notifier notifications.Notifier,
+	publisher events.Publisher,
 	cfg *config.PaymentsConfig,
 ) {
 	repo := marketRepos.NewPaymentsRepo(db)
-	paymentProvider := paymentproviders.NewStripePaymentProvider(
-		cfg.StripeSecretKey,
-		cfg.StripeWebhookSecret,
+	svc := marketSvc.NewPaymentsService(
+		paymentProvider,
+		repo,
+		listingsRepo,
+		notifier,
+		publisher,
 	)
-	svc := marketSvc.NewPaymentsService(paymentProvider, repo, listingsRepo)
 	hndl := marketHndl.NewPaymentsHandler(svc)
 	marketRoutes.RegisterPaymentsRoutes(e, hndl, authSvc)
+
+	reviewsSvc := marketSvc.NewReviewsService(marketRepos.NewReviewsRepo(db), cfg.ReviewWindow)
+	marketRoutes.RegisterReviewsRoutes(e, marketHndl.NewReviewsHandler(reviewsSvc), authSvc)
+}
+
+func setupMessages(
+	e *echo.Echo,
+	db *sqlx.DB,
+	authSvc *authSvc.Service,
+	listingsRepo marketRepos.ListingsRepo,
+	notifier notifications.Notifier,
+	publisher events.Publisher,
+) {
+	repo := marketRepos.NewMessagesRepo(db)
+	svc := marketSvc.NewMessagesService(repo, listingsRepo, notifier, publisher)
+	hndl := marketHndl.NewMessagesHandler(svc)
+	marketRoutes.RegisterMessagesRoutes(e, hndl, authSvc)
+}
+
+func setupModeration(
+	e *echo.Echo,
+	db *sqlx.DB,
+	authSvc *authSvc.Service,
+	listingsRepo marketRepos.ListingsRepo,
+	listingsSvc *marketSvc.ListingsService,
+	notifier notifications.Notifier,
+) {
+	repo := marketRepos.NewModerationRepo(db)
+	svc := marketSvc.NewModerationService(repo, listingsRepo, listingsSvc, notifier)
+	hndl := marketHndl.NewModerationHandler(svc)
+	marketRoutes.RegisterModerationRoutes(e, hndl, authSvc)
+}
+
+func setupShipments(
+	e *echo.Echo,
+	db *sqlx.DB,
+	authSvc *authSvc.Service,
+	notifier notifications.Notifier,
+	publisher events.Publisher,
+	cfg *config.ShipmentsConfig,
+) *marketSvc.ShipmentsService {
+	provider, err := newTrackingProvider(cfg)
+	if err != nil {
+		log.Panic("failed to create tracking provider: %w", err)
+	}
+
+	repo := marketRepos.NewShipmentsRepo(db)
+	svc := marketSvc.NewShipmentsService(repo, provider, notifier, publisher)
+	hndl := marketHndl.NewShipmentsHandler(svc)
+	marketRoutes.RegisterShipmentsRoutes(e, hndl, authSvc)
+
+	return svc
+}
+
+func newTrackingProvider( //nolint:ireturn
+	cfg *config.ShipmentsConfig,
+) (tracking.TrackingProvider, error) {
+	switch cfg.TrackingProvider {
+	case trackingProviderFake:
+		if cfg.FakeDeliveryDelay <= 0 {
+			return nil, errInvalidDeliveryDelay
+		}
+
+		return fakeTracking.NewFakeTrackingProvider(cfg.FakeDeliveryDelay), nil
+	case "":
+		return nil, errMissingTrackingProvider
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownTrackingProvider, cfg.TrackingProvider)
+	}
 }
@@ -1,13 +1,19 @@
 // Package config holds the application's configuration settings.
 package config
 
+import "time"
+
 // AppConfig defines environment-based configuration for the application.
 type AppConfig struct {
-	APIConfig      APIConfig
-	DBConfig       DBConfig
-	AuthConfig     AuthConfig
-	StorageConfig  StorageConfig
-	PaymentsConfig PaymentsConfig
+	APIConfig       APIConfig
+	DBConfig        DBConfig
+	AuthConfig      AuthConfig
+	StorageConfig   StorageConfig
+	ListingsConfig  ListingsConfig
+	ScreeningConfig ScreeningConfig
+	PaymentsConfig  PaymentsConfig
+	ShipmentsConfig ShipmentsConfig
+	EventsConfig    EventsConfig
 }
 
 // DBConfig holds settings for database.
@@ -54,12 +60,91 @@ type Google struct {
 
 // StorageConfig holds settings for storage.
 type StorageConfig struct {
-	UploadDir           string `env:"MARKET_IMAGE_UPLOAD_DIR"`
-	MaxImagesPerListing int    `env:"MARKET_MAX_IMAGES_PER_LISTING"`
+	Backend             string        `env:"MARKET_STORAGE_BACKEND"`
+	UploadDir           string        `env:"MARKET_IMAGE_UPLOAD_DIR"`
+	URLSigningSecret    string        `env:"MARKET_IMAGE_URL_SIGNING_SECRET"`
+	URLTTL              time.Duration `env:"MARKET_IMAGE_URL_TTL"`
+	OrphanGracePeriod   time.Duration `env:"MARKET_IMAGE_ORPHAN_GRACE_PERIOD"`
+	OrphanCheckInterval time.Duration `env:"MARKET_IMAGE_ORPHAN_CHECK_INTERVAL"`
+	MaxImagesPerListing int           `env:"MARKET_MAX_IMAGES_PER_LISTING"`
+	MaxImageFileSize    int64         `env:"MARKET_IMAGE_MAX_FILE_SIZE"`
+	MinImageDimension   int           `env:"MARKET_IMAGE_MIN_DIMENSION"`
+	MaxSourceDimension  int           `env:"MARKET_IMAGE_MAX_SOURCE_DIMENSION"`
+	MaxImageDimension   int           `env:"MARKET_IMAGE_MAX_DIMENSION"`
+	JPEGQuality         int           `env:"MARKET_IMAGE_JPEG_QUALITY"`
+	ThumbSize           int           `env:"MARKET_IMAGE_THUMB_SIZE"`
+	MediumSize          int           `env:"MARKET_IMAGE_MEDIUM_SIZE"`
+	LargeSize           int           `env:"MARKET_IMAGE_LARGE_SIZE"`
+	S3Config            S3Config
+}
+
+// S3Config holds settings for S3-compatible object storage.
+type S3Config struct {
+	Endpoint        string `env:"MARKET_S3_ENDPOINT"`
+	Region          string `env:"MARKET_S3_REGION"`
+	Bucket          string `env:"MARKET_S3_BUCKET"`
+	Prefix          string `env:"MARKET_S3_PREFIX"`
+	AccessKeyID     string `env:"MARKET_S3_ACCESS_KEY_ID"`
+	SecretAccessKey string `env:"MARKET_S3_SECRET_ACCESS_KEY"`
+	UseSSL          bool   `env:"MARKET_S3_USE_SSL"`
+	UsePathStyle    bool   `env:"MARKET_S3_USE_PATH_STYLE"`
 }
 
-// PaymentsConfig holds settings for payments.
+// ListingsConfig holds settings for listings lifecycle, categories and moderation.
+// With ReviewNewSellers, listings of sellers with less than TrustedSellerMinSales
+// completed sales wait for moderator approval. Running imports without progress for
+// ImportStaleAfter are resumed by another worker.
+type ListingsConfig struct {
+	DefaultDurationDays       int           `env:"MARKET_LISTING_DEFAULT_DURATION_DAYS"`
+	ExpiryNotifyBefore        time.Duration `env:"MARKET_LISTING_EXPIRY_NOTIFY_BEFORE"`
+	ExpiryCheckInterval       time.Duration `env:"MARKET_LISTING_EXPIRY_CHECK_INTERVAL"`
+	CategoryMaxDepth          int           `env:"MARKET_CATEGORY_MAX_DEPTH"`
+	PriceHistogramBuckets     int           `env:"MARKET_LISTING_PRICE_HISTOGRAM_BUCKETS"`
+	SearchCacheTTL            time.Duration `env:"MARKET_LISTING_SEARCH_CACHE_TTL"`
+	SearchCacheSize           int           `env:"MARKET_LISTING_SEARCH_CACHE_SIZE"`
+	SavedSearchMatchInterval  time.Duration `env:"MARKET_SAVED_SEARCH_MATCH_INTERVAL"`
+	SavedSearchDigestInterval time.Duration `env:"MARKET_SAVED_SEARCH_DIGEST_INTERVAL"`
+	MaxSavedSearchesPerUser   int           `env:"MARKET_SAVED_SEARCH_MAX_PER_USER"`
+	ReviewNewSellers          bool          `env:"MARKET_MODERATION_REVIEW_NEW_SELLERS"`
+	TrustedSellerMinSales     int           `env:"MARKET_MODERATION_TRUSTED_SELLER_MIN_SALES"`
+	ImportMaxRows             int           `env:"MARKET_LISTING_IMPORT_MAX_ROWS"`
+	ImportCheckInterval       time.Duration `env:"MARKET_LISTING_IMPORT_CHECK_INTERVAL"`
+	ImportStaleAfter          time.Duration `env:"MARKET_LISTING_IMPORT_STALE_AFTER"`
+}
+
+// ScreeningConfig holds settings for automated screening of new and updated listings.
+// Listings with BannedWords or matching BannedPatterns get BannedWordsVerdict, flag or block.
+// Listings priced below PriceMinRatio of the category median, once the category has
+// PriceMinSamples listings, and listings with DuplicateSimilarity to another listing of
+// the seller are flagged. Empty or zero settings disable the check.
+type ScreeningConfig struct {
+	BannedWords         []string `env:"MARKET_SCREENING_BANNED_WORDS"          env-separator:","`
+	BannedPatterns      []string `env:"MARKET_SCREENING_BANNED_PATTERNS"       env-separator:";"`
+	BannedWordsVerdict  string   `env:"MARKET_SCREENING_BANNED_WORDS_VERDICT"`
+	PriceMinRatio       float64  `env:"MARKET_SCREENING_PRICE_MIN_RATIO"`
+	PriceMinSamples     int      `env:"MARKET_SCREENING_PRICE_MIN_SAMPLES"`
+	DuplicateSimilarity float64  `env:"MARKET_SCREENING_DUPLICATE_SIMILARITY"`
+}
+
+// PaymentsConfig holds settings for payments and post-purchase reviews.
 type PaymentsConfig struct {
-	StripeSecretKey     string `env:"STRIPE_SECRET_KEY"`
-	StripeWebhookSecret string `env:"STRIPE_WEBHOOK_SECRET"`
+	StripeSecretKey     string        `env:"STRIPE_SECRET_KEY"`
+	StripeWebhookSecret string        `env:"STRIPE_WEBHOOK_SECRET"`
+	ReviewWindow        time.Duration `env:"MARKET_REVIEW_WINDOW"`
+}
+
+// ShipmentsConfig holds settings for tracking shipments of sold items. The tracking provider
+// has no default, the fake one reports every shipment as delivered FakeDeliveryDelay after
+// it was shipped, so it has to be chosen explicitly.
+type ShipmentsConfig struct {
+	TrackingProvider  string        `env:"MARKET_TRACKING_PROVIDER"`
+	CheckInterval     time.Duration `env:"MARKET_TRACKING_CHECK_INTERVAL"`
+	FakeDeliveryDelay time.Duration `env:"MARKET_TRACKING_FAKE_DELIVERY_DELAY"`
+}
+
+// EventsConfig holds settings for real-time events.
+type EventsConfig struct {
+	Backend           string        `env:"MARKET_EVENTS_BACKEND"`
+	ReplayBufferSize  int           `env:"MARKET_EVENTS_REPLAY_BUFFER_SIZE"`
+	HeartbeatInterval time.Duration `env:"MARKET_EVENTS_HEARTBEAT_INTERVAL"`
 }
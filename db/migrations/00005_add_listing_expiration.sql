@@ -0,0 +1,30 @@
+-- +goose NO TRANSACTION
+
+-- +goose Up
+ALTER TYPE listings.listing_status ADD VALUE IF NOT EXISTS 'expired';
+
+-- +goose StatementBegin
+ALTER TABLE listings.categories
+    ADD COLUMN IF NOT EXISTS listing_duration_days INTEGER;
+
+ALTER TABLE listings.listings
+    ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS expiry_notified_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS listings_status_expires_at_idx
+    ON listings.listings (status, expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS listings.listings_status_expires_at_idx;
+
+ALTER TABLE listings.listings
+    DROP COLUMN IF EXISTS expiry_notified_at,
+    DROP COLUMN IF EXISTS expires_at;
+
+ALTER TABLE listings.categories
+    DROP COLUMN IF EXISTS listing_duration_days;
+
+UPDATE listings.listings SET status = 'canceled' WHERE status = 'expired';
+-- +goose StatementEnd
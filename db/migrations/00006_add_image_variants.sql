@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE listings.listings_images
+    ADD COLUMN IF NOT EXISTS variants JSONB NOT NULL DEFAULT '{}';
+
+ALTER TABLE listings.categories
+    ADD COLUMN IF NOT EXISTS image_variants JSONB NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE listings.categories
+    DROP COLUMN IF EXISTS image_variants;
+
+ALTER TABLE listings.listings_images
+    DROP COLUMN IF EXISTS variants;
+-- +goose StatementEnd
@@ -0,0 +1,92 @@
+-- +goose Up
+-- Image paths used to include the local upload directory (MARKET_IMAGE_UPLOAD_DIR),
+-- they are now storage keys relative to the storage root. Every path was written as
+-- <upload dir>/<folder>/<file>, so the key is its last two segments whatever directory
+-- was configured. Rows that don't have that shape abort the migration instead of being
+-- left pointing at objects that don't exist.
+-- +goose StatementBegin
+DO $$
+DECLARE
+    bad_path TEXT;
+BEGIN
+    SELECT path INTO bad_path
+    FROM (
+        SELECT path FROM listings.listings_images
+        UNION ALL
+        SELECT v->>'path' FROM listings.listings_images, jsonb_each(variants) AS e(name, v)
+        UNION ALL
+        SELECT image_path FROM listings.categories
+        UNION ALL
+        SELECT v->>'path' FROM listings.categories, jsonb_each(image_variants) AS e(name, v)
+    ) AS paths
+    WHERE path IS NULL OR path !~ '^(.*/)?[^/]+/[^/]+$'
+    LIMIT 1;
+
+    IF FOUND THEN
+        RAISE EXCEPTION 'image path % is not <upload dir>/<folder>/<file>, fix it before migrating',
+            coalesce(bad_path, 'NULL');
+    END IF;
+END
+$$;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+UPDATE listings.listings_images
+SET
+    path = regexp_replace(path, '^.*/([^/]+/[^/]+)$', '\1'),
+    variants = (
+        SELECT coalesce(jsonb_object_agg(
+            name,
+            v || jsonb_build_object('path', regexp_replace(v->>'path', '^.*/([^/]+/[^/]+)$', '\1'))
+        ), '{}')
+        FROM jsonb_each(variants) AS e(name, v)
+    );
+
+UPDATE listings.categories
+SET
+    image_path = regexp_replace(image_path, '^.*/([^/]+/[^/]+)$', '\1'),
+    image_variants = (
+        SELECT coalesce(jsonb_object_agg(
+            name,
+            v || jsonb_build_object('path', regexp_replace(v->>'path', '^.*/([^/]+/[^/]+)$', '\1'))
+        ), '{}')
+        FROM jsonb_each(image_variants) AS e(name, v)
+    );
+-- +goose StatementEnd
+
+-- +goose Down
+-- The upload directory isn't known to the database, it is read from the
+-- market.image_upload_dir setting (e.g. PGOPTIONS='-c market.image_upload_dir=/srv/images')
+-- and defaults to the directory from .env.example.
+-- +goose StatementBegin
+DO $$
+DECLARE
+    upload_dir TEXT := rtrim(
+        coalesce(nullif(current_setting('market.image_upload_dir', true), ''), 'uploads/images'),
+        '/'
+    ) || '/';
+BEGIN
+    UPDATE listings.listings_images
+    SET
+        path = upload_dir || path,
+        variants = (
+            SELECT coalesce(jsonb_object_agg(
+                name,
+                v || jsonb_build_object('path', upload_dir || (v->>'path'))
+            ), '{}')
+            FROM jsonb_each(variants) AS e(name, v)
+        );
+
+    UPDATE listings.categories
+    SET
+        image_path = upload_dir || image_path,
+        image_variants = (
+            SELECT coalesce(jsonb_object_agg(
+                name,
+                v || jsonb_build_object('path', upload_dir || (v->>'path'))
+            ), '{}')
+            FROM jsonb_each(image_variants) AS e(name, v)
+        );
+END
+$$;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE listings.listings_images
+    ADD COLUMN IF NOT EXISTS position INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS alt_text VARCHAR(250);
+
+WITH ordered AS (
+    SELECT
+        id,
+        ROW_NUMBER() OVER (PARTITION BY listing_id ORDER BY created_at) - 1 AS position
+    FROM listings.listings_images
+)
+UPDATE listings.listings_images i
+SET
+    position = ordered.position,
+    is_primary = ordered.position = 0
+FROM ordered
+WHERE ordered.id = i.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS listings_images_one_primary_idx
+    ON listings.listings_images (listing_id)
+    WHERE is_primary;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS listings.listings_images_one_primary_idx;
+
+ALTER TABLE listings.listings_images
+    DROP COLUMN IF EXISTS alt_text,
+    DROP COLUMN IF EXISTS is_primary,
+    DROP COLUMN IF EXISTS position;
+-- +goose StatementEnd
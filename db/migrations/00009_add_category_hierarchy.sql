@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE listings.categories
+    ADD COLUMN IF NOT EXISTS parent_id VARCHAR(30)
+        REFERENCES listings.categories(id)
+        ON DELETE RESTRICT,
+    ADD COLUMN IF NOT EXISTS slug VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS display_order INTEGER NOT NULL DEFAULT 0;
+
+UPDATE listings.categories
+SET slug = trim(BOTH '-' FROM regexp_replace(lower(title), '[^a-z0-9]+', '-', 'g'))
+WHERE slug IS NULL;
+
+ALTER TABLE listings.categories
+    ALTER COLUMN slug SET NOT NULL,
+    ADD CONSTRAINT categories_slug_key UNIQUE (slug),
+    DROP CONSTRAINT IF EXISTS categories_title_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS categories_parent_title_idx
+    ON listings.categories (COALESCE(parent_id, ''), title)
+    WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS categories_parent_id_idx
+    ON listings.categories (parent_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS listings.categories_parent_id_idx;
+DROP INDEX IF EXISTS listings.categories_parent_title_idx;
+
+ALTER TABLE listings.categories
+    DROP CONSTRAINT IF EXISTS categories_slug_key,
+    ADD CONSTRAINT categories_title_key UNIQUE (title),
+    DROP COLUMN IF EXISTS display_order,
+    DROP COLUMN IF EXISTS slug,
+    DROP COLUMN IF EXISTS parent_id;
+-- +goose StatementEnd
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE listings.attribute_type AS ENUM ('enum', 'number', 'text', 'boolean');
+
+CREATE TABLE IF NOT EXISTS listings.category_attributes (
+    id VARCHAR(30) PRIMARY KEY,
+    category_id VARCHAR(30) NOT NULL
+        REFERENCES listings.categories(id)
+        ON DELETE CASCADE,
+    key VARCHAR(50) NOT NULL,
+    label VARCHAR(100) NOT NULL,
+    type listings.attribute_type NOT NULL,
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    unit VARCHAR(20),
+    options TEXT[] NOT NULL DEFAULT '{}',
+    display_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (category_id, key)
+);
+
+CREATE INDEX IF NOT EXISTS category_attributes_key_idx
+    ON listings.category_attributes (key);
+
+ALTER TABLE listings.listings
+    ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS listings_attributes_idx
+    ON listings.listings USING GIN (attributes);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS listings.listings_attributes_idx;
+
+ALTER TABLE listings.listings
+    DROP COLUMN IF EXISTS attributes;
+
+DROP TABLE IF EXISTS listings.category_attributes;
+DROP TYPE IF EXISTS listings.attribute_type;
+-- +goose StatementEnd
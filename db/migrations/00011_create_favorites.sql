@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS listings.favorites (
+    user_id VARCHAR(30) NOT NULL
+        REFERENCES auth.users(id)
+        ON DELETE CASCADE,
+    listing_id VARCHAR(30) NOT NULL
+        REFERENCES listings.listings(id)
+        ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, listing_id)
+);
+
+CREATE INDEX IF NOT EXISTS favorites_listing_id_idx
+    ON listings.favorites (listing_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS listings.favorites;
+-- +goose StatementEnd
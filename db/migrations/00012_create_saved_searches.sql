@@ -0,0 +1,56 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE listings.saved_search_frequency AS ENUM ('instant', 'daily');
+
+CREATE TABLE IF NOT EXISTS listings.saved_searches (
+    id VARCHAR(30) PRIMARY KEY,
+    user_id VARCHAR(30) NOT NULL
+        REFERENCES auth.users(id)
+        ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    filters JSONB NOT NULL DEFAULT '{}',
+    frequency listings.saved_search_frequency NOT NULL DEFAULT 'instant',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS saved_searches_user_id_idx
+    ON listings.saved_searches (user_id);
+
+CREATE TABLE IF NOT EXISTS listings.saved_search_matches (
+    saved_search_id VARCHAR(30) NOT NULL
+        REFERENCES listings.saved_searches(id)
+        ON DELETE CASCADE,
+    listing_id VARCHAR(30) NOT NULL
+        REFERENCES listings.listings(id)
+        ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    notified_at TIMESTAMPTZ,
+    PRIMARY KEY (saved_search_id, listing_id)
+);
+
+CREATE INDEX IF NOT EXISTS saved_search_matches_pending_idx
+    ON listings.saved_search_matches (saved_search_id)
+    WHERE notified_at IS NULL;
+
+ALTER TABLE listings.listings
+    ADD COLUMN IF NOT EXISTS search_matched_at TIMESTAMPTZ;
+
+UPDATE listings.listings SET search_matched_at = NOW();
+
+CREATE INDEX IF NOT EXISTS listings_search_unmatched_idx
+    ON listings.listings (created_at)
+    WHERE search_matched_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS listings.listings_search_unmatched_idx;
+
+ALTER TABLE listings.listings
+    DROP COLUMN IF EXISTS search_matched_at;
+
+DROP TABLE IF EXISTS listings.saved_search_matches;
+DROP TABLE IF EXISTS listings.saved_searches;
+DROP TYPE IF EXISTS listings.saved_search_frequency;
+-- +goose StatementEnd
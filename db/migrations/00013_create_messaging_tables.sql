@@ -0,0 +1,77 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE SCHEMA IF NOT EXISTS messaging;
+
+CREATE TABLE IF NOT EXISTS messaging.threads (
+    id VARCHAR(30) PRIMARY KEY,
+    listing_id VARCHAR(30) NOT NULL
+        REFERENCES listings.listings(id)
+        ON DELETE CASCADE,
+    buyer_id VARCHAR(30) NOT NULL
+        REFERENCES auth.users(id)
+        ON DELETE CASCADE,
+    seller_id VARCHAR(30) NOT NULL
+        REFERENCES auth.users(id)
+        ON DELETE CASCADE,
+    buyer_last_read_at TIMESTAMPTZ,
+    seller_last_read_at TIMESTAMPTZ,
+    last_message_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (listing_id, buyer_id)
+);
+
+CREATE INDEX IF NOT EXISTS threads_buyer_id_idx ON messaging.threads (buyer_id);
+CREATE INDEX IF NOT EXISTS threads_seller_id_idx ON messaging.threads (seller_id);
+
+CREATE TABLE IF NOT EXISTS messaging.messages (
+    id VARCHAR(30) PRIMARY KEY,
+    thread_id VARCHAR(30) NOT NULL
+        REFERENCES messaging.threads(id)
+        ON DELETE CASCADE,
+    sender_id VARCHAR(30) NOT NULL
+        REFERENCES auth.users(id)
+        ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS messages_thread_id_created_at_idx
+    ON messaging.messages (thread_id, created_at);
+
+CREATE TABLE IF NOT EXISTS messaging.blocks (
+    blocker_id VARCHAR(30) NOT NULL
+        REFERENCES auth.users(id)
+        ON DELETE CASCADE,
+    blocked_id VARCHAR(30) NOT NULL
+        REFERENCES auth.users(id)
+        ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (blocker_id, blocked_id)
+);
+
+CREATE TABLE IF NOT EXISTS messaging.abuse_reports (
+    id VARCHAR(30) PRIMARY KEY,
+    thread_id VARCHAR(30) NOT NULL
+        REFERENCES messaging.threads(id)
+        ON DELETE CASCADE,
+    message_id VARCHAR(30)
+        REFERENCES messaging.messages(id)
+        ON DELETE SET NULL,
+    reporter_id VARCHAR(30) NOT NULL
+        REFERENCES auth.users(id)
+        ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMPTZ
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS messaging.abuse_reports;
+DROP TABLE IF EXISTS messaging.blocks;
+DROP TABLE IF EXISTS messaging.messages;
+DROP TABLE IF EXISTS messaging.threads;
+DROP SCHEMA IF EXISTS messaging;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE payments.reviewer_role AS ENUM ('buyer', 'seller');
+
+CREATE TABLE IF NOT EXISTS payments.reviews (
+    id VARCHAR(30) PRIMARY KEY,
+    payment_id VARCHAR(30) NOT NULL
+        REFERENCES payments.payments(id)
+        ON DELETE CASCADE,
+    reviewer_id VARCHAR(30) NOT NULL
+        REFERENCES auth.users(id)
+        ON DELETE CASCADE,
+    reviewee_id VARCHAR(30) NOT NULL
+        REFERENCES auth.users(id)
+        ON DELETE CASCADE,
+    reviewer_role payments.reviewer_role NOT NULL,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    body TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (payment_id, reviewer_id)
+);
+
+CREATE INDEX IF NOT EXISTS reviews_reviewee_id_idx
+    ON payments.reviews (reviewee_id, reviewer_role, created_at);
+
+CREATE INDEX IF NOT EXISTS payments_listing_id_idx ON payments.payments (listing_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS payments.payments_listing_id_idx;
+DROP TABLE IF EXISTS payments.reviews;
+DROP TYPE IF EXISTS payments.reviewer_role;
+-- +goose StatementEnd
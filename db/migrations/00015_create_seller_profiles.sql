@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS listings.seller_profiles (
+    user_id VARCHAR(30) PRIMARY KEY
+        REFERENCES auth.users(id)
+        ON DELETE CASCADE,
+    bio TEXT NOT NULL DEFAULT '',
+    avatar_path TEXT,
+    avatar_variants JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS users_lower_username_idx ON auth.users (LOWER(username));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS auth.users_lower_username_idx;
+DROP TABLE IF EXISTS listings.seller_profiles;
+-- +goose StatementEnd
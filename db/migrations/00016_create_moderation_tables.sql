@@ -0,0 +1,105 @@
+-- +goose NO TRANSACTION
+
+-- +goose Up
+ALTER TYPE listings.listing_status ADD VALUE IF NOT EXISTS 'pending_review';
+ALTER TYPE listings.listing_status ADD VALUE IF NOT EXISTS 'hidden';
+ALTER TYPE listings.listing_status ADD VALUE IF NOT EXISTS 'removed';
+
+-- +goose StatementBegin
+CREATE SCHEMA IF NOT EXISTS moderation;
+
+CREATE TYPE moderation.report_target AS ENUM ('listing', 'user');
+
+CREATE TYPE moderation.report_reason AS ENUM (
+    'spam',
+    'prohibited_item',
+    'counterfeit',
+    'fraud',
+    'offensive',
+    'other'
+);
+
+CREATE TYPE moderation.report_status AS ENUM ('open', 'resolved', 'dismissed');
+
+CREATE TYPE moderation.action_type AS ENUM (
+    'approve_listing',
+    'hide_listing',
+    'restore_listing',
+    'remove_listing',
+    'warn_user',
+    'suspend_user',
+    'unsuspend_user',
+    'dismiss_report'
+);
+
+CREATE TABLE IF NOT EXISTS moderation.reports (
+    id VARCHAR(30) PRIMARY KEY,
+    target_type moderation.report_target NOT NULL,
+    listing_id VARCHAR(30)
+        REFERENCES listings.listings(id)
+        ON DELETE CASCADE,
+    user_id VARCHAR(30) NOT NULL
+        REFERENCES auth.users(id)
+        ON DELETE CASCADE,
+    reporter_id VARCHAR(30) NOT NULL
+        REFERENCES auth.users(id)
+        ON DELETE CASCADE,
+    reason moderation.report_reason NOT NULL,
+    details TEXT NOT NULL DEFAULT '',
+    status moderation.report_status NOT NULL DEFAULT 'open',
+    resolved_by VARCHAR(30)
+        REFERENCES auth.users(id)
+        ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS reports_status_created_at_idx
+    ON moderation.reports (status, created_at);
+
+CREATE TABLE IF NOT EXISTS moderation.actions (
+    id VARCHAR(30) PRIMARY KEY,
+    admin_id VARCHAR(30) NOT NULL
+        REFERENCES auth.users(id),
+    action moderation.action_type NOT NULL,
+    report_id VARCHAR(30)
+        REFERENCES moderation.reports(id)
+        ON DELETE SET NULL,
+    listing_id VARCHAR(30)
+        REFERENCES listings.listings(id)
+        ON DELETE SET NULL,
+    user_id VARCHAR(30)
+        REFERENCES auth.users(id)
+        ON DELETE SET NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS actions_listing_id_idx ON moderation.actions (listing_id);
+CREATE INDEX IF NOT EXISTS actions_user_id_idx ON moderation.actions (user_id);
+
+CREATE TABLE IF NOT EXISTS moderation.suspensions (
+    user_id VARCHAR(30) PRIMARY KEY
+        REFERENCES auth.users(id)
+        ON DELETE CASCADE,
+    suspended_by VARCHAR(30)
+        REFERENCES auth.users(id)
+        ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS moderation.suspensions;
+DROP TABLE IF EXISTS moderation.actions;
+DROP TABLE IF EXISTS moderation.reports;
+DROP TYPE IF EXISTS moderation.action_type;
+DROP TYPE IF EXISTS moderation.report_status;
+DROP TYPE IF EXISTS moderation.report_reason;
+DROP TYPE IF EXISTS moderation.report_target;
+DROP SCHEMA IF EXISTS moderation;
+
+UPDATE listings.listings SET status = 'canceled'
+WHERE status IN ('pending_review', 'hidden', 'removed');
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE moderation.reports
+    ALTER COLUMN reporter_id DROP NOT NULL;
+
+CREATE INDEX IF NOT EXISTS reports_listing_id_idx
+    ON moderation.reports (listing_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS moderation.reports_listing_id_idx;
+
+DELETE FROM moderation.reports WHERE reporter_id IS NULL;
+
+ALTER TABLE moderation.reports
+    ALTER COLUMN reporter_id SET NOT NULL;
+-- +goose StatementEnd
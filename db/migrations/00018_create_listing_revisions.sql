@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE listings.revision_actor_role AS ENUM ('owner', 'admin');
+
+CREATE TABLE IF NOT EXISTS listings.listing_revisions (
+    id VARCHAR(30) PRIMARY KEY,
+    listing_id VARCHAR(30) NOT NULL
+        REFERENCES listings.listings(id)
+        ON DELETE CASCADE,
+    actor_id VARCHAR(30)
+        REFERENCES auth.users(id)
+        ON DELETE SET NULL,
+    actor_role listings.revision_actor_role NOT NULL,
+    changes JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS listing_revisions_listing_id_created_at_idx
+    ON listings.listing_revisions (listing_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS listings.listing_revisions;
+DROP TYPE IF EXISTS listings.revision_actor_role;
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE listings.import_format AS ENUM ('csv', 'ndjson');
+
+CREATE TYPE listings.import_status AS ENUM ('pending', 'running', 'completed', 'failed');
+
+CREATE TABLE IF NOT EXISTS listings.imports (
+    id VARCHAR(30) PRIMARY KEY,
+    user_id VARCHAR(30) NOT NULL
+        REFERENCES auth.users(id)
+        ON DELETE CASCADE,
+    format listings.import_format NOT NULL,
+    status listings.import_status NOT NULL DEFAULT 'pending',
+    source BYTEA,
+    images BYTEA,
+    total_rows INT NOT NULL DEFAULT 0,
+    created_count INT NOT NULL DEFAULT 0,
+    failed_count INT NOT NULL DEFAULT 0,
+    results JSONB NOT NULL DEFAULT '[]',
+    error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS imports_user_id_idx ON listings.imports (user_id);
+
+CREATE INDEX IF NOT EXISTS imports_pending_idx
+    ON listings.imports (created_at)
+    WHERE status = 'pending';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS listings.imports;
+DROP TYPE IF EXISTS listings.import_status;
+DROP TYPE IF EXISTS listings.import_format;
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE listings.shipping_method AS ENUM ('flat_rate', 'free', 'local_pickup');
+
+CREATE TABLE IF NOT EXISTS listings.shipping_profiles (
+    id VARCHAR(30) PRIMARY KEY,
+    user_id VARCHAR(30) NOT NULL
+        REFERENCES auth.users(id)
+        ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    options JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS shipping_profiles_user_id_idx
+    ON listings.shipping_profiles (user_id);
+
+ALTER TABLE listings.listings
+    ADD COLUMN shipping_profile_id VARCHAR(30)
+        REFERENCES listings.shipping_profiles(id)
+        ON DELETE SET NULL;
+
+ALTER TABLE payments.payments
+    ADD COLUMN shipping_option_id VARCHAR(30),
+    ADD COLUMN shipping_method listings.shipping_method,
+    ADD COLUMN shipping_amount_in_cents INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN shipping_address JSONB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE payments.payments
+    DROP COLUMN IF EXISTS shipping_address,
+    DROP COLUMN IF EXISTS shipping_amount_in_cents,
+    DROP COLUMN IF EXISTS shipping_method,
+    DROP COLUMN IF EXISTS shipping_option_id;
+
+ALTER TABLE listings.listings DROP COLUMN IF EXISTS shipping_profile_id;
+
+DROP TABLE IF EXISTS listings.shipping_profiles;
+DROP TYPE IF EXISTS listings.shipping_method;
+-- +goose StatementEnd
@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE payments.shipment_status AS ENUM (
+    'shipped', 'in_transit', 'out_for_delivery', 'delivered', 'exception'
+);
+
+CREATE TABLE IF NOT EXISTS payments.shipments (
+    id VARCHAR(30) PRIMARY KEY,
+    payment_id VARCHAR(30) NOT NULL UNIQUE
+        REFERENCES payments.payments(id)
+        ON DELETE CASCADE,
+    carrier VARCHAR(50) NOT NULL,
+    tracking_number VARCHAR(100) NOT NULL,
+    status payments.shipment_status NOT NULL DEFAULT 'shipped',
+    shipped_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMPTZ,
+    checked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS shipments_undelivered_idx
+    ON payments.shipments (checked_at NULLS FIRST)
+    WHERE status <> 'delivered';
+
+CREATE TABLE IF NOT EXISTS payments.shipment_events (
+    id VARCHAR(30) PRIMARY KEY,
+    shipment_id VARCHAR(30) NOT NULL
+        REFERENCES payments.shipments(id)
+        ON DELETE CASCADE,
+    status payments.shipment_status NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    location TEXT NOT NULL DEFAULT '',
+    occurred_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (shipment_id, status, occurred_at)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS payments.shipment_events;
+DROP TABLE IF EXISTS payments.shipments;
+DROP TYPE IF EXISTS payments.shipment_status;
+-- +goose StatementEnd
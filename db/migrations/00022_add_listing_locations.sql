@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE listings.listings
+    ADD COLUMN latitude DOUBLE PRECISION,
+    ADD COLUMN longitude DOUBLE PRECISION,
+    ADD COLUMN location_city VARCHAR(100),
+    ADD CONSTRAINT listings_location_check CHECK ((latitude IS NULL) = (longitude IS NULL));
+
+CREATE INDEX IF NOT EXISTS listings_latitude_idx
+    ON listings.listings (latitude)
+    WHERE latitude IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS listings.listings_latitude_idx;
+
+ALTER TABLE listings.listings
+    DROP CONSTRAINT IF EXISTS listings_location_check,
+    DROP COLUMN IF EXISTS location_city,
+    DROP COLUMN IF EXISTS longitude,
+    DROP COLUMN IF EXISTS latitude;
+-- +goose StatementEnd
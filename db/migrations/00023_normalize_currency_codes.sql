@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+UPDATE listings.listings SET currency = UPPER(currency) WHERE currency <> UPPER(currency);
+UPDATE payments.payments SET currency = UPPER(currency) WHERE currency <> UPPER(currency);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- original letter case of currency codes is not kept, upper case codes stay valid
+SELECT 1;
+-- +goose StatementEnd
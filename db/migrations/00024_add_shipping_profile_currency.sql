@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE listings.shipping_profiles ADD COLUMN currency VARCHAR(3);
+
+-- profiles get the currency most of their listings are priced in, unused profiles the
+-- currency of the latest listing of the seller
+UPDATE listings.shipping_profiles sp
+SET currency = (
+    SELECT l.currency FROM listings.listings l
+    WHERE l.shipping_profile_id = sp.id
+    GROUP BY l.currency
+    ORDER BY COUNT(*) DESC, l.currency
+    LIMIT 1
+);
+
+UPDATE listings.shipping_profiles sp
+SET currency = COALESCE(
+    (
+        SELECT l.currency FROM listings.listings l
+        WHERE l.user_id = sp.user_id
+        ORDER BY l.created_at DESC
+        LIMIT 1
+    ),
+    'USD'
+)
+WHERE sp.currency IS NULL;
+
+-- listings priced in another currency than their profile are left without shipping options
+UPDATE listings.listings l
+SET shipping_profile_id = NULL
+FROM listings.shipping_profiles sp
+WHERE sp.id = l.shipping_profile_id AND sp.currency <> l.currency;
+
+ALTER TABLE listings.shipping_profiles ALTER COLUMN currency SET NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE listings.shipping_profiles DROP COLUMN IF EXISTS currency;
+-- +goose StatementEnd
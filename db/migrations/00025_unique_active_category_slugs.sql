@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+-- slugs given by admins were stored as is, they are normalized like generated slugs
+UPDATE listings.categories
+SET slug = trim(BOTH '-' FROM regexp_replace(lower(slug), '[^a-z0-9]+', '-', 'g'))
+WHERE slug !~ '^[a-z0-9]+(-[a-z0-9]+)*$';
+
+-- slugs without any latin letters or digits are derived from the category id
+UPDATE listings.categories SET slug = replace(id, '_', '-') WHERE slug = '';
+
+-- slugs which became equal, like "T-Shirts" and "t shirts", are kept only by the oldest
+-- active category, the others get their id appended
+UPDATE listings.categories c
+SET slug = left(c.slug, 69) || '-' || replace(c.id, '_', '-')
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY slug ORDER BY created_at, id) AS n
+    FROM listings.categories
+    WHERE deleted_at IS NULL
+) d
+WHERE d.id = c.id AND d.n > 1;
+
+-- slugs were unique across deleted categories too, so a deleted category blocked its slug
+ALTER TABLE listings.categories DROP CONSTRAINT IF EXISTS categories_slug_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS categories_slug_idx
+    ON listings.categories (slug)
+    WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- deleted categories reusing a slug of another category get their id appended
+UPDATE listings.categories c
+SET slug = left(c.slug, 69) || '-' || replace(c.id, '_', '-')
+FROM (
+    SELECT
+        id,
+        ROW_NUMBER() OVER (
+            PARTITION BY slug ORDER BY deleted_at IS NOT NULL, created_at, id
+        ) AS n
+    FROM listings.categories
+) d
+WHERE d.id = c.id AND d.n > 1;
+
+DROP INDEX IF EXISTS listings.categories_slug_idx;
+
+ALTER TABLE listings.categories ADD CONSTRAINT categories_slug_key UNIQUE (slug);
+-- +goose StatementEnd
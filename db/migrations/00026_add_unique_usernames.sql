@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- usernames taken by more than one active account, ignoring letter case, stay with the oldest
+-- account, the others get their id appended
+UPDATE auth.users u
+SET username = left(u.username, 14) || '-' || substr(u.id, 6), updated_at = NOW()
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY LOWER(username) ORDER BY created_at, id) AS n
+    FROM auth.users
+    WHERE deleted_at IS NULL
+) d
+WHERE d.id = u.id AND d.n > 1;
+
+DROP INDEX IF EXISTS auth.users_lower_username_idx;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_lower_username_key
+    ON auth.users (LOWER(username))
+    WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS auth.users_lower_username_key;
+
+CREATE INDEX IF NOT EXISTS users_lower_username_idx ON auth.users (LOWER(username));
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- open listings created before listings expired never got an expiry date, they get a full
+-- listing period from now, 30 days where the category has no duration of its own
+UPDATE listings.listings l
+SET expires_at = NOW() + make_interval(days => COALESCE(
+    (SELECT c.listing_duration_days FROM listings.categories c WHERE c.id = l.category_id),
+    30
+))
+WHERE l.status = 'open' AND l.expires_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- backfilled expiry dates can't be told apart from ones set by the service, they are kept
+SELECT 1;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+-- repeated success webhooks used to save the same payment more than once, later copies
+-- without a shipment or reviews are dropped
+DELETE FROM payments.payments p
+USING (
+    SELECT
+        id,
+        ROW_NUMBER() OVER (
+            PARTITION BY provider, provider_payment_id ORDER BY created_at, id
+        ) AS n
+    FROM payments.payments
+) d
+WHERE
+    d.id = p.id
+    AND d.n > 1
+    AND NOT EXISTS (SELECT 1 FROM payments.shipments s WHERE s.payment_id = p.id)
+    AND NOT EXISTS (SELECT 1 FROM payments.reviews r WHERE r.payment_id = p.id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS payments_provider_payment_id_key
+    ON payments.payments (provider, provider_payment_id);
+
+-- payments charged for listings which can't be sold to the buyer anymore wait for a refund
+-- or an admin
+ALTER TABLE payments.payments ADD COLUMN needs_review BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS payments_needs_review_idx
+    ON payments.payments (created_at)
+    WHERE needs_review;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS payments.payments_needs_review_idx;
+
+ALTER TABLE payments.payments DROP COLUMN IF EXISTS needs_review;
+
+DROP INDEX IF EXISTS payments.payments_provider_payment_id_key;
+-- +goose StatementEnd
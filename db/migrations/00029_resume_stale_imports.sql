@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+-- running imports record progress after every row, imports without progress for a while
+-- were left behind by a stopped worker and are resumed
+ALTER TABLE listings.imports ADD COLUMN progressed_at TIMESTAMPTZ;
+
+UPDATE listings.imports SET progressed_at = started_at WHERE status = 'running';
+
+CREATE INDEX IF NOT EXISTS imports_running_idx
+    ON listings.imports (progressed_at)
+    WHERE status = 'running';
+
+-- listings remember the import row they were created from, so a resumed import doesn't
+-- create them again
+ALTER TABLE listings.listings
+    ADD COLUMN import_id VARCHAR(30)
+        REFERENCES listings.imports(id)
+        ON DELETE SET NULL,
+    ADD COLUMN import_row INT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS listings_import_row_key
+    ON listings.listings (import_id, import_row)
+    WHERE import_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS listings.listings_import_row_key;
+
+ALTER TABLE listings.listings
+    DROP COLUMN IF EXISTS import_row,
+    DROP COLUMN IF EXISTS import_id;
+
+DROP INDEX IF EXISTS listings.imports_running_idx;
+
+ALTER TABLE listings.imports DROP COLUMN IF EXISTS progressed_at;
+-- +goose StatementEnd
@@ -45,6 +45,10 @@ func (h *Handler) HandleRegister(c echo.Context) error {
 
 	respDto, err := h.svc.Register(c.Request().Context(), &reqDto)
 	if err != nil {
+		if errors.Is(err, service.ErrUsernameTaken) {
+			return r.JSONError(c, err.Error(), err, http.StatusConflict)
+		}
+
 		return r.JSONError(c, "failed to register user", err, http.StatusInternalServerError)
 	}
 
@@ -63,6 +63,40 @@ func AuthenticateMiddleware(
 	}
 }
 
+// OptionalAuthenticateMiddleware saves UserClaims in echo context when a valid token is
+// provided in Authorization header and lets anonymous requests through.
+func OptionalAuthenticateMiddleware(svc *service.Service) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			auth := c.Request().Header.Get("Authorization")
+			if auth == "" {
+				return next(c)
+			}
+
+			userClaims, err := svc.ParseJWT(strings.TrimPrefix(auth, "Bearer "))
+			if err != nil {
+				return responses.JSONError(
+					c,
+					"invalid token",
+					err,
+					http.StatusUnauthorized,
+				)
+			}
+
+			c.Set(userContextKey, userClaims)
+
+			return next(c)
+		}
+	}
+}
+
+// LookupUserFromContext returns UserClaims from echo context or nil for anonymous requests.
+func LookupUserFromContext(c echo.Context) *dto.UserClaims {
+	claims, _ := c.Get(userContextKey).(*dto.UserClaims)
+
+	return claims
+}
+
 // GetUserFromContext fetches UserClaims object from echo context.
 func GetUserFromContext(c echo.Context) (*dto.UserClaims, error) {
 	claims, ok := c.Get(userContextKey).(*dto.UserClaims)
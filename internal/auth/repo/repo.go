@@ -9,10 +9,18 @@ import (
 	"golang-connect-marketplace/pkg/generate"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
-// ErrNoRowsReturned is returned if database query returns no results.
-var ErrNoRowsReturned = errors.New("no rows returned")
+// usernameConstraint is the unique index of usernames, which ignores letter case.
+const usernameConstraint = "users_lower_username_key"
+
+var (
+	// ErrNoRowsReturned is returned if database query returns no results.
+	ErrNoRowsReturned = errors.New("no rows returned")
+	// ErrUsernameTaken is returned when another active user already has the username.
+	ErrUsernameTaken = errors.New("username is taken")
+)
 
 // Repo defines methods for accessing and managing user data.
 type Repo interface {
@@ -46,12 +54,17 @@ func (r *repo) CreateUser(ctx context.Context, reqDto *dto.RegisterRequest) (*dt
 
 	row, err := r.db.NamedQueryContext(ctx, query, reqDto)
 	if err != nil {
-		return nil, fmt.Errorf("inserting user into database: %w", err)
+		return nil, fmt.Errorf("inserting user into database: %w", userError(err))
 	}
 
 	defer func() { _ = row.Close() }()
 
 	if !row.Next() {
+		err = row.Err()
+		if err != nil {
+			return nil, fmt.Errorf("inserting user into database: %w", userError(err))
+		}
+
 		return nil, ErrNoRowsReturned
 	}
 
@@ -188,3 +201,14 @@ func (r *repo) CreateOAuthUser(ctx context.Context, req *dto.OAuthUser) (*dto.OA
 
 	return &user, nil
 }
+
+// userError returns ErrUsernameTaken for violations of the username index, other errors are
+// returned unchanged.
+func userError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Constraint == usernameConstraint {
+		return fmt.Errorf("%w: %w", ErrUsernameTaken, err)
+	}
+
+	return err
+}
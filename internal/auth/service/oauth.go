@@ -7,15 +7,24 @@ import (
 	"errors"
 	"fmt"
 	"golang-connect-marketplace/internal/auth/dto"
+	"golang-connect-marketplace/internal/auth/repo"
+	"golang-connect-marketplace/pkg/generate"
 	"net/http"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	// "net/http".
 
 	"golang.org/x/oauth2"
 )
 
+const (
+	maxUsernameLength = 40
+	// suffixedUsernamePrefixLength leaves room for a random suffix of suffixed usernames.
+	suffixedUsernamePrefixLength = 30
+)
+
 // ErrOAuthAPIBadStatus is returned when external oauth api returns status other than OK (200).
 var ErrOAuthAPIBadStatus = errors.New("external oauth API returned bad status")
 
@@ -155,13 +164,22 @@ func (s *Service) getOrCreateOAuthUser(
 		return user, nil
 	}
 
-	user, err = s.repo.CreateUser(ctx, &dto.RegisterRequest{ //nolint:exhaustruct
+	req := &dto.RegisterRequest{ //nolint:exhaustruct
 		Email:    email,
 		Password: "_",
 		Name:     name,
 		Lastname: lastname,
 		Username: username,
-	})
+	}
+
+	user, err = s.repo.CreateUser(ctx, req)
+	if errors.Is(err, repo.ErrUsernameTaken) {
+		// names from the provider may be taken by local accounts, sign up under a
+		// suffixed one instead of failing the login
+		req.Username = suffixedUsername(username)
+		user, err = s.repo.CreateUser(ctx, req)
+	}
+
 	if err != nil {
 		return nil, fmt.Errorf("creating new user during oauth: %w", err)
 	}
@@ -178,6 +196,18 @@ func (s *Service) getOrCreateOAuthUser(
 	return user, nil
 }
 
+// suffixedUsername appends a random suffix to the username, shortening it to fit the
+// username length limit.
+func suffixedUsername(username string) string {
+	prefix := username
+	for len(prefix) > suffixedUsernamePrefixLength {
+		_, size := utf8.DecodeLastRuneInString(prefix)
+		prefix = prefix[:len(prefix)-size]
+	}
+
+	return generate.ID(prefix, maxUsernameLength)
+}
+
 func fetchExternalOAuthUser[T any](
 	ctx context.Context,
 	token *oauth2.Token,
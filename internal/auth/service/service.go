@@ -30,6 +30,9 @@ var (
 	ErrMissingRoleClaim = errors.New("token is missing role claim")
 	// ErrParsingJWTClaims is returned when parsing jwt claims fails.
 	ErrParsingJWTClaims = errors.New("failed to parse jwt token claims")
+	// ErrUsernameTaken is returned when another user already has the username, ignoring
+	// letter case.
+	ErrUsernameTaken = errors.New("username is taken")
 )
 
 // Service provides user and auth related operations.
@@ -77,6 +80,10 @@ func (s *Service) Register(ctx context.Context, reqDto *dto.RegisterRequest) (*d
 
 	respDto, err := s.repo.CreateUser(ctx, reqDto)
 	if err != nil {
+		if errors.Is(err, repo.ErrUsernameTaken) {
+			return nil, ErrUsernameTaken
+		}
+
 		return nil, fmt.Errorf("creating new user: %w", err)
 	}
 
@@ -0,0 +1,97 @@
+package dto
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// AttributeType represents the type of values a category attribute accepts.
+type AttributeType string
+
+const (
+	// AttributeTypeEnum accepts one of the attribute options.
+	AttributeTypeEnum AttributeType = "enum"
+	// AttributeTypeNumber accepts any number, e.g. mileage or engine size.
+	AttributeTypeNumber AttributeType = "number"
+	// AttributeTypeText accepts free text.
+	AttributeTypeText AttributeType = "text"
+	// AttributeTypeBoolean accepts true or false.
+	AttributeTypeBoolean AttributeType = "boolean"
+)
+
+// CategoryAttribute describes a structured field that listings in a category can fill in.
+// Subcategories inherit attributes of their parent categories.
+type CategoryAttribute struct {
+	ID           string         `json:"id"            db:"id"`
+	CategoryID   string         `json:"category_id"   db:"category_id"`
+	Key          string         `json:"key"           db:"key"           validate:"required,max=50"`
+	Label        string         `json:"label"         db:"label"         validate:"required,max=100"`
+	Type         AttributeType  `json:"type"          db:"type"          validate:"required,oneof=enum number text boolean"`
+	Required     bool           `json:"required"      db:"required"`
+	Unit         *string        `json:"unit"          db:"unit"          validate:"omitempty,max=20"`
+	Options      pq.StringArray `json:"options"       db:"options"       validate:"required_if=Type enum,unique,dive,required,max=50"`
+	DisplayOrder int            `json:"display_order" db:"display_order"`
+}
+
+// SetCategoryAttributesRequest represents payload sent when replacing attribute schema
+// of a category.
+type SetCategoryAttributesRequest struct {
+	CategoryID string              `json:"-"          validate:"required"`
+	Attributes []CategoryAttribute `json:"attributes" validate:"dive"`
+}
+
+// ListingAttributes holds attribute values of a listing keyed by attribute key.
+type ListingAttributes map[string]any
+
+// ErrInvalidListingAttributesScanType is returned if scanning json into ListingAttributes fails.
+var ErrInvalidListingAttributesScanType = errors.New("invalid type for ListingAttributes scan")
+
+// Scan implements sql.Scanner to decode listing attributes stored as JSONB.
+func (la *ListingAttributes) Scan(value any) error {
+	if value == nil {
+		*la = ListingAttributes{}
+
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrInvalidListingAttributesScanType, value)
+	}
+
+	attributes := ListingAttributes{}
+
+	err := json.Unmarshal(bytes, &attributes)
+	if err != nil {
+		return fmt.Errorf("unmarshaling ListingAttributes dto: %w", err)
+	}
+
+	*la = attributes
+
+	return nil
+}
+
+// Value implements driver.Valuer to store listing attributes as JSONB.
+// Nil attributes are stored as NULL, so that updates can leave them unchanged.
+func (la ListingAttributes) Value() (driver.Value, error) {
+	if la == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	bytes, err := json.Marshal(map[string]any(la))
+	if err != nil {
+		return nil, fmt.Errorf("marshaling ListingAttributes dto: %w", err)
+	}
+
+	return bytes, nil
+}
+
+// AttributeRange limits numeric attribute values, nil bounds are open.
+type AttributeRange struct {
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+}
@@ -0,0 +1,36 @@
+package dto
+
+import "mime/multipart"
+
+// Category represents category model.
+type Category struct {
+	ID                  string                `json:"id"                    form:"-"                     db:"id"`
+	ParentID            *string               `json:"parent_id"             form:"parent_id"             db:"parent_id"`
+	Title               string                `json:"title"                 form:"title"                 db:"title"                 validate:"required,max=30"`
+	Slug                string                `json:"slug"                  form:"slug"                  db:"slug"                  validate:"omitempty,max=100"`
+	Description         string                `json:"description"           form:"description"           db:"description"`
+	Color               string                `json:"color"                 form:"Color"                 db:"color"                 validate:"required,len=7"`
+	DisplayOrder        int                   `json:"display_order"         form:"display_order"         db:"display_order"`
+	ImagePath           string                `json:"-"                     form:"-"                     db:"image_path"`
+	ImageURL            string                `json:"image_url"             form:"-"                     db:"-"`
+	ImageVariants       ImageVariants         `json:"image_variants"        form:"-"                     db:"image_variants"`
+	ListingDurationDays *int                  `json:"listing_duration_days" form:"listing_duration_days" db:"listing_duration_days" validate:"omitempty,min=1"`
+	Children            []Category            `json:"children,omitempty"    form:"-"                     db:"-"`
+	FileHeader          *multipart.FileHeader `json:"-"                     form:"image"                 db:"-"                     validate:"required"`
+}
+
+// UpdateCategoryRequest represents payload sent when updating a category.
+// Nil fields are left unchanged, an empty ParentID moves the category to the top level.
+type UpdateCategoryRequest struct {
+	ID                  string                `form:"-"                     db:"id"                    validate:"required"`
+	ParentID            *string               `form:"parent_id"             db:"parent_id"`
+	Title               *string               `form:"title"                 db:"title"                 validate:"omitempty,min=1,max=30"`
+	Slug                *string               `form:"slug"                  db:"slug"                  validate:"omitempty,max=100"`
+	Description         *string               `form:"description"           db:"description"`
+	Color               *string               `form:"color"                 db:"color"                 validate:"omitempty,len=7"`
+	DisplayOrder        *int                  `form:"display_order"         db:"display_order"`
+	ListingDurationDays *int                  `form:"listing_duration_days" db:"listing_duration_days" validate:"omitempty,min=1"`
+	ImagePath           *string               `form:"-"                     db:"image_path"`
+	ImageVariants       ImageVariants         `form:"-"                     db:"image_variants"`
+	FileHeader          *multipart.FileHeader `form:"image"                 db:"-"`
+}
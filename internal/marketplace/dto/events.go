@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+// EventType represents a kind of real-time event pushed to connected users.
+type EventType string
+
+const (
+	// EventTypeListingSold is pushed to a seller when their listing has been paid for.
+	EventTypeListingSold EventType = "listing_sold"
+	// EventTypePaymentRefunded is pushed to the buyer and the seller when a payment is refunded.
+	EventTypePaymentRefunded EventType = "payment_refunded"
+	// EventTypeNewMessage is pushed to a thread participant when they receive a message.
+	EventTypeNewMessage EventType = "new_message"
+	// EventTypeOrderShipped is pushed to a buyer when the seller ships their order.
+	EventTypeOrderShipped EventType = "order_shipped"
+	// EventTypeOrderDelivered is pushed to the buyer and the seller when an order is delivered.
+	EventTypeOrderDelivered EventType = "order_delivered"
+)
+
+// Event represents a real-time event addressed to a single user.
+type Event struct {
+	ID        string            `json:"id"`
+	UserID    string            `json:"user_id"`
+	Type      EventType         `json:"type"`
+	Data      map[string]string `json:"data,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
@@ -0,0 +1,41 @@
+package dto
+
+// ListingFacets holds facet counts calculated for listings matching the current filters.
+type ListingFacets struct {
+	Categories []CategoryFacet         `json:"categories"`
+	Prices     []PriceBucket           `json:"prices"`
+	Currencies []FacetCount            `json:"currencies"`
+	Sellers    []SellerFacet           `json:"sellers"`
+	Attributes map[string][]FacetCount `json:"attributes"`
+}
+
+// FacetCount represents the number of listings matching a single facet value.
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// CategoryFacet represents the number of listings directly in a category.
+type CategoryFacet struct {
+	CategoryID string  `json:"category_id"`
+	ParentID   *string `json:"parent_id"`
+	Title      string  `json:"title"`
+	Slug       string  `json:"slug"`
+	Count      int     `json:"count"`
+}
+
+// PriceBucket represents the number of listings priced within an inclusive range.
+// Buckets are calculated separately for every currency.
+type PriceBucket struct {
+	Currency   string `json:"currency"`
+	MinInCents int    `json:"min_in_cents"`
+	MaxInCents int    `json:"max_in_cents"`
+	Count      int    `json:"count"`
+}
+
+// SellerFacet represents the number of listings of a single seller.
+type SellerFacet struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	Count    int    `json:"count"`
+}
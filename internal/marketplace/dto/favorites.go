@@ -0,0 +1,20 @@
+package dto
+
+// FavoriteRequest represents payload sent when adding or removing a favorite listing.
+type FavoriteRequest struct {
+	UserID    string `validate:"required"`
+	ListingID string `validate:"required"`
+}
+
+// GetFavoritesRequest represents payload sent when fetching favorite listings of a user.
+type GetFavoritesRequest struct {
+	UserID string `json:"-"     validate:"required"                query:"-"`
+	Limit  int    `json:"limit" validate:"omitempty,min=1,max=100" query:"limit"`
+	Page   int    `json:"page"  validate:"omitempty,min=1"         query:"page"`
+}
+
+// Watcher represents a user who added a listing to their favorites.
+type Watcher struct {
+	UserID string `db:"user_id"`
+	Email  string `db:"email"`
+}
@@ -0,0 +1,144 @@
+package dto
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"time"
+)
+
+// ImportFormat represents the file format of listing imports and exports.
+type ImportFormat string
+
+const (
+	// ImportFormatCSV indicates a CSV file with a header row.
+	ImportFormatCSV ImportFormat = "csv"
+	// ImportFormatNDJSON indicates a file with one JSON object per line.
+	ImportFormatNDJSON ImportFormat = "ndjson"
+)
+
+// ImportStatus represents the state of a listing import job.
+type ImportStatus string
+
+const (
+	// ImportStatusPending indicates an import waiting for a worker.
+	ImportStatusPending ImportStatus = "pending"
+	// ImportStatusRunning indicates an import being processed.
+	ImportStatusRunning ImportStatus = "running"
+	// ImportStatusCompleted indicates a processed import, some rows may have failed.
+	ImportStatusCompleted ImportStatus = "completed"
+	// ImportStatusFailed indicates an import which couldn't be processed at all.
+	ImportStatusFailed ImportStatus = "failed"
+)
+
+// ImportRow represents one listing of an import file. Images are file names in the ZIP
+// archive uploaded with the file.
+type ImportRow struct {
+	CategoryID   string            `json:"category_id"`
+	Title        string            `json:"title"`
+	Description  string            `json:"description"`
+	PriceInCents int               `json:"price_in_cents"`
+	Currency     string            `json:"currency"`
+	Attributes   ListingAttributes `json:"attributes"`
+	Images       []string          `json:"images,omitempty"`
+}
+
+// ExportRow represents one listing of an export file, in the same format as imports.
+type ExportRow struct {
+	ID     string        `json:"id"`
+	Status ListingStatus `json:"status"`
+	ImportRow
+
+	ImageURLs []string `json:"image_urls"`
+}
+
+// ImportRowResult represents the outcome of importing one row, Row is 1-based and doesn't
+// count the CSV header. ListingID is set when the listing was created.
+type ImportRowResult struct {
+	Row       int      `json:"row"`
+	ListingID *string  `json:"listing_id"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+// ImportRowResults holds results of all rows of an import.
+type ImportRowResults []ImportRowResult
+
+// ErrInvalidImportRowResultsScanType is returned if scanning json into ImportRowResults fails.
+var ErrInvalidImportRowResultsScanType = errors.New("invalid type for ImportRowResults scan")
+
+// Scan implements sql.Scanner to decode import results stored as JSONB.
+func (r *ImportRowResults) Scan(value any) error {
+	if value == nil {
+		*r = ImportRowResults{}
+
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrInvalidImportRowResultsScanType, value)
+	}
+
+	results := ImportRowResults{}
+
+	err := json.Unmarshal(bytes, &results)
+	if err != nil {
+		return fmt.Errorf("unmarshaling ImportRowResults dto: %w", err)
+	}
+
+	*r = results
+
+	return nil
+}
+
+// Value implements driver.Valuer to store import results as JSONB.
+func (r ImportRowResults) Value() (driver.Value, error) {
+	if r == nil {
+		r = ImportRowResults{}
+	}
+
+	bytes, err := json.Marshal([]ImportRowResult(r))
+	if err != nil {
+		return nil, fmt.Errorf("marshaling ImportRowResults dto: %w", err)
+	}
+
+	return bytes, nil
+}
+
+// ImportJob represents an asynchronous import of listings from an uploaded file.
+// Source and Images hold the uploaded files until the import is processed.
+type ImportJob struct {
+	ID           string           `json:"id"            db:"id"`
+	UserID       string           `json:"user_id"       db:"user_id"`
+	Format       ImportFormat     `json:"format"        db:"format"`
+	Status       ImportStatus     `json:"status"        db:"status"`
+	Source       []byte           `json:"-"             db:"source"`
+	Images       []byte           `json:"-"             db:"images"`
+	TotalRows    int              `json:"total_rows"    db:"total_rows"`
+	CreatedCount int              `json:"created_count" db:"created_count"`
+	FailedCount  int              `json:"failed_count"  db:"failed_count"`
+	Results      ImportRowResults `json:"results"       db:"results"`
+	Error        *string          `json:"error"         db:"error"`
+	CreatedAt    time.Time        `json:"created_at"    db:"created_at"`
+	StartedAt    *time.Time       `json:"started_at"    db:"started_at"`
+	ProgressedAt *time.Time       `json:"progressed_at" db:"progressed_at"`
+	FinishedAt   *time.Time       `json:"finished_at"   db:"finished_at"`
+}
+
+// CreateImportRequest represents payload sent when importing listings from a CSV or NDJSON
+// file, with an optional ZIP archive of images. Format is detected from the file extension
+// when it's not set.
+type CreateImportRequest struct {
+	UserID       string                `form:"-"      validate:"required"`
+	Format       ImportFormat          `form:"format" validate:"omitempty,oneof=csv ndjson"`
+	FileHeader   *multipart.FileHeader `form:"file"   validate:"required"`
+	ImagesHeader *multipart.FileHeader `form:"images"`
+}
+
+// ExportListingsRequest represents payload sent when exporting own listings.
+type ExportListingsRequest struct {
+	UserID string       `json:"-"      validate:"required"`
+	Format ImportFormat `json:"format" validate:"omitempty,oneof=csv ndjson" query:"format"`
+}
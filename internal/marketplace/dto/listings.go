@@ -2,23 +2,15 @@
 package dto
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"golang-connect-marketplace/pkg/money"
 	"mime/multipart"
 	"time"
 )
 
-// Category represents category model.
-type Category struct {
-	ID          string                `json:"id"          form:"-"           db:"id"`
-	Title       string                `json:"title"       form:"title"       db:"title"       validate:"required,max=30"`
-	Description string                `json:"description" form:"description" db:"description"`
-	Color       string                `json:"color"       form:"Color"       db:"color"       validate:"required,len=7"`
-	ImagePath   string                `json:"image_path"  form:"-"           db:"image_path"`
-	FileHeader  *multipart.FileHeader `json:"-"           form:"image"       db:"-"           validate:"required"`
-}
-
 // ListingStatus represents the current lifecycle state of a marketplace listing.
 type ListingStatus string
 
@@ -31,24 +23,90 @@ const (
 	ListingStatusSold ListingStatus = "sold"
 	// ListingStatusRefunded indicates that the listing has been refunded.
 	ListingStatusRefunded ListingStatus = "refunded"
+	// ListingStatusExpired indicates that the listing reached its expiry date without being sold.
+	ListingStatusExpired ListingStatus = "expired"
+	// ListingStatusPendingReview indicates that the listing waits for moderator approval.
+	ListingStatusPendingReview ListingStatus = "pending_review"
+	// ListingStatusHidden indicates that the listing was hidden by a moderator.
+	ListingStatusHidden ListingStatus = "hidden"
+	// ListingStatusRemoved indicates that the listing was removed by a moderator.
+	ListingStatusRemoved ListingStatus = "removed"
 )
 
+// IsModerated reports whether the listing status was set by moderation and the listing
+// is visible only to its owner.
+func (s ListingStatus) IsModerated() bool {
+	return s == ListingStatusPendingReview || s == ListingStatusHidden || s == ListingStatusRemoved
+}
+
 // Listing represents a marketplace listing created by a user.
+// PriceInCents is in minor units of Currency, which are cents only for some currencies.
+// ImportID and ImportRow refer to the import row the listing was created from.
 type Listing struct {
-	ID            string        `json:"id"             db:"id"`
-	UserID        string        `json:"user_id"        db:"user_id"`
-	CategoryID    string        `json:"category_id"    db:"category_id"    validate:"required"`
-	CategoryTitle string        `json:"category_title" db:"category_title"`
-	Title         string        `json:"title"          db:"title"          validate:"required,min=8,max=100"`
-	Description   string        `json:"description"    db:"description"    validate:"required"`
-	PriceInCents  int           `json:"price_in_cents" db:"price_in_cents" validate:"required,min=1000"`
-	Currency      string        `json:"currency"       db:"currency"       validate:"required,len=3"`
-	Seller        SellerAccount `json:"seller"         db:"seller"`
-	Status        ListingStatus `json:"status"         db:"status"`
-	Images        ListingImages `json:"images"         db:"images"`
-	CreatedAt     time.Time     `json:"created_at"     db:"created_at"`
-	UpdatedAt     time.Time     `json:"updated_at"     db:"updated_at"`
-	DeletedAt     *time.Time    `json:"deleted_at"     db:"deleted_at"`
+	ID                string            `json:"id"                  db:"id"`
+	UserID            string            `json:"user_id"             db:"user_id"`
+	CategoryID        string            `json:"category_id"         db:"category_id"         validate:"required"`
+	CategoryTitle     string            `json:"category_title"      db:"category_title"`
+	Title             string            `json:"title"               db:"title"               validate:"required,min=8,max=100"`
+	Description       string            `json:"description"         db:"description"         validate:"required"`
+	PriceInCents      int               `json:"price_in_cents"      db:"price_in_cents"      validate:"required,min=1"`
+	Currency          string            `json:"currency"            db:"currency"            validate:"required,len=3"`
+	Attributes        ListingAttributes `json:"attributes"          db:"attributes"`
+	ShippingProfileID *string           `json:"shipping_profile_id" db:"shipping_profile_id"`
+	ShippingOptions   ShippingOptions   `json:"shipping_options"    db:"shipping_options"`
+	Latitude          *float64          `json:"latitude"            db:"latitude"            validate:"required_with=Longitude,omitempty,latitude"`
+	Longitude         *float64          `json:"longitude"           db:"longitude"           validate:"required_with=Latitude,omitempty,longitude"`
+	City              *string           `json:"city"                db:"location_city"       validate:"excluded_without=Latitude,omitempty,min=1,max=100"`
+	DistanceKm        *float64          `json:"distance_km"         db:"distance_km"`
+	Seller            SellerAccount     `json:"seller"              db:"seller"`
+	Status            ListingStatus     `json:"status"              db:"status"`
+	Images            ListingImages     `json:"images"              db:"images"`
+	PrimaryImage      *ListingImage     `json:"primary_image"       db:"-"`
+	FavoriteCount     int               `json:"favorite_count"      db:"favorite_count"`
+	IsFavorited       bool              `json:"is_favorited"        db:"-"`
+	ExpiresAt         *time.Time        `json:"expires_at"          db:"expires_at"`
+	ExpiryNotifiedAt  *time.Time        `json:"-"                   db:"expiry_notified_at"`
+	SearchMatchedAt   *time.Time        `json:"-"                   db:"search_matched_at"`
+	ImportID          *string           `json:"-"                   db:"import_id"`
+	ImportRow         *int              `json:"-"                   db:"import_row"`
+	CreatedAt         time.Time         `json:"created_at"          db:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at"          db:"updated_at"`
+	DeletedAt         *time.Time        `json:"deleted_at"          db:"deleted_at"`
+}
+
+// Price returns the listing price as money of the listing currency.
+func (l *Listing) Price() (money.Money, error) {
+	price, err := money.New(int64(l.PriceInCents), l.Currency)
+	if err != nil {
+		return money.Money{}, fmt.Errorf("parsing listing price: %w", err)
+	}
+
+	return price, nil
+}
+
+// RenewListingRequest represents payload sent when renewing an open or expired listing.
+type RenewListingRequest struct {
+	UserID    string `json:"-" validate:"required"`
+	ListingID string `json:"-" validate:"required"`
+}
+
+// SetListingLocationRequest represents payload sent when setting the pickup location of
+// a listing. Nil coordinates remove the location from the listing.
+type SetListingLocationRequest struct {
+	UserID    string   `json:"-"         validate:"required"`
+	ListingID string   `json:"-"         validate:"required"`
+	Latitude  *float64 `json:"latitude"  validate:"required_with=Longitude,omitempty,latitude"`
+	Longitude *float64 `json:"longitude" validate:"required_with=Latitude,omitempty,longitude"`
+	City      *string  `json:"city"      validate:"excluded_without=Latitude,omitempty,min=1,max=100"`
+}
+
+// ListingExpiryNotice represents a listing about to expire, along with its seller contact info.
+type ListingExpiryNotice struct {
+	ListingID   string    `db:"listing_id"`
+	Title       string    `db:"title"`
+	UserID      string    `db:"user_id"`
+	SellerEmail string    `db:"seller_email"`
+	ExpiresAt   time.Time `db:"expires_at"`
 }
 
 // AddImagesRequest represents payload sent when adding new images for a listing.
@@ -67,14 +125,136 @@ type DeleteImageRequest struct {
 
 // ListingImage represents a single image belonging to a listing.
 type ListingImage struct {
-	ID        string `json:"id"         db:"id"`
-	ListingID string `json:"listing_id" db:"listing_id"`
-	Path      string `json:"path"       db:"path"`
+	ID        string        `json:"id"         db:"id"`
+	ListingID string        `json:"listing_id" db:"listing_id"`
+	Path      string        `json:"-"          db:"path"`
+	URL       string        `json:"url"        db:"-"`
+	Position  int           `json:"position"   db:"position"`
+	IsPrimary bool          `json:"is_primary" db:"is_primary"`
+	AltText   *string       `json:"alt_text"   db:"alt_text"`
+	Variants  ImageVariants `json:"variants"   db:"variants"`
+}
+
+// ReorderImagesRequest represents payload sent when changing the order of listing images.
+// ImageIDs must contain every image of the listing exactly once.
+type ReorderImagesRequest struct {
+	UserID         string   `json:"-"                validate:"required"`
+	ListingID      string   `json:"-"                validate:"required"`
+	ImageIDs       []string `json:"image_ids"        validate:"required,min=1,unique,dive,required"`
+	PrimaryImageID string   `json:"primary_image_id"`
+}
+
+// UpdateImageRequest represents payload sent when updating a single listing image.
+type UpdateImageRequest struct {
+	UserID    string  `json:"-"          validate:"required"`
+	ListingID string  `json:"-"          validate:"required"`
+	ImageID   string  `json:"-"          validate:"required"`
+	AltText   *string `json:"alt_text"   validate:"omitempty,max=250"`
+	IsPrimary *bool   `json:"is_primary"`
+}
+
+// ImageVariant represents a resized copy of an uploaded image.
+// Path is the storage key and is never exposed to clients, they get a signed URL instead.
+type ImageVariant struct {
+	Path   string `json:"-"`
+	URL    string `json:"url,omitempty"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// imageVariantRecord is the database representation of ImageVariant.
+type imageVariantRecord struct {
+	Path   string `json:"path"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// listingImageRecord is the database representation of ListingImage.
+type listingImageRecord struct {
+	ID        string                        `json:"id"`
+	ListingID string                        `json:"listing_id"`
+	Path      string                        `json:"path"`
+	Position  int                           `json:"position"`
+	IsPrimary bool                          `json:"is_primary"`
+	AltText   *string                       `json:"alt_text"`
+	Variants  map[string]imageVariantRecord `json:"variants"`
+}
+
+func variantsFromRecords(records map[string]imageVariantRecord) ImageVariants {
+	variants := make(ImageVariants, len(records))
+
+	for name, r := range records {
+		variants[name] = ImageVariant{Path: r.Path, URL: "", Width: r.Width, Height: r.Height}
+	}
+
+	return variants
+}
+
+// ImageVariants represents image variants keyed by variant name, e.g. thumb, medium, large.
+type ImageVariants map[string]ImageVariant
+
+// ErrInvalidImageVariantsScanType is returned if scanning json into ImageVariants fails.
+var ErrInvalidImageVariantsScanType = errors.New("invalid type for ImageVariants scan")
+
+// Scan implements sql.Scanner to decode image variants stored as JSONB.
+func (iv *ImageVariants) Scan(value any) error {
+	if value == nil {
+		*iv = ImageVariants{}
+
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrInvalidImageVariantsScanType, value)
+	}
+
+	var records map[string]imageVariantRecord
+
+	err := json.Unmarshal(bytes, &records)
+	if err != nil {
+		return fmt.Errorf("unmarshaling ImageVariants dto: %w", err)
+	}
+
+	*iv = variantsFromRecords(records)
+
+	return nil
+}
+
+// Value implements driver.Valuer to store image variants as JSONB.
+func (iv ImageVariants) Value() (driver.Value, error) {
+	records := make(map[string]imageVariantRecord, len(iv))
+
+	for name, v := range iv {
+		records[name] = imageVariantRecord{Path: v.Path, Width: v.Width, Height: v.Height}
+	}
+
+	bytes, err := json.Marshal(records)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling ImageVariants dto: %w", err)
+	}
+
+	return bytes, nil
 }
 
 // ListingImages represents a collection of listing images.
 type ListingImages []ListingImage
 
+// Primary returns the image flagged as primary, falling back to the first image.
+func (li ListingImages) Primary() *ListingImage {
+	for i := range li {
+		if li[i].IsPrimary {
+			return &li[i]
+		}
+	}
+
+	if len(li) > 0 {
+		return &li[0]
+	}
+
+	return nil
+}
+
 // ErrInvalidListingImagesScanType is returned if scanning json into ListingImage fails.
 var ErrInvalidListingImagesScanType = errors.New("invalid type for ListingImages scan")
 
@@ -91,31 +271,72 @@ func (li *ListingImages) Scan(value any) error {
 		return fmt.Errorf("%w: %T", ErrInvalidListingImagesScanType, value)
 	}
 
-	err := json.Unmarshal(bytes, li)
+	var records []listingImageRecord
+
+	err := json.Unmarshal(bytes, &records)
 	if err != nil {
 		return fmt.Errorf("unmarshaling ListingImage dto: %w", err)
 	}
 
+	images := make(ListingImages, 0, len(records))
+
+	for _, r := range records {
+		images = append(images, ListingImage{
+			ID:        r.ID,
+			ListingID: r.ListingID,
+			Path:      r.Path,
+			URL:       "",
+			Position:  r.Position,
+			IsPrimary: r.IsPrimary,
+			AltText:   r.AltText,
+			Variants:  variantsFromRecords(r.Variants),
+		})
+	}
+
+	*li = images
+
 	return nil
 }
 
 // UpdateListingRequest represents payload sent when adding updating a listing.
+// Provided attributes replace all attribute values of the listing. ExpiresAt is set when
+// the listing is reopened. ActorID and ActorRole identify who made the edit in the revision
+// history.
 type UpdateListingRequest struct {
-	ID           string         `json:"id"             db:"id"`
-	CategoryID   string         `json:"category_id"    db:"category_id"`
-	Title        string         `json:"title"          db:"title"          validate:"omitempty,min=8,max=100"`
-	Description  string         `json:"description"    db:"description"`
-	PriceInCents int            `json:"price_in_cents" db:"price_in_cents" validate:"omitempty,min=1000"`
-	Currency     string         `json:"currency"       db:"currency"       validate:"omitempty,len=3"`
-	Status       *ListingStatus `json:"status"         db:"status"`
+	ID           string            `json:"id"             db:"id"`
+	CategoryID   string            `json:"category_id"    db:"category_id"`
+	Title        string            `json:"title"          db:"title"          validate:"omitempty,min=8,max=100"`
+	Description  string            `json:"description"    db:"description"`
+	PriceInCents int               `json:"price_in_cents" db:"price_in_cents" validate:"omitempty,min=1"`
+	Currency     string            `json:"currency"       db:"currency"       validate:"omitempty,len=3"`
+	Attributes   ListingAttributes `json:"attributes"     db:"attributes"`
+	Status       *ListingStatus    `json:"status"         db:"status"         validate:"omitempty,oneof=open canceled sold refunded expired pending_review hidden removed"`
+	ExpiresAt    *time.Time        `json:"-"              db:"expires_at"`
+	ActorID      string            `json:"-"              db:"-"`
+	ActorRole    RevisionActorRole `json:"-"              db:"-"`
 }
 
 // GetListingsRequest represents payload sent when fetching a list of listings.
+// Attributes filter listings by attribute values, any of the listed values matches.
+// AttributeRanges filter listings by numeric attribute values. Facets requests facet counts
+// computed with the same filters, UserID is empty for anonymous requests.
+// SellerID limits listings to a single seller. Near holds "lat,lng" the listings are sorted
+// by distance from, it is parsed into NearLatitude and NearLongitude. RadiusKm requires Near
+// and limits listings to those located within the radius.
 type GetListingsRequest struct {
-	Limit    int     `json:"limit"    validate:"omitempty,min=1,max=100" query:"limit"`
-	Page     int     `json:"page"     validate:"omitempty,min=1"         query:"page"`
-	Category *string `json:"category" validate:"-"                       query:"category"`
-	Keyword  *string `json:"keyword"  validate:"-"                       query:"keyword"`
+	Limit           int                       `json:"limit"            validate:"omitempty,min=1,max=100" query:"limit"`
+	Page            int                       `json:"page"             validate:"omitempty,min=1"         query:"page"`
+	Category        *string                   `json:"category"         validate:"-"                       query:"category"`
+	Keyword         *string                   `json:"keyword"          validate:"-"                       query:"keyword"`
+	SellerID        *string                   `json:"seller_id"        validate:"-"                       query:"seller_id"`
+	Attributes      map[string][]string       `json:"attributes"       validate:"-"                       query:"-"`
+	AttributeRanges map[string]AttributeRange `json:"attribute_ranges" validate:"-"                       query:"-"`
+	Facets          bool                      `json:"facets"           validate:"-"                       query:"facets"`
+	Near            string                    `json:"near"             validate:"-"                       query:"near"`
+	NearLatitude    *float64                  `json:"-"                validate:"-"                       query:"-"`
+	NearLongitude   *float64                  `json:"-"                validate:"-"                       query:"-"`
+	RadiusKm        *float64                  `json:"radius_km"        validate:"omitempty,gt=0,max=1000" query:"radius_km"`
+	UserID          string                    `json:"-"                validate:"-"                       query:"-"`
 }
 
 // PaginationMeta represents pagination metadata sent back to the client.
@@ -130,5 +351,6 @@ type PaginationMeta struct {
 // GetListingsResponse represents payload sent back when fetching a list of listings.
 type GetListingsResponse struct {
 	Meta     PaginationMeta `json:"meta"`
+	Facets   *ListingFacets `json:"facets,omitempty"`
 	Listings []Listing      `json:"listings"`
 }
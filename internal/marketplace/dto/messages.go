@@ -0,0 +1,88 @@
+package dto
+
+import "time"
+
+// Participant represents a user taking part in a message thread. Email is only used for
+// notifications and is never exposed to the other participant.
+type Participant struct {
+	ID       string `json:"id"       db:"id"`
+	Username string `json:"username" db:"username"`
+	Email    string `json:"-"        db:"email"`
+}
+
+// Thread represents a conversation between a buyer and the seller of a listing.
+type Thread struct {
+	ID            string      `json:"id"              db:"id"`
+	ListingID     string      `json:"listing_id"      db:"listing_id"`
+	ListingTitle  string      `json:"listing_title"   db:"listing_title"`
+	Buyer         Participant `json:"buyer"           db:"buyer"`
+	Seller        Participant `json:"seller"          db:"seller"`
+	UnreadCount   int         `json:"unread_count"    db:"unread_count"`
+	LastMessageAt *time.Time  `json:"last_message_at" db:"last_message_at"`
+	CreatedAt     time.Time   `json:"created_at"      db:"created_at"`
+}
+
+// HasParticipant reports whether user is the buyer or the seller of the thread.
+func (t *Thread) HasParticipant(userID string) bool {
+	return t.Buyer.ID == userID || t.Seller.ID == userID
+}
+
+// Message represents a single message sent in a thread.
+type Message struct {
+	ID        string    `json:"id"         db:"id"`
+	ThreadID  string    `json:"thread_id"  db:"thread_id"`
+	SenderID  string    `json:"sender_id"  db:"sender_id"`
+	Body      string    `json:"body"       db:"body"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// SendMessageRequest represents payload sent when sending a message. Buyers start a thread
+// by sending a message to a listing, replies are sent to an existing thread.
+type SendMessageRequest struct {
+	SenderID  string `json:"-"    validate:"required"`
+	ListingID string `json:"-"`
+	ThreadID  string `json:"-"`
+	Body      string `json:"body" validate:"required,max=2000"`
+}
+
+// GetThreadRequest represents payload sent when fetching messages of a thread.
+type GetThreadRequest struct {
+	ThreadID string `json:"-"     validate:"required"                query:"-"`
+	Limit    int    `json:"limit" validate:"omitempty,min=1,max=100" query:"limit"`
+	Page     int    `json:"page"  validate:"omitempty,min=1"         query:"page"`
+}
+
+// GetThreadResponse represents payload sent back when fetching messages of a thread.
+type GetThreadResponse struct {
+	Thread   *Thread   `json:"thread"`
+	Messages []Message `json:"messages"`
+}
+
+// UnreadMessagesResponse represents payload sent back when fetching unread messages count.
+type UnreadMessagesResponse struct {
+	Unread int `json:"unread"`
+}
+
+// BlockUserRequest represents payload sent when blocking or unblocking a user.
+type BlockUserRequest struct {
+	UserID        string `validate:"required"`
+	BlockedUserID string `validate:"required,nefield=UserID"`
+}
+
+// ReportAbuseRequest represents payload sent when reporting abuse in a thread.
+type ReportAbuseRequest struct {
+	ReporterID string  `json:"-"          validate:"required"`
+	ThreadID   string  `json:"-"          validate:"required"`
+	MessageID  *string `json:"message_id"`
+	Reason     string  `json:"reason"     validate:"required,max=1000"`
+}
+
+// AbuseReport represents a report of abusive messages in a thread.
+type AbuseReport struct {
+	ID         string    `json:"id"          db:"id"`
+	ThreadID   string    `json:"thread_id"   db:"thread_id"`
+	MessageID  *string   `json:"message_id"  db:"message_id"`
+	ReporterID string    `json:"reporter_id" db:"reporter_id"`
+	Reason     string    `json:"reason"      db:"reason"`
+	CreatedAt  time.Time `json:"created_at"  db:"created_at"`
+}
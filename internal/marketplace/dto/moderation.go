@@ -0,0 +1,187 @@
+package dto
+
+import "time"
+
+// ReportTarget represents what kind of entity a report is about.
+type ReportTarget string
+
+const (
+	// ReportTargetListing indicates a report about a listing.
+	ReportTargetListing ReportTarget = "listing"
+	// ReportTargetUser indicates a report about a user.
+	ReportTargetUser ReportTarget = "user"
+)
+
+// ReportReason represents the reason code of a report.
+type ReportReason string
+
+const (
+	// ReportReasonSpam indicates spam or misleading content.
+	ReportReasonSpam ReportReason = "spam"
+	// ReportReasonProhibitedItem indicates an item which is not allowed on the marketplace.
+	ReportReasonProhibitedItem ReportReason = "prohibited_item"
+	// ReportReasonCounterfeit indicates a counterfeit item.
+	ReportReasonCounterfeit ReportReason = "counterfeit"
+	// ReportReasonFraud indicates a suspected scam.
+	ReportReasonFraud ReportReason = "fraud"
+	// ReportReasonOffensive indicates offensive content or behavior.
+	ReportReasonOffensive ReportReason = "offensive"
+	// ReportReasonOther indicates a reason explained in report details.
+	ReportReasonOther ReportReason = "other"
+)
+
+// ReportStatus represents the state of a report in the moderation queue.
+type ReportStatus string
+
+const (
+	// ReportStatusOpen indicates a report waiting for a moderator.
+	ReportStatusOpen ReportStatus = "open"
+	// ReportStatusResolved indicates a report a moderator acted on.
+	ReportStatusResolved ReportStatus = "resolved"
+	// ReportStatusDismissed indicates a report a moderator dismissed.
+	ReportStatusDismissed ReportStatus = "dismissed"
+)
+
+// ModerationActionType represents an action a moderator can take.
+type ModerationActionType string
+
+const (
+	// ModerationActionApproveListing publishes a listing pending review.
+	ModerationActionApproveListing ModerationActionType = "approve_listing"
+	// ModerationActionHideListing hides a listing from everyone except its owner.
+	ModerationActionHideListing ModerationActionType = "hide_listing"
+	// ModerationActionRestoreListing publishes a hidden listing again.
+	ModerationActionRestoreListing ModerationActionType = "restore_listing"
+	// ModerationActionRemoveListing permanently removes a listing.
+	ModerationActionRemoveListing ModerationActionType = "remove_listing"
+	// ModerationActionWarnUser sends a warning to a user.
+	ModerationActionWarnUser ModerationActionType = "warn_user"
+	// ModerationActionSuspendUser suspends a seller, their listings are no longer public.
+	ModerationActionSuspendUser ModerationActionType = "suspend_user"
+	// ModerationActionUnsuspendUser lifts a seller suspension.
+	ModerationActionUnsuspendUser ModerationActionType = "unsuspend_user"
+	// ModerationActionDismissReport closes a report without acting on it.
+	ModerationActionDismissReport ModerationActionType = "dismiss_report"
+)
+
+// IsListingAction reports whether the action targets a listing.
+func (a ModerationActionType) IsListingAction() bool {
+	switch a {
+	case ModerationActionApproveListing, ModerationActionHideListing,
+		ModerationActionRestoreListing, ModerationActionRemoveListing:
+		return true
+	default:
+		return false
+	}
+}
+
+// Report represents a report about a listing or a user. UserID is the reported user,
+// for listing reports it's the listing owner. ReporterID is nil for reports filed by
+// automated listing screening.
+type Report struct {
+	ID         string       `json:"id"          db:"id"`
+	TargetType ReportTarget `json:"target_type" db:"target_type"`
+	ListingID  *string      `json:"listing_id"  db:"listing_id"`
+	UserID     string       `json:"user_id"     db:"user_id"`
+	ReporterID *string      `json:"reporter_id" db:"reporter_id"`
+	Reason     ReportReason `json:"reason"      db:"reason"`
+	Details    string       `json:"details"     db:"details"`
+	Status     ReportStatus `json:"status"      db:"status"`
+	ResolvedBy *string      `json:"resolved_by" db:"resolved_by"`
+	CreatedAt  time.Time    `json:"created_at"  db:"created_at"`
+	ResolvedAt *time.Time   `json:"resolved_at" db:"resolved_at"`
+}
+
+// CreateReportRequest represents payload sent when reporting a listing or a user.
+// For listing reports UserID is filled in with the listing owner.
+type CreateReportRequest struct {
+	ReporterID string       `json:"-"       validate:"required"`
+	TargetType ReportTarget `json:"-"`
+	ListingID  *string      `json:"-"`
+	UserID     string       `json:"-"       validate:"nefield=ReporterID"`
+	Reason     ReportReason `json:"reason"  validate:"required,oneof=spam prohibited_item counterfeit fraud offensive other"`
+	Details    string       `json:"details" validate:"max=2000"`
+}
+
+// GetReportsRequest represents payload sent when fetching the moderation queue.
+type GetReportsRequest struct {
+	Status ReportStatus `json:"status" validate:"omitempty,oneof=open resolved dismissed" query:"status"`
+	Limit  int          `json:"limit"  validate:"omitempty,min=1,max=100"                 query:"limit"`
+	Page   int          `json:"page"   validate:"omitempty,min=1"                         query:"page"`
+}
+
+// ModerationAction represents an entry of the moderation audit trail.
+type ModerationAction struct {
+	ID        string               `json:"id"         db:"id"`
+	AdminID   string               `json:"admin_id"   db:"admin_id"`
+	Action    ModerationActionType `json:"action"     db:"action"`
+	ReportID  *string              `json:"report_id"  db:"report_id"`
+	ListingID *string              `json:"listing_id" db:"listing_id"`
+	UserID    *string              `json:"user_id"    db:"user_id"`
+	UserEmail *string              `json:"-"          db:"user_email"`
+	Note      string               `json:"note"       db:"note"`
+	CreatedAt time.Time            `json:"created_at" db:"created_at"`
+}
+
+// ModerationActionRequest represents payload sent when a moderator takes an action.
+// Targets missing from the request are taken from the report.
+type ModerationActionRequest struct {
+	AdminID   string               `json:"-"          validate:"required"`
+	Action    ModerationActionType `json:"action"     validate:"required,oneof=approve_listing hide_listing restore_listing remove_listing warn_user suspend_user unsuspend_user dismiss_report"`
+	ReportID  *string              `json:"report_id"`
+	ListingID *string              `json:"listing_id"`
+	UserID    *string              `json:"user_id"`
+	Note      string               `json:"note"       validate:"max=1000"`
+}
+
+// GetModerationActionsRequest represents payload sent when fetching the audit trail.
+type GetModerationActionsRequest struct {
+	ListingID *string `json:"listing_id" validate:"-"                       query:"listing_id"`
+	UserID    *string `json:"user_id"    validate:"-"                       query:"user_id"`
+	Limit     int     `json:"limit"      validate:"omitempty,min=1,max=100" query:"limit"`
+	Page      int     `json:"page"       validate:"omitempty,min=1"         query:"page"`
+}
+
+// GetPendingListingsRequest represents payload sent when fetching listings waiting for review.
+type GetPendingListingsRequest struct {
+	Limit int `json:"limit" validate:"omitempty,min=1,max=100" query:"limit"`
+	Page  int `json:"page"  validate:"omitempty,min=1"         query:"page"`
+}
+
+// SellerStanding represents moderation related state of a seller.
+type SellerStanding struct {
+	Suspended  bool `db:"suspended"`
+	SalesCount int  `db:"sales_count"`
+}
+
+// ScreeningVerdict represents what automated screening decided about a listing.
+type ScreeningVerdict string
+
+const (
+	// ScreeningVerdictFlag sends the listing to the moderation queue.
+	ScreeningVerdictFlag ScreeningVerdict = "flag"
+	// ScreeningVerdictBlock rejects the listing.
+	ScreeningVerdictBlock ScreeningVerdict = "block"
+)
+
+// ScreeningFinding represents a problem automated screening found in a listing.
+type ScreeningFinding struct {
+	Screener string           `json:"screener"`
+	Verdict  ScreeningVerdict `json:"verdict"`
+	Reason   ReportReason     `json:"reason"`
+	Details  string           `json:"details"`
+}
+
+// CategoryPriceStats represents prices of listings in a category, used to spot
+// anomalous prices.
+type CategoryPriceStats struct {
+	MedianPriceInCents float64 `db:"median_price_in_cents"`
+	SampleSize         int     `db:"sample_size"`
+}
+
+// ListingText represents the text of a listing, used to spot duplicate listings.
+type ListingText struct {
+	ID          string `db:"id"`
+	Title       string `db:"title"`
+	Description string `db:"description"`
+}
@@ -0,0 +1,44 @@
+package dto
+
+// NotificationType represents a kind of notification sent to a user.
+type NotificationType string
+
+const (
+	// NotificationTypeListingExpiringSoon is sent to a seller before their listing expires.
+	NotificationTypeListingExpiringSoon NotificationType = "listing_expiring_soon"
+	// NotificationTypeListingExpired is sent to a seller once their listing has expired.
+	NotificationTypeListingExpired NotificationType = "listing_expired"
+	// NotificationTypeFavoritePriceDropped is sent to watchers when a listing price drops.
+	NotificationTypeFavoritePriceDropped NotificationType = "favorite_price_dropped"
+	// NotificationTypeFavoriteSold is sent to watchers when a listing they watch is sold.
+	NotificationTypeFavoriteSold NotificationType = "favorite_sold"
+	// NotificationTypeSavedSearchMatch is sent when new listings match an instant saved search.
+	NotificationTypeSavedSearchMatch NotificationType = "saved_search_match"
+	// NotificationTypeSavedSearchDigest is sent with matches of daily saved searches.
+	NotificationTypeSavedSearchDigest NotificationType = "saved_search_digest"
+	// NotificationTypeNewMessage is sent to a thread participant when they receive a message.
+	NotificationTypeNewMessage NotificationType = "new_message"
+	// NotificationTypeListingApproved is sent to a seller when their listing passes review.
+	NotificationTypeListingApproved NotificationType = "listing_approved"
+	// NotificationTypeListingModerated is sent to a seller when their listing is hidden or removed.
+	NotificationTypeListingModerated NotificationType = "listing_moderated"
+	// NotificationTypeModerationWarning is sent to a user warned by a moderator.
+	NotificationTypeModerationWarning NotificationType = "moderation_warning"
+	// NotificationTypeAccountSuspended is sent to a seller whose account has been suspended.
+	NotificationTypeAccountSuspended NotificationType = "account_suspended"
+	// NotificationTypeOrderShipped is sent to a buyer when the seller ships their order.
+	NotificationTypeOrderShipped NotificationType = "order_shipped"
+	// NotificationTypeOrderDelivered is sent to the buyer and the seller when an order is
+	// delivered.
+	NotificationTypeOrderDelivered NotificationType = "order_delivered"
+)
+
+// Notification represents a message delivered to a user.
+type Notification struct {
+	UserID string            `json:"user_id"`
+	Email  string            `json:"-"`
+	Type   NotificationType  `json:"type"`
+	Title  string            `json:"title"`
+	Body   string            `json:"body"`
+	Data   map[string]string `json:"data,omitempty"`
+}
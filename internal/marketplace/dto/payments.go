@@ -31,22 +31,26 @@ type SellerAcountLinkingSessionResponse struct {
 }
 
 // SellerAccount represents a seller account.
+// Reputation is only loaded along with listings.
 type SellerAccount struct {
-	ID        string    `json:"id,omitempty"         db:"id"`
-	Email     string    `json:"email,omitempty"      db:"email"`
-	Name      string    `json:"name,omitempty"       db:"name"`
-	Lastname  string    `json:"lastname,omitempty"   db:"lastname"`
-	Username  string    `json:"username,omitempty"   db:"username"`
-	SellerID  *string   `json:"seller_id,omitempty"  db:"seller_id"`
-	CreatedAt time.Time `json:"created_at,omitempty" db:"created_at"`
+	ID         string      `json:"id,omitempty"         db:"id"`
+	Email      string      `json:"email,omitempty"      db:"email"`
+	Name       string      `json:"name,omitempty"       db:"name"`
+	Lastname   string      `json:"lastname,omitempty"   db:"lastname"`
+	Username   string      `json:"username,omitempty"   db:"username"`
+	SellerID   *string     `json:"seller_id,omitempty"  db:"seller_id"`
+	Reputation *Reputation `json:"reputation,omitempty" db:"reputation"`
+	CreatedAt  time.Time   `json:"created_at,omitempty" db:"created_at"`
 }
 
 // CheckoutSessionRequest represents payload sent when creating checkout session.
+// ShippingOptionID is required when the listing has a shipping profile.
 type CheckoutSessionRequest struct {
-	BuyerID    string `json:"-"           validate:"required"`
-	ListingID  string `json:"-"           validate:"required"`
-	SuccessURL string `json:"success_url" validate:"required"`
-	CancelURL  string `json:"cancel_url"  validate:"required"`
+	BuyerID          string `json:"-"                  validate:"required"`
+	ListingID        string `json:"-"                  validate:"required"`
+	SuccessURL       string `json:"success_url"        validate:"required"`
+	CancelURL        string `json:"cancel_url"         validate:"required"`
+	ShippingOptionID string `json:"shipping_option_id"`
 }
 
 // CheckoutSessionResponse represents payload sent back when creating checkout session.
@@ -54,17 +58,33 @@ type CheckoutSessionResponse struct {
 	URL string `json:"url"`
 }
 
-// Payment represents payment.
+// Payment represents payment of an order. Amounts are in minor units of Currency.
+// ShippingAddress is captured by the payment provider at checkout and is only shown
+// to the seller. NeedsReview marks payments charged for a listing which couldn't be sold
+// to the buyer anymore, they wait for a refund or an admin.
 type Payment struct {
-	ID                string     `json:"id"                  db:"id"`
-	ListingID         string     `json:"listing_id"          db:"listing_id"`
-	BuyerID           string     `json:"buyer_id"            db:"buyer_id"`
-	ProviderPaymentID string     `json:"provider_payment_id" db:"provider_payment_id"`
-	Provider          Provider   `json:"provider"            db:"provider"`
-	AmountInCents     int        `json:"amount_in_cents"     db:"amount_in_cents"`
-	FeeAmountInCents  int        `json:"fee_amount_in_cents" db:"fee_amount_in_cents"`
-	Currency          string     `json:"currency"            db:"currency"`
-	CreatedAt         time.Time  `json:"created_at"          db:"created_at"`
-	UpdatedAt         time.Time  `json:"updated_at"          db:"updated_at"`
-	RefundedAt        *time.Time `json:"refunded_at"         db:"refunded_at"`
+	ID                    string           `json:"id"                         db:"id"`
+	ListingID             string           `json:"listing_id"                 db:"listing_id"`
+	BuyerID               string           `json:"buyer_id"                   db:"buyer_id"`
+	ProviderPaymentID     string           `json:"provider_payment_id"        db:"provider_payment_id"`
+	Provider              Provider         `json:"provider"                   db:"provider"`
+	AmountInCents         int              `json:"amount_in_cents"            db:"amount_in_cents"`
+	FeeAmountInCents      int              `json:"fee_amount_in_cents"        db:"fee_amount_in_cents"`
+	Currency              string           `json:"currency"                   db:"currency"`
+	ShippingOptionID      *string          `json:"shipping_option_id"         db:"shipping_option_id"`
+	ShippingMethod        *ShippingMethod  `json:"shipping_method"            db:"shipping_method"`
+	ShippingAmountInCents int              `json:"shipping_amount_in_cents"   db:"shipping_amount_in_cents"`
+	ShippingAddress       *ShippingAddress `json:"shipping_address,omitempty" db:"shipping_address"`
+	CreatedAt             time.Time        `json:"created_at"                 db:"created_at"`
+	UpdatedAt             time.Time        `json:"updated_at"                 db:"updated_at"`
+	RefundedAt            *time.Time       `json:"refunded_at"                db:"refunded_at"`
+	NeedsReview           bool             `json:"needs_review"               db:"needs_review"`
+}
+
+// Order represents a paid listing, along with its seller.
+type Order struct {
+	Payment
+
+	ListingTitle string `json:"listing_title" db:"listing_title"`
+	SellerID     string `json:"seller_id"     db:"seller_id"`
 }
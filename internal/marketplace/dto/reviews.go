@@ -0,0 +1,67 @@
+package dto
+
+import "time"
+
+// ReviewerRole represents the side of a sale the reviewer was on.
+type ReviewerRole string
+
+const (
+	// ReviewerRoleBuyer indicates a review left by the buyer about the seller.
+	ReviewerRoleBuyer ReviewerRole = "buyer"
+	// ReviewerRoleSeller indicates a review left by the seller about the buyer.
+	ReviewerRoleSeller ReviewerRole = "seller"
+)
+
+// Review represents a post-purchase review one party of a sale left about the other.
+type Review struct {
+	ID               string       `json:"id"                db:"id"`
+	PaymentID        string       `json:"payment_id"        db:"payment_id"`
+	ReviewerID       string       `json:"reviewer_id"       db:"reviewer_id"`
+	ReviewerUsername string       `json:"reviewer_username" db:"reviewer_username"`
+	RevieweeID       string       `json:"reviewee_id"       db:"reviewee_id"`
+	ReviewerRole     ReviewerRole `json:"reviewer_role"     db:"reviewer_role"`
+	Rating           int          `json:"rating"            db:"rating"`
+	Body             string       `json:"body"              db:"body"`
+	CreatedAt        time.Time    `json:"created_at"        db:"created_at"`
+	UpdatedAt        time.Time    `json:"updated_at"        db:"updated_at"`
+}
+
+// ReviewablePayment represents a payment along with both parties of the sale.
+type ReviewablePayment struct {
+	ID         string     `db:"id"`
+	BuyerID    string     `db:"buyer_id"`
+	SellerID   string     `db:"seller_id"`
+	CreatedAt  time.Time  `db:"created_at"`
+	RefundedAt *time.Time `db:"refunded_at"`
+}
+
+// CreateReviewRequest represents payload sent when reviewing the other party of a sale.
+type CreateReviewRequest struct {
+	ReviewerID string `json:"-"          validate:"required"`
+	PaymentID  string `json:"payment_id" validate:"required"`
+	Rating     int    `json:"rating"     validate:"required,min=1,max=5"`
+	Body       string `json:"body"       validate:"max=2000"`
+}
+
+// GetReviewsRequest represents payload sent when fetching reviews a user received.
+// Role filters reviews by the side the reviewer was on, e.g. buyer returns reviews
+// the user received as a seller.
+type GetReviewsRequest struct {
+	UserID string       `json:"-"     validate:"required"                     query:"-"`
+	Role   ReviewerRole `json:"role"  validate:"omitempty,oneof=buyer seller" query:"role"`
+	Limit  int          `json:"limit" validate:"omitempty,min=1,max=100"      query:"limit"`
+	Page   int          `json:"page"  validate:"omitempty,min=1"              query:"page"`
+}
+
+// Reputation represents aggregated reviews and completed sales of a seller.
+type Reputation struct {
+	Rating      *float64 `json:"rating"       db:"rating"`
+	ReviewCount int      `json:"review_count" db:"review_count"`
+	SalesCount  int      `json:"sales_count"  db:"sales_count"`
+}
+
+// GetReviewsResponse represents payload sent back when fetching reviews of a user.
+type GetReviewsResponse struct {
+	Reputation Reputation `json:"reputation"`
+	Reviews    []Review   `json:"reviews"`
+}
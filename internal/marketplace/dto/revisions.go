@@ -0,0 +1,92 @@
+package dto
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RevisionActorRole represents who edited a listing.
+type RevisionActorRole string
+
+const (
+	// RevisionActorOwner indicates an edit made by the listing owner.
+	RevisionActorOwner RevisionActorRole = "owner"
+	// RevisionActorAdmin indicates an edit made by an admin.
+	RevisionActorAdmin RevisionActorRole = "admin"
+)
+
+// FieldChange holds the value of a listing field before and after an edit.
+type FieldChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// RevisionChanges holds changed fields of a listing revision keyed by field name.
+type RevisionChanges map[string]FieldChange
+
+// ErrInvalidRevisionChangesScanType is returned if scanning json into RevisionChanges fails.
+var ErrInvalidRevisionChangesScanType = errors.New("invalid type for RevisionChanges scan")
+
+// Scan implements sql.Scanner to decode revision changes stored as JSONB.
+func (rc *RevisionChanges) Scan(value any) error {
+	if value == nil {
+		*rc = RevisionChanges{}
+
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrInvalidRevisionChangesScanType, value)
+	}
+
+	changes := RevisionChanges{}
+
+	err := json.Unmarshal(bytes, &changes)
+	if err != nil {
+		return fmt.Errorf("unmarshaling RevisionChanges dto: %w", err)
+	}
+
+	*rc = changes
+
+	return nil
+}
+
+// Value implements driver.Valuer to store revision changes as JSONB.
+func (rc RevisionChanges) Value() (driver.Value, error) {
+	bytes, err := json.Marshal(map[string]FieldChange(rc))
+	if err != nil {
+		return nil, fmt.Errorf("marshaling RevisionChanges dto: %w", err)
+	}
+
+	return bytes, nil
+}
+
+// ListingRevision represents a recorded edit of a listing. ActorID is nil when
+// the editing user was deleted.
+type ListingRevision struct {
+	ID        string            `json:"id"         db:"id"`
+	ListingID string            `json:"listing_id" db:"listing_id"`
+	ActorID   *string           `json:"actor_id"   db:"actor_id"`
+	ActorRole RevisionActorRole `json:"actor_role" db:"actor_role"`
+	Changes   RevisionChanges   `json:"changes"    db:"changes"`
+	CreatedAt time.Time         `json:"created_at" db:"created_at"`
+}
+
+// GetListingRevisionsRequest represents payload sent when fetching edit history of a listing.
+type GetListingRevisionsRequest struct {
+	ListingID string `json:"-"     validate:"required"`
+	Limit     int    `json:"limit" validate:"omitempty,min=1,max=100" query:"limit"`
+	Page      int    `json:"page"  validate:"omitempty,min=1"         query:"page"`
+}
+
+// PriceChange represents a change of listing price, shown publicly on listings.
+type PriceChange struct {
+	OldPriceInCents int       `json:"old_price_in_cents" db:"old_price_in_cents"`
+	NewPriceInCents int       `json:"new_price_in_cents" db:"new_price_in_cents"`
+	Currency        string    `json:"currency"           db:"currency"`
+	ChangedAt       time.Time `json:"changed_at"         db:"changed_at"`
+}
@@ -0,0 +1,121 @@
+package dto
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SavedSearchFrequency represents how often a user is notified about saved search matches.
+type SavedSearchFrequency string
+
+const (
+	// SavedSearchFrequencyInstant notifies about matches as soon as they are found.
+	SavedSearchFrequencyInstant SavedSearchFrequency = "instant"
+	// SavedSearchFrequencyDaily collects matches into a daily digest.
+	SavedSearchFrequencyDaily SavedSearchFrequency = "daily"
+)
+
+// SavedSearch represents a listings search saved by a user.
+type SavedSearch struct {
+	ID        string               `json:"id"         db:"id"`
+	UserID    string               `json:"user_id"    db:"user_id"`
+	Email     string               `json:"-"          db:"email"`
+	Name      string               `json:"name"       db:"name"`
+	Filters   SavedSearchFilters   `json:"filters"    db:"filters"`
+	Frequency SavedSearchFrequency `json:"frequency"  db:"frequency"`
+	CreatedAt time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time            `json:"updated_at" db:"updated_at"`
+}
+
+// SavedSearchFilters holds the filters of GetListingsRequest that are kept in a saved search.
+type SavedSearchFilters struct {
+	Category        *string                   `json:"category"`
+	Keyword         *string                   `json:"keyword"`
+	Attributes      map[string][]string       `json:"attributes"`
+	AttributeRanges map[string]AttributeRange `json:"attribute_ranges"`
+}
+
+// IsEmpty reports whether filters would match every listing.
+func (f *SavedSearchFilters) IsEmpty() bool {
+	return f.Category == nil && f.Keyword == nil &&
+		len(f.Attributes) == 0 && len(f.AttributeRanges) == 0
+}
+
+// ListingsRequest converts filters into GetListingsRequest.
+func (f *SavedSearchFilters) ListingsRequest() *GetListingsRequest {
+	return &GetListingsRequest{
+		Limit:           0,
+		Page:            0,
+		Category:        f.Category,
+		Keyword:         f.Keyword,
+		SellerID:        nil,
+		Attributes:      f.Attributes,
+		AttributeRanges: f.AttributeRanges,
+		Facets:          false,
+		Near:            "",
+		NearLatitude:    nil,
+		NearLongitude:   nil,
+		RadiusKm:        nil,
+		UserID:          "",
+	}
+}
+
+// ErrInvalidSavedSearchFiltersScanType is returned if scanning json into SavedSearchFilters fails.
+var ErrInvalidSavedSearchFiltersScanType = errors.New("invalid type for SavedSearchFilters scan")
+
+// Scan implements sql.Scanner to decode saved search filters stored as JSONB.
+func (f *SavedSearchFilters) Scan(value any) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrInvalidSavedSearchFiltersScanType, value)
+	}
+
+	err := json.Unmarshal(bytes, f)
+	if err != nil {
+		return fmt.Errorf("unmarshaling SavedSearchFilters dto: %w", err)
+	}
+
+	return nil
+}
+
+// Value implements driver.Valuer to store saved search filters as JSONB.
+func (f SavedSearchFilters) Value() (driver.Value, error) {
+	bytes, err := json.Marshal(f)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling SavedSearchFilters dto: %w", err)
+	}
+
+	return bytes, nil
+}
+
+// CreateSavedSearchRequest represents payload sent when saving a search.
+type CreateSavedSearchRequest struct {
+	UserID    string               `json:"-"         validate:"required"`
+	Name      string               `json:"name"      validate:"required,max=100"`
+	Filters   SavedSearchFilters   `json:"filters"`
+	Frequency SavedSearchFrequency `json:"frequency" validate:"required,oneof=instant daily"`
+}
+
+// UpdateSavedSearchRequest represents payload sent when updating a saved search.
+// Nil fields are left unchanged, provided filters replace all saved filters.
+type UpdateSavedSearchRequest struct {
+	ID        string                `json:"-"         validate:"required"`
+	UserID    string                `json:"-"         validate:"required"`
+	Name      *string               `json:"name"      validate:"omitempty,min=1,max=100"`
+	Filters   *SavedSearchFilters   `json:"filters"`
+	Frequency *SavedSearchFrequency `json:"frequency" validate:"omitempty,oneof=instant daily"`
+}
+
+// SavedSearchMatch represents a listing matching a saved search, along with contact info
+// of the user who saved the search.
+type SavedSearchMatch struct {
+	SavedSearchID string `db:"saved_search_id"`
+	SearchName    string `db:"search_name"`
+	UserID        string `db:"user_id"`
+	Email         string `db:"email"`
+	ListingID     string `db:"listing_id"`
+	Title         string `db:"title"`
+}
@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"mime/multipart"
+	"time"
+)
+
+// SellerProfile represents the public profile of a seller.
+type SellerProfile struct {
+	ID             string        `json:"id"              db:"id"`
+	Username       string        `json:"username"        db:"username"`
+	Bio            string        `json:"bio"             db:"bio"`
+	AvatarPath     *string       `json:"-"               db:"avatar_path"`
+	AvatarURL      string        `json:"avatar_url"      db:"-"`
+	AvatarVariants ImageVariants `json:"avatar_variants" db:"avatar_variants"`
+	Reputation     Reputation    `json:"reputation"      db:"reputation"`
+	MemberSince    time.Time     `json:"member_since"    db:"member_since"`
+}
+
+// GetSellerProfileRequest represents payload sent when fetching a seller storefront.
+// UserID is the viewer and is empty for anonymous requests.
+type GetSellerProfileRequest struct {
+	Username string `json:"-"     validate:"required"                query:"-"`
+	Limit    int    `json:"limit" validate:"omitempty,min=1,max=100" query:"limit"`
+	Page     int    `json:"page"  validate:"omitempty,min=1"         query:"page"`
+	UserID   string `json:"-"     validate:"-"                       query:"-"`
+}
+
+// GetSellerProfileResponse represents payload sent back when fetching a seller storefront.
+type GetSellerProfileResponse struct {
+	Profile  SellerProfile       `json:"profile"`
+	Listings GetListingsResponse `json:"listings"`
+}
+
+// UpdateSellerProfileRequest represents payload sent when sellers edit their profile.
+// A new avatar replaces the current one.
+type UpdateSellerProfileRequest struct {
+	UserID         string                `form:"-"      validate:"required"`
+	Bio            *string               `form:"bio"    validate:"omitempty,max=1000"`
+	AvatarPath     *string               `form:"-"`
+	AvatarVariants ImageVariants         `form:"-"`
+	FileHeader     *multipart.FileHeader `form:"avatar"`
+}
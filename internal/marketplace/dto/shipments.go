@@ -0,0 +1,68 @@
+package dto
+
+import "time"
+
+// ShipmentStatus represents the delivery progress of a shipment.
+type ShipmentStatus string
+
+const (
+	// ShipmentStatusShipped indicates that the seller handed the item to the carrier.
+	ShipmentStatusShipped ShipmentStatus = "shipped"
+	// ShipmentStatusInTransit indicates that the carrier is moving the item.
+	ShipmentStatusInTransit ShipmentStatus = "in_transit"
+	// ShipmentStatusOutForDelivery indicates that the item is on its way to the buyer.
+	ShipmentStatusOutForDelivery ShipmentStatus = "out_for_delivery"
+	// ShipmentStatusDelivered indicates that the item reached the buyer.
+	ShipmentStatusDelivered ShipmentStatus = "delivered"
+	// ShipmentStatusException indicates that the carrier couldn't deliver the item.
+	ShipmentStatusException ShipmentStatus = "exception"
+)
+
+// Shipment represents delivery of a sold item, tracked with the carrier.
+type Shipment struct {
+	ID             string          `json:"id"              db:"id"`
+	OrderID        string          `json:"order_id"        db:"payment_id"`
+	Carrier        string          `json:"carrier"         db:"carrier"`
+	TrackingNumber string          `json:"tracking_number" db:"tracking_number"`
+	Status         ShipmentStatus  `json:"status"          db:"status"`
+	ShippedAt      time.Time       `json:"shipped_at"      db:"shipped_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at"    db:"delivered_at"`
+	CheckedAt      *time.Time      `json:"-"               db:"checked_at"`
+	Events         []ShipmentEvent `json:"events"          db:"-"`
+	CreatedAt      time.Time       `json:"created_at"      db:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"      db:"updated_at"`
+}
+
+// ShipmentEvent represents a single status update of a shipment.
+type ShipmentEvent struct {
+	ID          string         `json:"id"          db:"id"`
+	ShipmentID  string         `json:"-"           db:"shipment_id"`
+	Status      ShipmentStatus `json:"status"      db:"status"`
+	Description string         `json:"description" db:"description"`
+	Location    string         `json:"location"    db:"location"`
+	OccurredAt  time.Time      `json:"occurred_at" db:"occurred_at"`
+	CreatedAt   time.Time      `json:"created_at"  db:"created_at"`
+}
+
+// ShippableOrder represents an order along with contact info of its buyer and the shipment,
+// if the item has been shipped.
+type ShippableOrder struct {
+	ID             string          `db:"id"`
+	ListingTitle   string          `db:"listing_title"`
+	BuyerID        string          `db:"buyer_id"`
+	BuyerEmail     string          `db:"buyer_email"`
+	SellerID       string          `db:"seller_id"`
+	SellerEmail    string          `db:"seller_email"`
+	ShippingMethod *ShippingMethod `db:"shipping_method"`
+	ShipmentStatus *ShipmentStatus `db:"shipment_status"`
+	RefundedAt     *time.Time      `db:"refunded_at"`
+}
+
+// CreateShipmentRequest represents payload sent when a seller ships an order. Shipping an
+// order again replaces its carrier and tracking number.
+type CreateShipmentRequest struct {
+	OrderID        string `json:"-"               validate:"required"`
+	SellerID       string `json:"-"               validate:"required"`
+	Carrier        string `json:"carrier"         validate:"required,max=50"`
+	TrackingNumber string `json:"tracking_number" validate:"required,max=100"`
+}
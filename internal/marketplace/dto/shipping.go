@@ -0,0 +1,165 @@
+package dto
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ShippingMethod represents how an item gets from the seller to the buyer.
+type ShippingMethod string
+
+const (
+	// ShippingMethodFlatRate indicates delivery to listed regions for a fixed price.
+	ShippingMethodFlatRate ShippingMethod = "flat_rate"
+	// ShippingMethodFree indicates delivery to listed regions at no cost.
+	ShippingMethodFree ShippingMethod = "free"
+	// ShippingMethodLocalPickup indicates that the buyer picks the item up from the seller.
+	ShippingMethodLocalPickup ShippingMethod = "local_pickup"
+)
+
+// ShippingOption represents a single way of shipping offered by a shipping profile.
+// Regions are ISO 3166-1 alpha-2 country codes, only flat rate options have a price.
+type ShippingOption struct {
+	ID           string         `json:"id"`
+	Method       ShippingMethod `json:"method"         validate:"required,oneof=flat_rate free local_pickup"`
+	Name         string         `json:"name"           validate:"required,max=100"`
+	Regions      []string       `json:"regions"        validate:"required_unless=Method local_pickup,excluded_if=Method local_pickup,dive,iso3166_1_alpha2"`
+	PriceInCents int            `json:"price_in_cents" validate:"required_if=Method flat_rate,excluded_unless=Method flat_rate,min=0"`
+}
+
+// ShippingOptions represents shipping options of a profile stored as JSONB.
+type ShippingOptions []ShippingOption
+
+// Find returns the option with the id, or nil if there is none.
+func (so ShippingOptions) Find(id string) *ShippingOption {
+	for i := range so {
+		if so[i].ID == id {
+			return &so[i]
+		}
+	}
+
+	return nil
+}
+
+// ErrInvalidShippingOptionsScanType is returned if scanning json into ShippingOptions fails.
+var ErrInvalidShippingOptionsScanType = errors.New("invalid type for ShippingOptions scan")
+
+// Scan implements sql.Scanner to decode shipping options stored as JSONB.
+func (so *ShippingOptions) Scan(value any) error {
+	if value == nil {
+		*so = ShippingOptions{}
+
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrInvalidShippingOptionsScanType, value)
+	}
+
+	options := ShippingOptions{}
+
+	err := json.Unmarshal(bytes, &options)
+	if err != nil {
+		return fmt.Errorf("unmarshaling ShippingOptions dto: %w", err)
+	}
+
+	*so = options
+
+	return nil
+}
+
+// Value implements driver.Valuer to store shipping options as JSONB.
+func (so ShippingOptions) Value() (driver.Value, error) {
+	if so == nil {
+		so = ShippingOptions{}
+	}
+
+	bytes, err := json.Marshal([]ShippingOption(so))
+	if err != nil {
+		return nil, fmt.Errorf("marshaling ShippingOptions dto: %w", err)
+	}
+
+	return bytes, nil
+}
+
+// ShippingProfile represents a reusable set of shipping options defined by a seller. Option
+// prices are in minor units of Currency, the profile can be used only by listings priced in it.
+type ShippingProfile struct {
+	ID        string          `json:"id"         db:"id"`
+	UserID    string          `json:"user_id"    db:"user_id"`
+	Name      string          `json:"name"       db:"name"`
+	Options   ShippingOptions `json:"options"    db:"options"`
+	Currency  string          `json:"currency"   db:"currency"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// CreateShippingProfileRequest represents payload sent when creating a shipping profile.
+type CreateShippingProfileRequest struct {
+	UserID   string          `json:"-"        validate:"required"`
+	Name     string          `json:"name"     validate:"required,max=100"`
+	Options  ShippingOptions `json:"options"  validate:"required,min=1,dive"`
+	Currency string          `json:"currency" validate:"required,len=3"`
+}
+
+// UpdateShippingProfileRequest represents payload sent when updating a shipping profile.
+// Nil fields are left unchanged, provided options replace all options of the profile. The
+// currency of a profile can't be changed.
+type UpdateShippingProfileRequest struct {
+	ID      string           `json:"-"       validate:"required"`
+	UserID  string           `json:"-"       validate:"required"`
+	Name    *string          `json:"name"    validate:"omitempty,min=1,max=100"`
+	Options *ShippingOptions `json:"options" validate:"omitempty,min=1,dive"`
+}
+
+// SetListingShippingRequest represents payload sent when choosing the shipping profile
+// of a listing. A nil ShippingProfileID removes the profile from the listing.
+type SetListingShippingRequest struct {
+	UserID            string  `json:"-"                   validate:"required"`
+	ListingID         string  `json:"-"                   validate:"required"`
+	ShippingProfileID *string `json:"shipping_profile_id"`
+}
+
+// ShippingAddress represents the delivery address captured at checkout.
+type ShippingAddress struct {
+	Name       string `json:"name"`
+	Phone      string `json:"phone,omitempty"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city"`
+	State      string `json:"state,omitempty"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+}
+
+// ErrInvalidShippingAddressScanType is returned if scanning json into ShippingAddress fails.
+var ErrInvalidShippingAddressScanType = errors.New("invalid type for ShippingAddress scan")
+
+// Scan implements sql.Scanner to decode a shipping address stored as JSONB.
+func (a *ShippingAddress) Scan(value any) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrInvalidShippingAddressScanType, value)
+	}
+
+	err := json.Unmarshal(bytes, a)
+	if err != nil {
+		return fmt.Errorf("unmarshaling ShippingAddress dto: %w", err)
+	}
+
+	return nil
+}
+
+// Value implements driver.Valuer to store a shipping address as JSONB.
+func (a ShippingAddress) Value() (driver.Value, error) {
+	bytes, err := json.Marshal(a)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling ShippingAddress dto: %w", err)
+	}
+
+	return bytes, nil
+}
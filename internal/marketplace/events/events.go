@@ -0,0 +1,154 @@
+// Package events handles publishing real-time events to connected users.
+package events
+
+import (
+	"context"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/pkg/generate"
+	"sync"
+	"time"
+)
+
+const subscriptionBufferSize = 16
+
+// Publisher defines methods for publishing events to users.
+type Publisher interface {
+	Publish(ctx context.Context, e *dto.Event) error
+}
+
+// NewEvent creates an event with a unique id, the id is used by clients to resume a stream.
+func NewEvent(userID string, eventType dto.EventType, data map[string]string) *dto.Event {
+	return &dto.Event{
+		ID:        generate.ID("evt"),
+		UserID:    userID,
+		Type:      eventType,
+		Data:      data,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Subscription receives events published to a single user.
+//
+// Events is closed when the subscription is canceled or when the subscriber falls
+// too far behind, clients are expected to reconnect and resume from the last event.
+type Subscription struct {
+	Events <-chan dto.Event
+	userID string
+	ch     chan dto.Event
+}
+
+// Hub fans out events to subscribers in this process and keeps a short replay buffer
+// of recent events so reconnecting clients don't miss anything.
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[string]map[*Subscription]struct{}
+	buffer      []dto.Event
+	next        int
+	full        bool
+}
+
+// NewHub creates a new Hub which remembers up to bufferSize most recent events.
+func NewHub(bufferSize int) *Hub {
+	return &Hub{
+		mu:          sync.Mutex{},
+		subscribers: make(map[string]map[*Subscription]struct{}),
+		buffer:      make([]dto.Event, max(bufferSize, 1)),
+		next:        0,
+		full:        false,
+	}
+}
+
+// Publish delivers the event to subscribers of its user in this process.
+func (h *Hub) Publish(_ context.Context, e *dto.Event) error {
+	h.Dispatch(e)
+
+	return nil
+}
+
+// Dispatch buffers the event and delivers it to subscribers of its user.
+// Subscribers that can't keep up are dropped.
+func (h *Hub) Dispatch(e *dto.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.buffer[h.next] = *e
+	h.next = (h.next + 1) % len(h.buffer)
+	h.full = h.full || h.next == 0
+
+	for sub := range h.subscribers[e.UserID] {
+		select {
+		case sub.ch <- *e:
+		default:
+			h.remove(sub)
+		}
+	}
+}
+
+// Subscribe subscribes to events of a user. If lastEventID is still in the replay buffer,
+// events of the user published after it are returned for replay.
+func (h *Hub) Subscribe(userID, lastEventID string) (*Subscription, []dto.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ch := make(chan dto.Event, subscriptionBufferSize)
+	sub := &Subscription{Events: ch, userID: userID, ch: ch}
+
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[*Subscription]struct{})
+	}
+
+	h.subscribers[userID][sub] = struct{}{}
+
+	return sub, h.replay(userID, lastEventID)
+}
+
+// Unsubscribe cancels the subscription and closes its Events channel.
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(sub)
+}
+
+func (h *Hub) remove(sub *Subscription) {
+	subs, ok := h.subscribers[sub.userID]
+	if !ok {
+		return
+	}
+
+	if _, ok = subs[sub]; !ok {
+		return
+	}
+
+	delete(subs, sub)
+	close(sub.ch)
+
+	if len(subs) == 0 {
+		delete(h.subscribers, sub.userID)
+	}
+}
+
+func (h *Hub) replay(userID, lastEventID string) []dto.Event {
+	if lastEventID == "" {
+		return nil
+	}
+
+	ordered := h.buffer[:h.next]
+	if h.full {
+		ordered = append(append([]dto.Event{}, h.buffer[h.next:]...), h.buffer[:h.next]...)
+	}
+
+	var events []dto.Event
+
+	found := false
+
+	for _, e := range ordered {
+		if found && e.UserID == userID {
+			events = append(events, e)
+		}
+
+		found = found || e.ID == lastEventID
+	}
+
+	return events
+}
@@ -0,0 +1,90 @@
+package events
+
+import (
+	"context"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHub_PublishDeliversToUserSubscribers(t *testing.T) {
+	t.Parallel()
+
+	hub := NewHub(10)
+
+	sub, replay := hub.Subscribe("user_1", "")
+	require.Empty(t, replay)
+
+	other, _ := hub.Subscribe("user_2", "")
+
+	e := NewEvent("user_1", dto.EventTypeNewMessage, map[string]string{"thread_id": "t1"})
+	require.NoError(t, hub.Publish(context.Background(), e))
+
+	got := <-sub.Events
+	assert.Equal(t, e.ID, got.ID)
+	assert.Equal(t, "t1", got.Data["thread_id"])
+	assert.Empty(t, other.Events)
+}
+
+func TestHub_SubscribeReplaysEventsAfterLastEventID(t *testing.T) {
+	t.Parallel()
+
+	hub := NewHub(10)
+
+	first := NewEvent("user_1", dto.EventTypeListingSold, nil)
+	hub.Dispatch(first)
+	hub.Dispatch(NewEvent("user_2", dto.EventTypeListingSold, nil))
+	second := NewEvent("user_1", dto.EventTypePaymentRefunded, nil)
+	hub.Dispatch(second)
+
+	_, replay := hub.Subscribe("user_1", first.ID)
+	require.Len(t, replay, 1)
+	assert.Equal(t, second.ID, replay[0].ID)
+
+	_, replay = hub.Subscribe("user_1", "evt_unknown")
+	assert.Empty(t, replay)
+}
+
+func TestHub_ReplayBufferKeepsMostRecentEvents(t *testing.T) {
+	t.Parallel()
+
+	hub := NewHub(2)
+
+	published := make([]*dto.Event, 0, 4)
+
+	for range 4 {
+		e := NewEvent("user_1", dto.EventTypeNewMessage, nil)
+		hub.Dispatch(e)
+		published = append(published, e)
+	}
+
+	_, replay := hub.Subscribe("user_1", published[0].ID)
+	assert.Empty(t, replay, "evicted event id can't be resumed from")
+
+	_, replay = hub.Subscribe("user_1", published[2].ID)
+	require.Len(t, replay, 1)
+	assert.Equal(t, published[3].ID, replay[0].ID)
+}
+
+func TestHub_SlowSubscriberIsDropped(t *testing.T) {
+	t.Parallel()
+
+	hub := NewHub(1)
+
+	sub, _ := hub.Subscribe("user_1", "")
+
+	for range subscriptionBufferSize + 1 {
+		hub.Dispatch(NewEvent("user_1", dto.EventTypeNewMessage, nil))
+	}
+
+	received := 0
+	for range sub.Events {
+		received++
+	}
+
+	assert.Equal(t, subscriptionBufferSize, received)
+
+	hub.Unsubscribe(sub)
+}
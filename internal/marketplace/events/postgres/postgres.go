@@ -0,0 +1,110 @@
+// Package postgres implements an event publisher which fans out events to every
+// application replica through Postgres LISTEN/NOTIFY.
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/internal/marketplace/events"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const (
+	channel              = "marketplace_events"
+	minReconnectInterval = time.Second
+	maxReconnectInterval = time.Minute
+	pingInterval         = 90 * time.Second
+)
+
+type postgresPublisher struct {
+	db     *sqlx.DB
+	dbURI  string
+	hub    *events.Hub
+	logger *slog.Logger
+}
+
+// NewPostgresPublisher creates a new publisher which sends events with NOTIFY and
+// dispatches events received with LISTEN to hub.
+func NewPostgresPublisher( //nolint:revive
+	db *sqlx.DB,
+	dbURI string,
+	hub *events.Hub,
+	logger *slog.Logger,
+) *postgresPublisher {
+	return &postgresPublisher{
+		db:     db,
+		dbURI:  dbURI,
+		hub:    hub,
+		logger: logger,
+	}
+}
+
+// Publish sends the event to every replica listening on the events channel.
+func (p *postgresPublisher) Publish(ctx context.Context, e *dto.Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshaling event: %w", err)
+	}
+
+	_, err = p.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", channel, string(payload))
+	if err != nil {
+		return fmt.Errorf("notifying event: %w", err)
+	}
+
+	return nil
+}
+
+// Listen dispatches events received on the events channel to the hub until ctx is canceled.
+func (p *postgresPublisher) Listen(ctx context.Context) error {
+	listener := pq.NewListener(p.dbURI, minReconnectInterval, maxReconnectInterval,
+		func(ev pq.ListenerEventType, err error) {
+			if err != nil {
+				p.logger.Error("events listener connection problem", "event", ev, "error", err)
+			}
+		},
+	)
+	defer listener.Close() //nolint:errcheck
+
+	err := listener.Listen(channel)
+	if err != nil {
+		return fmt.Errorf("listening on events channel: %w", err)
+	}
+
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n := <-listener.Notify:
+			// nil notification is sent after the connection was re-established
+			if n == nil {
+				continue
+			}
+
+			p.dispatch(n.Extra)
+		case <-ticker.C:
+			go func() { _ = listener.Ping() }()
+		}
+	}
+}
+
+func (p *postgresPublisher) dispatch(payload string) {
+	var e dto.Event
+
+	err := json.Unmarshal([]byte(payload), &e)
+	if err != nil {
+		p.logger.Error("failed to decode event", "error", err)
+
+		return
+	}
+
+	p.hub.Dispatch(&e)
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	attributeFilterPrefix = "attr."
+	attributeMinSuffix    = ".min"
+	attributeMaxSuffix    = ".max"
+)
+
+var errInvalidAttributeFilter = errors.New("invalid attribute filter")
+
+// attributeFilters parses attribute filters from query parameters. attr.<key>=a,b matches
+// listings with any of the values, attr.<key>.min and attr.<key>.max limit numeric values.
+func attributeFilters(
+	params url.Values,
+) (map[string][]string, map[string]dto.AttributeRange, error) {
+	values := map[string][]string{}
+	ranges := map[string]dto.AttributeRange{}
+
+	for name, param := range params {
+		key, ok := strings.CutPrefix(name, attributeFilterPrefix)
+		if !ok || len(param) == 0 {
+			continue
+		}
+
+		switch {
+		case strings.HasSuffix(key, attributeMinSuffix):
+			key = strings.TrimSuffix(key, attributeMinSuffix)
+
+			bound, err := parseAttributeBound(name, param[0])
+			if err != nil {
+				return nil, nil, err
+			}
+
+			r := ranges[key]
+			r.Min = bound
+			ranges[key] = r
+		case strings.HasSuffix(key, attributeMaxSuffix):
+			key = strings.TrimSuffix(key, attributeMaxSuffix)
+
+			bound, err := parseAttributeBound(name, param[0])
+			if err != nil {
+				return nil, nil, err
+			}
+
+			r := ranges[key]
+			r.Max = bound
+			ranges[key] = r
+		default:
+			for _, p := range param {
+				values[key] = append(values[key], strings.Split(p, ",")...)
+			}
+		}
+	}
+
+	return values, ranges, nil
+}
+
+func parseAttributeBound(name, value string) (*float64, error) {
+	bound, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s must be a number", errInvalidAttributeFilter, name)
+	}
+
+	return &bound, nil
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"golang-connect-marketplace/internal/auth/middleware"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/internal/marketplace/events"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const lastEventIDHeader = "Last-Event-ID"
+
+// EventsHandler streams real-time events to users over Server-Sent Events.
+type EventsHandler struct {
+	hub       *events.Hub
+	heartbeat time.Duration
+}
+
+// NewEventsHandler creates a new Handler for streaming events. A comment line is sent every
+// heartbeat to keep idle connections open through proxies.
+func NewEventsHandler(hub *events.Hub, heartbeat time.Duration) *EventsHandler {
+	return &EventsHandler{
+		hub:       hub,
+		heartbeat: heartbeat,
+	}
+}
+
+// HandleStream streams events of the authenticated user until the client disconnects.
+// Events published after the one in Last-Event-ID header are replayed first, if still buffered.
+func (h *EventsHandler) HandleStream(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	sub, replay := h.hub.Subscribe(user.ID, c.Request().Header.Get(lastEventIDHeader))
+	defer h.hub.Unsubscribe(sub)
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	for i := range replay {
+		err = writeEvent(res, &replay[i])
+		if err != nil {
+			return nil
+		}
+	}
+
+	heartbeat := h.heartbeat
+	if heartbeat <= 0 {
+		heartbeat = time.Minute
+	}
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case e, ok := <-sub.Events:
+			if !ok {
+				return nil
+			}
+
+			err = writeEvent(res, &e)
+		case <-ticker.C:
+			_, err = fmt.Fprint(res, ": ping\n\n")
+			res.Flush()
+		}
+
+		if err != nil {
+			return nil
+		}
+	}
+}
+
+func writeEvent(res *echo.Response, e *dto.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshaling event: %w", err)
+	}
+
+	_, err = fmt.Fprintf(res, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+	if err != nil {
+		return fmt.Errorf("writing event: %w", err)
+	}
+
+	res.Flush()
+
+	return nil
+}
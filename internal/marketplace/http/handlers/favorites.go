@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"errors"
+	"golang-connect-marketplace/internal/auth/middleware"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/internal/marketplace/services"
+	r "golang-connect-marketplace/pkg/responses"
+	"golang-connect-marketplace/pkg/validation"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HandleAddFavorite handles requests to add a listing to user's favorites.
+func (h *ListingsHandler) HandleAddFavorite(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	reqDto := dto.FavoriteRequest{UserID: user.ID, ListingID: c.Param(listingIDParamName)}
+
+	err = h.svc.AddFavorite(c.Request().Context(), &reqDto)
+	if err != nil {
+		if errors.Is(err, services.ErrListingIsNotOpen) {
+			return r.JSONError(c, err.Error(), err, http.StatusConflict)
+		}
+
+		return r.JSONError(c, "failed to add favorite", err, http.StatusInternalServerError)
+	}
+
+	return r.JSONSuccess(c, "added listing to favorites", nil)
+}
+
+// HandleRemoveFavorite handles requests to remove a listing from user's favorites.
+func (h *ListingsHandler) HandleRemoveFavorite(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	reqDto := dto.FavoriteRequest{UserID: user.ID, ListingID: c.Param(listingIDParamName)}
+
+	err = h.svc.RemoveFavorite(c.Request().Context(), &reqDto)
+	if err != nil {
+		return r.JSONError(c, "failed to remove favorite", err, http.StatusInternalServerError)
+	}
+
+	return r.JSONSuccess(c, "removed listing from favorites", nil)
+}
+
+// HandleGetFavorites handles requests to fetch favorite listings of the authenticated user.
+func (h *ListingsHandler) HandleGetFavorites(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	var reqDto dto.GetFavoritesRequest
+
+	reqDto.UserID = user.ID
+
+	err = validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	resp, err := h.svc.GetFavorites(c.Request().Context(), &reqDto)
+	if err != nil {
+		return r.JSONError(c, "failed to fetch favorites", err, http.StatusInternalServerError)
+	}
+
+	return r.JSONSuccess(c, "fetched favorites", resp)
+}
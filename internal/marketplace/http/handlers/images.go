@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"golang-connect-marketplace/internal/marketplace/storage"
+	r "golang-connect-marketplace/pkg/responses"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ImagesHandler serves images stored locally through signed URLs.
+type ImagesHandler struct {
+	verifier storage.SignedURLVerifier
+}
+
+// NewImagesHandler creates a new Handler for serving signed image URLs.
+func NewImagesHandler(verifier storage.SignedURLVerifier) *ImagesHandler {
+	return &ImagesHandler{
+		verifier: verifier,
+	}
+}
+
+// HandleGetImage verifies the url signature and serves the image file.
+func (h *ImagesHandler) HandleGetImage(c echo.Context) error {
+	key := c.Param("*")
+
+	path, err := h.verifier.VerifySignedURL(
+		key,
+		c.QueryParam("expires"),
+		c.QueryParam("signature"),
+	)
+	if err != nil {
+		return r.JSONError(c, "invalid or expired image url", err, http.StatusForbidden)
+	}
+
+	c.Response().Header().Set("Cache-Control", "private, max-age=300")
+
+	return c.File(path)
+}
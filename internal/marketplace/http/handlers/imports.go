@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"golang-connect-marketplace/internal/auth/middleware"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/internal/marketplace/imports"
+	"golang-connect-marketplace/internal/marketplace/services"
+	r "golang-connect-marketplace/pkg/responses"
+	"golang-connect-marketplace/pkg/validation"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+const importIDParamName = "import_id"
+
+// HandleImportListings handles uploads of CSV or NDJSON files to create listings in bulk.
+func (h *ListingsHandler) HandleImportListings(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	var reqDto dto.CreateImportRequest
+
+	reqDto.UserID = user.ID
+
+	err = validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	resp, err := h.svc.CreateImport(c.Request().Context(), &reqDto)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidImportFile) ||
+			errors.Is(err, services.ErrTooManyImportRows) {
+			return r.JSONError(c, err.Error(), err)
+		}
+
+		return r.JSONError(c, "failed to import listings", err, http.StatusInternalServerError)
+	}
+
+	return r.JSONSuccess(c, "accepted listings import", resp)
+}
+
+// HandleGetImport handles requests to fetch status and row results of an import.
+func (h *ListingsHandler) HandleGetImport(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.svc.GetImport(c.Request().Context(), c.Param(importIDParamName), user.ID)
+	if err != nil {
+		if errors.Is(err, services.ErrImportNotFound) {
+			return r.JSONError(c, err.Error(), err, http.StatusNotFound)
+		}
+
+		return r.JSONError(c, "failed to fetch import", err, http.StatusInternalServerError)
+	}
+
+	return r.JSONSuccess(c, "fetched import", resp)
+}
+
+// HandleExportListings handles requests to download all listings of the authenticated user
+// as a CSV or NDJSON file, which can be edited and imported again.
+func (h *ListingsHandler) HandleExportListings(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	var reqDto dto.ExportListingsRequest
+
+	reqDto.UserID = user.ID
+
+	err = validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	if reqDto.Format == "" {
+		reqDto.Format = dto.ImportFormatCSV
+	}
+
+	rows, err := h.svc.ExportListings(c.Request().Context(), &reqDto)
+	if err != nil {
+		return r.JSONError(c, "failed to export listings", err, http.StatusInternalServerError)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, imports.ContentType(reqDto.Format))
+	c.Response().Header().Set(
+		echo.HeaderContentDisposition,
+		fmt.Sprintf("attachment; filename=%q", "listings."+string(reqDto.Format)),
+	)
+	c.Response().WriteHeader(http.StatusOK)
+
+	return imports.Write(reqDto.Format, c.Response(), rows)
+}
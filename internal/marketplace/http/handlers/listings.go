@@ -6,6 +6,7 @@ import (
 	"golang-connect-marketplace/internal/auth/middleware"
 	"golang-connect-marketplace/internal/marketplace/dto"
 	"golang-connect-marketplace/internal/marketplace/services"
+	"golang-connect-marketplace/pkg/imaging"
 	r "golang-connect-marketplace/pkg/responses"
 	"golang-connect-marketplace/pkg/validation"
 	"net/http"
@@ -13,7 +14,11 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-const listingIDParamName = "listing_id"
+const (
+	categoryIDParamName = "category_id"
+	listingIDParamName  = "listing_id"
+	imageIDParamName    = "image_id"
+)
 
 // ListingsHandler handles listings-related HTTP requests.
 type ListingsHandler struct {
@@ -38,7 +43,7 @@ func (h *ListingsHandler) HandleCreateCategory(c echo.Context) error {
 
 	resp, err := h.svc.CreateCategory(c.Request().Context(), &reqDto)
 	if err != nil {
-		return r.JSONError(c, "failed to create category", err)
+		return categoryError(c, "failed to create category", err)
 	}
 
 	return r.JSONSuccess(c, "created new category", resp)
@@ -54,6 +59,64 @@ func (h *ListingsHandler) HandleGetCategories(c echo.Context) error {
 	return r.JSONSuccess(c, "fetched categories", resp)
 }
 
+// HandleUpdateCategory handles requests to update a category.
+func (h *ListingsHandler) HandleUpdateCategory(c echo.Context) error {
+	var reqDto dto.UpdateCategoryRequest
+
+	reqDto.ID = c.Param(categoryIDParamName)
+
+	err := validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	resp, err := h.svc.UpdateCategory(c.Request().Context(), &reqDto)
+	if err != nil {
+		return categoryError(c, "failed to update category", err)
+	}
+
+	return r.JSONSuccess(c, "updated category", resp)
+}
+
+// HandleDeleteCategory handles requests to delete a category.
+func (h *ListingsHandler) HandleDeleteCategory(c echo.Context) error {
+	err := h.svc.DeleteCategory(c.Request().Context(), c.Param(categoryIDParamName))
+	if err != nil {
+		return categoryError(c, "failed to delete category", err)
+	}
+
+	return r.JSONSuccess(c, "deleted category", nil)
+}
+
+// HandleGetCategoryAttributes handles requests to fetch attribute schema of a category.
+func (h *ListingsHandler) HandleGetCategoryAttributes(c echo.Context) error {
+	resp, err := h.svc.GetCategoryAttributes(c.Request().Context(), c.Param(categoryIDParamName))
+	if err != nil {
+		return categoryError(c, "failed to fetch category attributes", err)
+	}
+
+	return r.JSONSuccess(c, "fetched category attributes", resp)
+}
+
+// HandleSetCategoryAttributes handles requests to replace attribute schema of a category.
+func (h *ListingsHandler) HandleSetCategoryAttributes(c echo.Context) error {
+	var reqDto dto.SetCategoryAttributesRequest
+
+	reqDto.CategoryID = c.Param(categoryIDParamName)
+
+	err := validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	resp, err := h.svc.SetCategoryAttributes(c.Request().Context(), &reqDto)
+	if err != nil {
+		return categoryError(c, "failed to set category attributes", err)
+	}
+
+	return r.JSONSuccess(c, "updated category attributes", resp)
+}
+
 // HandleCreateListing handles requests to create new listing.
 func (h *ListingsHandler) HandleCreateListing(c echo.Context) error {
 	userClaims, err := middleware.GetUserFromContext(c)
@@ -70,6 +133,20 @@ func (h *ListingsHandler) HandleCreateListing(c echo.Context) error {
 
 	resp, err := h.svc.CreateListing(c.Request().Context(), userClaims, &reqDto)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidAttributes) ||
+			errors.Is(err, services.ErrCategoryNotFound) ||
+			errors.Is(err, services.ErrListingRejected) ||
+			errors.Is(err, services.ErrShippingProfileNotFound) ||
+			errors.Is(err, services.ErrShippingCurrencyMismatch) ||
+			errors.Is(err, services.ErrUnsupportedCurrency) ||
+			errors.Is(err, services.ErrPriceBelowMinimum) {
+			return r.JSONError(c, err.Error(), err)
+		}
+
+		if errors.Is(err, services.ErrUserSuspended) {
+			return r.JSONError(c, err.Error(), err, http.StatusForbidden)
+		}
+
 		return r.JSONError(c, "failed to create listing", err, http.StatusInternalServerError)
 	}
 
@@ -105,6 +182,10 @@ func (h *ListingsHandler) HandleAddImages(c echo.Context) error {
 			return r.JSONError(c, "listing has too many images", err)
 		}
 
+		if errors.Is(err, imaging.ErrInvalidImage) {
+			return imageValidationError(c, err)
+		}
+
 		return r.JSONError(c, "failed to add images", err, http.StatusInternalServerError)
 	}
 
@@ -142,6 +223,57 @@ func (h *ListingsHandler) HandleDeleteImages(c echo.Context) error {
 	return r.JSONSuccess(c, "deleted image from listing", resp)
 }
 
+// HandleReorderImages handles changing the order and primary image of listing images.
+func (h *ListingsHandler) HandleReorderImages(c echo.Context) error {
+	userClaims, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	var reqDto dto.ReorderImagesRequest
+
+	reqDto.UserID = userClaims.ID
+	reqDto.ListingID = c.Param(listingIDParamName)
+
+	err = validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	resp, err := h.svc.ReorderImages(c.Request().Context(), &reqDto)
+	if err != nil {
+		return imageUpdateError(c, "failed to reorder images", err)
+	}
+
+	return r.JSONSuccess(c, "reordered listing images", resp)
+}
+
+// HandleUpdateImage handles updating alt text and primary flag of a listing image.
+func (h *ListingsHandler) HandleUpdateImage(c echo.Context) error {
+	userClaims, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	var reqDto dto.UpdateImageRequest
+
+	reqDto.UserID = userClaims.ID
+	reqDto.ListingID = c.Param(listingIDParamName)
+	reqDto.ImageID = c.Param(imageIDParamName)
+
+	err = validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	resp, err := h.svc.UpdateImage(c.Request().Context(), &reqDto)
+	if err != nil {
+		return imageUpdateError(c, "failed to update image", err)
+	}
+
+	return r.JSONSuccess(c, "updated listing image", resp)
+}
+
 // HandleGetListings handles requests to get a list of listings.
 func (h *ListingsHandler) HandleGetListings(c echo.Context) error {
 	var reqDto dto.GetListingsRequest
@@ -151,6 +283,20 @@ func (h *ListingsHandler) HandleGetListings(c echo.Context) error {
 		return r.JSONError(c, err.Error(), err)
 	}
 
+	reqDto.Attributes, reqDto.AttributeRanges, err = attributeFilters(c.QueryParams())
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	reqDto.NearLatitude, reqDto.NearLongitude, err = nearLocation(&reqDto)
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	if user := middleware.LookupUserFromContext(c); user != nil {
+		reqDto.UserID = user.ID
+	}
+
 	resp, err := h.svc.GetListings(c.Request().Context(), &reqDto)
 	if err != nil {
 		return r.JSONError(c, "failed to fetch listings", err)
@@ -163,8 +309,17 @@ func (h *ListingsHandler) HandleGetListings(c echo.Context) error {
 func (h *ListingsHandler) HandleGetListing(c echo.Context) error {
 	listingID := c.Param(listingIDParamName)
 
-	resp, err := h.svc.GetListingByID(c.Request().Context(), listingID)
+	var userID string
+	if user := middleware.LookupUserFromContext(c); user != nil {
+		userID = user.ID
+	}
+
+	resp, err := h.svc.GetListingByID(c.Request().Context(), listingID, userID)
 	if err != nil {
+		if errors.Is(err, services.ErrListingNotFound) {
+			return r.JSONError(c, err.Error(), err, http.StatusNotFound)
+		}
+
 		return r.JSONError(c, "failed to fetch listing", err, http.StatusInternalServerError)
 	}
 
@@ -195,8 +350,95 @@ func (h *ListingsHandler) HandleUpdateListing(c echo.Context) error {
 			return r.JSONError(c, "forbidden", err, http.StatusForbidden)
 		}
 
+		if errors.Is(err, services.ErrIllegalStatusTransition) {
+			return r.JSONError(c, err.Error(), err, http.StatusConflict)
+		}
+
+		if errors.Is(err, services.ErrInvalidAttributes) ||
+			errors.Is(err, services.ErrCategoryNotFound) ||
+			errors.Is(err, services.ErrListingRejected) ||
+			errors.Is(err, services.ErrShippingCurrencyMismatch) ||
+			errors.Is(err, services.ErrUnsupportedCurrency) ||
+			errors.Is(err, services.ErrPriceBelowMinimum) {
+			return r.JSONError(c, err.Error(), err)
+		}
+
 		return r.JSONError(c, "failed to update listing", err)
 	}
 
 	return r.JSONSuccess(c, "updated listing", resp)
 }
+
+// HandleRenewListing handles requests to renew an open or expired listing.
+func (h *ListingsHandler) HandleRenewListing(c echo.Context) error {
+	userClaims, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	var reqDto dto.RenewListingRequest
+
+	reqDto.UserID = userClaims.ID
+	reqDto.ListingID = c.Param(listingIDParamName)
+
+	err = validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	resp, err := h.svc.RenewListing(c.Request().Context(), &reqDto)
+	if err != nil {
+		if errors.Is(err, services.ErrForbidden) {
+			return r.JSONError(c, "forbidden", err, http.StatusForbidden)
+		}
+
+		if errors.Is(err, services.ErrListingCantBeRenewed) {
+			return r.JSONError(c, err.Error(), err, http.StatusConflict)
+		}
+
+		return r.JSONError(c, "failed to renew listing", err)
+	}
+
+	return r.JSONSuccess(c, "renewed listing", resp)
+}
+
+func imageValidationError(c echo.Context, err error) error {
+	status := http.StatusBadRequest
+	if errors.Is(err, imaging.ErrFileTooLarge) {
+		status = http.StatusRequestEntityTooLarge
+	}
+
+	return r.JSONError(c, err.Error(), err, status)
+}
+
+func imageUpdateError(c echo.Context, msg string, err error) error {
+	switch {
+	case errors.Is(err, services.ErrForbidden):
+		return r.JSONError(c, "forbidden", err, http.StatusForbidden)
+	case errors.Is(err, services.ErrImageDoesntExist),
+		errors.Is(err, services.ErrImageOrderMismatch),
+		errors.Is(err, services.ErrListingIsNotOpen):
+		return r.JSONError(c, err.Error(), err)
+	default:
+		return r.JSONError(c, msg, err, http.StatusInternalServerError)
+	}
+}
+
+func categoryError(c echo.Context, msg string, err error) error {
+	switch {
+	case errors.Is(err, services.ErrCategoryNotFound):
+		return r.JSONError(c, err.Error(), err, http.StatusNotFound)
+	case errors.Is(err, services.ErrCategoryHasChildren),
+		errors.Is(err, services.ErrCategoryConflict):
+		return r.JSONError(c, err.Error(), err, http.StatusConflict)
+	case errors.Is(err, services.ErrInvalidCategoryParent),
+		errors.Is(err, services.ErrEmptyCategorySlug),
+		errors.Is(err, services.ErrCategoryTooDeep),
+		errors.Is(err, services.ErrInvalidAttributeSchema):
+		return r.JSONError(c, err.Error(), err)
+	case errors.Is(err, imaging.ErrInvalidImage):
+		return imageValidationError(c, err)
+	default:
+		return r.JSONError(c, msg, err)
+	}
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"golang-connect-marketplace/internal/auth/middleware"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/internal/marketplace/services"
+	r "golang-connect-marketplace/pkg/responses"
+	"golang-connect-marketplace/pkg/validation"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+var errInvalidNearFilter = errors.New("invalid near filter")
+
+// HandleSetListingLocation handles requests from listing owners to set or remove the pickup
+// location of a listing.
+func (h *ListingsHandler) HandleSetListingLocation(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	var reqDto dto.SetListingLocationRequest
+
+	reqDto.UserID = user.ID
+	reqDto.ListingID = c.Param(listingIDParamName)
+
+	err = validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	resp, err := h.svc.SetListingLocation(c.Request().Context(), &reqDto)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrForbidden):
+			return r.JSONError(c, "forbidden", err, http.StatusForbidden)
+		case errors.Is(err, services.ErrListingNotFound):
+			return r.JSONError(c, err.Error(), err, http.StatusNotFound)
+		default:
+			return r.JSONError(
+				c,
+				"failed to set listing location",
+				err,
+				http.StatusInternalServerError,
+			)
+		}
+	}
+
+	return r.JSONSuccess(c, "set listing location", resp)
+}
+
+// nearLocation parses the near filter given as "lat,lng". An empty filter returns nil
+// coordinates, radius_km is only accepted along with it.
+func nearLocation(req *dto.GetListingsRequest) (*float64, *float64, error) {
+	if req.Near == "" {
+		if req.RadiusKm != nil {
+			return nil, nil, fmt.Errorf("%w: radius_km requires near", errInvalidNearFilter)
+		}
+
+		return nil, nil, nil
+	}
+
+	latValue, lngValue, ok := strings.Cut(req.Near, ",")
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: near must be lat,lng", errInvalidNearFilter)
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latValue), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return nil, nil, fmt.Errorf("%w: latitude must be between -90 and 90", errInvalidNearFilter)
+	}
+
+	lng, err := strconv.ParseFloat(strings.TrimSpace(lngValue), 64)
+	if err != nil || lng < -180 || lng > 180 {
+		return nil, nil, fmt.Errorf(
+			"%w: longitude must be between -180 and 180",
+			errInvalidNearFilter,
+		)
+	}
+
+	return &lat, &lng, nil
+}
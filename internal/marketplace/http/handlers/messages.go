@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"errors"
+	"golang-connect-marketplace/internal/auth/middleware"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/internal/marketplace/services"
+	r "golang-connect-marketplace/pkg/responses"
+	"golang-connect-marketplace/pkg/validation"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	threadIDParamName = "thread_id"
+	userIDParamName   = "user_id"
+)
+
+// MessagesHandler handles buyer-seller messaging HTTP requests.
+type MessagesHandler struct {
+	svc *services.MessagesService
+}
+
+// NewMessagesHandler creates a new Handler for handling messaging requests.
+func NewMessagesHandler(svc *services.MessagesService) *MessagesHandler {
+	return &MessagesHandler{
+		svc: svc,
+	}
+}
+
+// HandleSendListingMessage handles requests from buyers to message the seller of a listing.
+func (h *MessagesHandler) HandleSendListingMessage(c echo.Context) error {
+	return h.sendMessage(c, dto.SendMessageRequest{
+		SenderID:  "",
+		ListingID: c.Param(listingIDParamName),
+		ThreadID:  "",
+		Body:      "",
+	})
+}
+
+// HandleSendThreadMessage handles requests to reply in an existing thread.
+func (h *MessagesHandler) HandleSendThreadMessage(c echo.Context) error {
+	return h.sendMessage(c, dto.SendMessageRequest{
+		SenderID:  "",
+		ListingID: "",
+		ThreadID:  c.Param(threadIDParamName),
+		Body:      "",
+	})
+}
+
+// HandleGetThreads handles requests to fetch threads of the authenticated user.
+func (h *MessagesHandler) HandleGetThreads(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.svc.GetThreads(c.Request().Context(), user.ID)
+	if err != nil {
+		return r.JSONError(c, "failed to fetch threads", err, http.StatusInternalServerError)
+	}
+
+	return r.JSONSuccess(c, "fetched threads", resp)
+}
+
+// HandleGetThread handles requests to fetch messages of a thread.
+func (h *MessagesHandler) HandleGetThread(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	var reqDto dto.GetThreadRequest
+
+	reqDto.ThreadID = c.Param(threadIDParamName)
+
+	err = validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	resp, err := h.svc.GetThread(c.Request().Context(), &reqDto, user)
+	if err != nil {
+		return messagesError(c, "failed to fetch thread", err)
+	}
+
+	return r.JSONSuccess(c, "fetched thread", resp)
+}
+
+// HandleGetUnreadCount handles requests to count unread messages of the authenticated user.
+func (h *MessagesHandler) HandleGetUnreadCount(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.svc.CountUnreadMessages(c.Request().Context(), user.ID)
+	if err != nil {
+		return r.JSONError(
+			c, "failed to count unread messages", err, http.StatusInternalServerError,
+		)
+	}
+
+	return r.JSONSuccess(c, "counted unread messages", resp)
+}
+
+// HandleBlockUser handles requests to block messages from a user.
+func (h *MessagesHandler) HandleBlockUser(c echo.Context) error {
+	reqDto, err := blockUserRequest(c)
+	if err != nil {
+		return err
+	}
+
+	err = h.svc.BlockUser(c.Request().Context(), reqDto)
+	if err != nil {
+		return r.JSONError(c, "failed to block user", err, http.StatusInternalServerError)
+	}
+
+	return r.JSONSuccess(c, "blocked user", nil)
+}
+
+// HandleUnblockUser handles requests to unblock a user.
+func (h *MessagesHandler) HandleUnblockUser(c echo.Context) error {
+	reqDto, err := blockUserRequest(c)
+	if err != nil {
+		return err
+	}
+
+	err = h.svc.UnblockUser(c.Request().Context(), reqDto)
+	if err != nil {
+		return r.JSONError(c, "failed to unblock user", err, http.StatusInternalServerError)
+	}
+
+	return r.JSONSuccess(c, "unblocked user", nil)
+}
+
+// HandleReportAbuse handles requests to report abusive messages in a thread.
+func (h *MessagesHandler) HandleReportAbuse(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	var reqDto dto.ReportAbuseRequest
+
+	reqDto.ReporterID = user.ID
+	reqDto.ThreadID = c.Param(threadIDParamName)
+
+	err = validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	resp, err := h.svc.ReportAbuse(c.Request().Context(), &reqDto, user)
+	if err != nil {
+		return messagesError(c, "failed to report abuse", err)
+	}
+
+	return r.JSONSuccess(c, "reported abuse", resp)
+}
+
+func (h *MessagesHandler) sendMessage(c echo.Context, reqDto dto.SendMessageRequest) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	reqDto.SenderID = user.ID
+
+	err = validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	resp, err := h.svc.SendMessage(c.Request().Context(), &reqDto)
+	if err != nil {
+		return messagesError(c, "failed to send message", err)
+	}
+
+	return r.JSONSuccess(c, "sent message", resp)
+}
+
+func blockUserRequest(c echo.Context) (*dto.BlockUserRequest, error) {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	reqDto := dto.BlockUserRequest{UserID: user.ID, BlockedUserID: c.Param(userIDParamName)}
+
+	err = validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return nil, r.JSONError(c, err.Error(), err) //nolint:wrapcheck
+	}
+
+	return &reqDto, nil
+}
+
+func messagesError(c echo.Context, msg string, err error) error {
+	switch {
+	case errors.Is(err, services.ErrForbidden):
+		return r.JSONError(c, "forbidden", err, http.StatusForbidden)
+	case errors.Is(err, services.ErrUserBlocked):
+		return r.JSONError(c, err.Error(), err, http.StatusForbidden)
+	case errors.Is(err, services.ErrThreadNotFound),
+		errors.Is(err, services.ErrListingNotFound):
+		return r.JSONError(c, err.Error(), err, http.StatusNotFound)
+	case errors.Is(err, services.ErrCantMessageOwnListing),
+		errors.Is(err, services.ErrMessageDoesntExist):
+		return r.JSONError(c, err.Error(), err)
+	default:
+		return r.JSONError(c, msg, err, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"errors"
+	"golang-connect-marketplace/internal/auth/middleware"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/internal/marketplace/services"
+	r "golang-connect-marketplace/pkg/responses"
+	"golang-connect-marketplace/pkg/validation"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ModerationHandler handles abuse reports and moderation HTTP requests.
+type ModerationHandler struct {
+	svc *services.ModerationService
+}
+
+// NewModerationHandler creates a new Handler for handling moderation requests.
+func NewModerationHandler(svc *services.ModerationService) *ModerationHandler {
+	return &ModerationHandler{
+		svc: svc,
+	}
+}
+
+// HandleReportListing handles requests to report a listing.
+func (h *ModerationHandler) HandleReportListing(c echo.Context) error {
+	listingID := c.Param(listingIDParamName)
+
+	reqDto, err := reportRequest(c, "", &listingID)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.svc.ReportListing(c.Request().Context(), reqDto)
+	if err != nil {
+		return moderationError(c, "failed to report listing", err)
+	}
+
+	return r.JSONSuccess(c, "reported listing", resp)
+}
+
+// HandleReportUser handles requests to report a user.
+func (h *ModerationHandler) HandleReportUser(c echo.Context) error {
+	reqDto, err := reportRequest(c, c.Param(userIDParamName), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.svc.ReportUser(c.Request().Context(), reqDto)
+	if err != nil {
+		return moderationError(c, "failed to report user", err)
+	}
+
+	return r.JSONSuccess(c, "reported user", resp)
+}
+
+// HandleGetReports handles requests from admins to fetch the moderation queue.
+func (h *ModerationHandler) HandleGetReports(c echo.Context) error {
+	var reqDto dto.GetReportsRequest
+
+	err := validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	resp, err := h.svc.GetReports(c.Request().Context(), &reqDto)
+	if err != nil {
+		return r.JSONError(c, "failed to fetch reports", err, http.StatusInternalServerError)
+	}
+
+	return r.JSONSuccess(c, "fetched reports", resp)
+}
+
+// HandleGetPendingListings handles requests from admins to fetch listings waiting for review.
+func (h *ModerationHandler) HandleGetPendingListings(c echo.Context) error {
+	var reqDto dto.GetPendingListingsRequest
+
+	err := validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	resp, err := h.svc.GetPendingListings(c.Request().Context(), &reqDto)
+	if err != nil {
+		return r.JSONError(
+			c, "failed to fetch pending listings", err, http.StatusInternalServerError,
+		)
+	}
+
+	return r.JSONSuccess(c, "fetched pending listings", resp)
+}
+
+// HandleApplyAction handles requests from admins to act on a report, a listing or a user.
+func (h *ModerationHandler) HandleApplyAction(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	var reqDto dto.ModerationActionRequest
+
+	reqDto.AdminID = user.ID
+
+	err = validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	resp, err := h.svc.ApplyAction(c.Request().Context(), &reqDto)
+	if err != nil {
+		return moderationError(c, "failed to apply moderation action", err)
+	}
+
+	return r.JSONSuccess(c, "applied moderation action", resp)
+}
+
+// HandleGetActions handles requests from admins to fetch the moderation audit trail.
+func (h *ModerationHandler) HandleGetActions(c echo.Context) error {
+	var reqDto dto.GetModerationActionsRequest
+
+	err := validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	resp, err := h.svc.GetActions(c.Request().Context(), &reqDto)
+	if err != nil {
+		return r.JSONError(
+			c, "failed to fetch moderation actions", err, http.StatusInternalServerError,
+		)
+	}
+
+	return r.JSONSuccess(c, "fetched moderation actions", resp)
+}
+
+func reportRequest(
+	c echo.Context,
+	userID string,
+	listingID *string,
+) (*dto.CreateReportRequest, error) {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var reqDto dto.CreateReportRequest
+
+	reqDto.ReporterID = user.ID
+	reqDto.UserID = userID
+	reqDto.ListingID = listingID
+
+	err = validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return nil, r.JSONError(c, err.Error(), err)
+	}
+
+	return &reqDto, nil
+}
+
+func moderationError(c echo.Context, msg string, err error) error {
+	switch {
+	case errors.Is(err, services.ErrListingNotFound),
+		errors.Is(err, services.ErrUserNotFound),
+		errors.Is(err, services.ErrReportNotFound):
+		return r.JSONError(c, err.Error(), err, http.StatusNotFound)
+	case errors.Is(err, services.ErrReportClosed),
+		errors.Is(err, services.ErrIllegalStatusTransition):
+		return r.JSONError(c, err.Error(), err, http.StatusConflict)
+	case errors.Is(err, services.ErrCantReportYourself),
+		errors.Is(err, services.ErrInvalidModerationAction):
+		return r.JSONError(c, err.Error(), err)
+	default:
+		return r.JSONError(c, msg, err, http.StatusInternalServerError)
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"golang-connect-marketplace/internal/auth/middleware"
 	"golang-connect-marketplace/internal/marketplace/dto"
 	"golang-connect-marketplace/internal/marketplace/services"
@@ -13,6 +14,8 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+const orderIDParamName = "order_id"
+
 // PaymentsHandler handles payments-related HTTP requests.
 type PaymentsHandler struct {
 	svc *services.PaymentsService
@@ -75,6 +78,17 @@ func (h *PaymentsHandler) HandleCreateCheckoutSession(c echo.Context) error {
 
 	resp, err := h.svc.CreateCheckoutSession(c.Request().Context(), &reqDto)
 	if err != nil {
+		if errors.Is(err, services.ErrListingIsExpired) ||
+			errors.Is(err, services.ErrListingIsNotOpen) {
+			return r.JSONError(c, err.Error(), err, http.StatusConflict)
+		}
+
+		if errors.Is(err, services.ErrShippingOptionRequired) ||
+			errors.Is(err, services.ErrShippingOptionNotFound) ||
+			errors.Is(err, services.ErrUnsupportedCurrency) {
+			return r.JSONError(c, err.Error(), err)
+		}
+
 		return r.JSONError(
 			c,
 			"failed to create checkout session",
@@ -86,6 +100,40 @@ func (h *PaymentsHandler) HandleCreateCheckoutSession(c echo.Context) error {
 	return r.JSONSuccess(c, "created checkout session", resp)
 }
 
+// HandleGetOrder handles requests from buyers and sellers to fetch an order.
+func (h *PaymentsHandler) HandleGetOrder(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.svc.GetOrder(c.Request().Context(), c.Param(orderIDParamName), user)
+	if err != nil {
+		if errors.Is(err, services.ErrOrderNotFound) {
+			return r.JSONError(c, err.Error(), err, http.StatusNotFound)
+		}
+
+		return r.JSONError(c, "failed to fetch order", err, http.StatusInternalServerError)
+	}
+
+	return r.JSONSuccess(c, "fetched order", resp)
+}
+
+// HandleGetSales handles requests to fetch orders of listings sold by the authenticated user.
+func (h *PaymentsHandler) HandleGetSales(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.svc.GetSales(c.Request().Context(), user.ID)
+	if err != nil {
+		return r.JSONError(c, "failed to fetch sales", err, http.StatusInternalServerError)
+	}
+
+	return r.JSONSuccess(c, "fetched sales", resp)
+}
+
 // HandlePaymentWebhookSuccess handles payment success webhook events.
 func (h *PaymentsHandler) HandlePaymentWebhookSuccess(c echo.Context) error {
 	payload, err := io.ReadAll(c.Request().Body)
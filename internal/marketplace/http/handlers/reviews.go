@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"golang-connect-marketplace/internal/auth/middleware"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/internal/marketplace/services"
+	r "golang-connect-marketplace/pkg/responses"
+	"golang-connect-marketplace/pkg/validation"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ReviewsHandler handles post-purchase reviews HTTP requests.
+type ReviewsHandler struct {
+	svc *services.ReviewsService
+}
+
+// NewReviewsHandler creates a new Handler for handling reviews requests.
+func NewReviewsHandler(svc *services.ReviewsService) *ReviewsHandler {
+	return &ReviewsHandler{
+		svc: svc,
+	}
+}
+
+// HandleCreateReview handles requests from the buyer or the seller to review a sale.
+func (h *ReviewsHandler) HandleCreateReview(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	var reqDto dto.CreateReviewRequest
+
+	reqDto.ReviewerID = user.ID
+
+	err = validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	resp, err := h.svc.CreateReview(c.Request().Context(), &reqDto)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrPaymentNotFound):
+			return r.JSONError(c, err.Error(), err, http.StatusNotFound)
+		case errors.Is(err, services.ErrForbidden):
+			return r.JSONError(c, "forbidden", err, http.StatusForbidden)
+		case errors.Is(err, services.ErrAlreadyReviewed):
+			return r.JSONError(c, err.Error(), err, http.StatusConflict)
+		case errors.Is(err, services.ErrPaymentRefunded),
+			errors.Is(err, services.ErrReviewWindowClosed):
+			return r.JSONError(c, err.Error(), err)
+		default:
+			return r.JSONError(c, "failed to create review", err, http.StatusInternalServerError)
+		}
+	}
+
+	return r.JSONSuccess(c, "created review", resp)
+}
+
+// HandleGetUserReviews handles requests to fetch reviews a user received.
+func (h *ReviewsHandler) HandleGetUserReviews(c echo.Context) error {
+	var reqDto dto.GetReviewsRequest
+
+	reqDto.UserID = c.Param(userIDParamName)
+
+	err := validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	resp, err := h.svc.GetReviews(c.Request().Context(), &reqDto)
+	if err != nil {
+		return r.JSONError(c, "failed to fetch reviews", err, http.StatusInternalServerError)
+	}
+
+	return r.JSONSuccess(c, "fetched reviews", resp)
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"golang-connect-marketplace/internal/auth/middleware"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/internal/marketplace/services"
+	r "golang-connect-marketplace/pkg/responses"
+	"golang-connect-marketplace/pkg/validation"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HandleGetListingRevisions handles requests from listing owners and admins to fetch
+// edit history of a listing.
+func (h *ListingsHandler) HandleGetListingRevisions(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	var reqDto dto.GetListingRevisionsRequest
+
+	reqDto.ListingID = c.Param(listingIDParamName)
+
+	err = validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	resp, err := h.svc.GetListingRevisions(c.Request().Context(), &reqDto, user)
+	if err != nil {
+		if errors.Is(err, services.ErrForbidden) {
+			return r.JSONError(c, "forbidden", err, http.StatusForbidden)
+		}
+
+		return r.JSONError(
+			c, "failed to fetch listing revisions", err, http.StatusInternalServerError,
+		)
+	}
+
+	return r.JSONSuccess(c, "fetched listing revisions", resp)
+}
+
+// HandleGetPriceHistory handles requests to fetch public price changes of a listing.
+func (h *ListingsHandler) HandleGetPriceHistory(c echo.Context) error {
+	var userID string
+	if user := middleware.LookupUserFromContext(c); user != nil {
+		userID = user.ID
+	}
+
+	resp, err := h.svc.GetPriceHistory(
+		c.Request().Context(), c.Param(listingIDParamName), userID,
+	)
+	if err != nil {
+		if errors.Is(err, services.ErrListingNotFound) {
+			return r.JSONError(c, err.Error(), err, http.StatusNotFound)
+		}
+
+		return r.JSONError(c, "failed to fetch price history", err, http.StatusInternalServerError)
+	}
+
+	return r.JSONSuccess(c, "fetched price history", resp)
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"errors"
+	"golang-connect-marketplace/internal/auth/middleware"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/internal/marketplace/services"
+	r "golang-connect-marketplace/pkg/responses"
+	"golang-connect-marketplace/pkg/validation"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+const savedSearchIDParamName = "saved_search_id"
+
+// HandleCreateSavedSearch handles requests to save a listings search.
+func (h *ListingsHandler) HandleCreateSavedSearch(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	var reqDto dto.CreateSavedSearchRequest
+
+	reqDto.UserID = user.ID
+
+	err = validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	resp, err := h.svc.CreateSavedSearch(c.Request().Context(), &reqDto)
+	if err != nil {
+		return savedSearchError(c, "failed to save search", err)
+	}
+
+	return r.JSONSuccess(c, "saved search", resp)
+}
+
+// HandleGetSavedSearches handles requests to fetch saved searches of the authenticated user.
+func (h *ListingsHandler) HandleGetSavedSearches(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.svc.GetSavedSearches(c.Request().Context(), user.ID)
+	if err != nil {
+		return savedSearchError(c, "failed to fetch saved searches", err)
+	}
+
+	return r.JSONSuccess(c, "fetched saved searches", resp)
+}
+
+// HandleUpdateSavedSearch handles requests to update a saved search.
+func (h *ListingsHandler) HandleUpdateSavedSearch(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	var reqDto dto.UpdateSavedSearchRequest
+
+	reqDto.ID = c.Param(savedSearchIDParamName)
+	reqDto.UserID = user.ID
+
+	err = validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	resp, err := h.svc.UpdateSavedSearch(c.Request().Context(), &reqDto)
+	if err != nil {
+		return savedSearchError(c, "failed to update saved search", err)
+	}
+
+	return r.JSONSuccess(c, "updated saved search", resp)
+}
+
+// HandleDeleteSavedSearch handles requests to delete a saved search.
+func (h *ListingsHandler) HandleDeleteSavedSearch(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	err = h.svc.DeleteSavedSearch(c.Request().Context(), c.Param(savedSearchIDParamName), user.ID)
+	if err != nil {
+		return savedSearchError(c, "failed to delete saved search", err)
+	}
+
+	return r.JSONSuccess(c, "deleted saved search", nil)
+}
+
+func savedSearchError(c echo.Context, msg string, err error) error {
+	switch {
+	case errors.Is(err, services.ErrSavedSearchNotFound):
+		return r.JSONError(c, err.Error(), err, http.StatusNotFound)
+	case errors.Is(err, services.ErrTooManySavedSearches):
+		return r.JSONError(c, err.Error(), err, http.StatusConflict)
+	case errors.Is(err, services.ErrEmptySavedSearch):
+		return r.JSONError(c, err.Error(), err)
+	default:
+		return r.JSONError(c, msg, err, http.StatusInternalServerError)
+	}
+}
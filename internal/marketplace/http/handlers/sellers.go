@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"errors"
+	"golang-connect-marketplace/internal/auth/middleware"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/internal/marketplace/services"
+	r "golang-connect-marketplace/pkg/responses"
+	"golang-connect-marketplace/pkg/validation"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+const usernameParamName = "username"
+
+// HandleGetSellerProfile handles requests to fetch a seller storefront.
+func (h *ListingsHandler) HandleGetSellerProfile(c echo.Context) error {
+	var reqDto dto.GetSellerProfileRequest
+
+	reqDto.Username = c.Param(usernameParamName)
+
+	if user := middleware.LookupUserFromContext(c); user != nil {
+		reqDto.UserID = user.ID
+	}
+
+	err := validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	resp, err := h.svc.GetSellerProfile(c.Request().Context(), &reqDto)
+	if err != nil {
+		if errors.Is(err, services.ErrSellerNotFound) {
+			return r.JSONError(c, err.Error(), err, http.StatusNotFound)
+		}
+
+		return r.JSONError(c, "failed to fetch seller profile", err, http.StatusInternalServerError)
+	}
+
+	return r.JSONSuccess(c, "fetched seller profile", resp)
+}
+
+// HandleUpdateSellerProfile handles requests from sellers to edit their bio and avatar.
+func (h *ListingsHandler) HandleUpdateSellerProfile(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	var reqDto dto.UpdateSellerProfileRequest
+
+	reqDto.UserID = user.ID
+
+	err = validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	resp, err := h.svc.UpdateSellerProfile(c.Request().Context(), &reqDto)
+	if err != nil {
+		return r.JSONError(
+			c, "failed to update seller profile", err, http.StatusInternalServerError,
+		)
+	}
+
+	return r.JSONSuccess(c, "updated seller profile", resp)
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+	"golang-connect-marketplace/internal/auth/middleware"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/internal/marketplace/services"
+	r "golang-connect-marketplace/pkg/responses"
+	"golang-connect-marketplace/pkg/validation"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ShipmentsHandler handles shipment tracking HTTP requests.
+type ShipmentsHandler struct {
+	svc *services.ShipmentsService
+}
+
+// NewShipmentsHandler creates a new Handler for handling shipment requests.
+func NewShipmentsHandler(svc *services.ShipmentsService) *ShipmentsHandler {
+	return &ShipmentsHandler{
+		svc: svc,
+	}
+}
+
+// HandleCreateShipment handles requests from sellers to attach carrier and tracking number
+// to an order.
+func (h *ShipmentsHandler) HandleCreateShipment(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	var reqDto dto.CreateShipmentRequest
+
+	reqDto.OrderID = c.Param(orderIDParamName)
+	reqDto.SellerID = user.ID
+
+	err = validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	resp, err := h.svc.CreateShipment(c.Request().Context(), &reqDto)
+	if err != nil {
+		return shipmentsError(c, "failed to ship order", err)
+	}
+
+	return r.JSONSuccess(c, "shipped order", resp)
+}
+
+// HandleGetShipment handles requests from buyers and sellers to track an order.
+func (h *ShipmentsHandler) HandleGetShipment(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.svc.GetShipment(c.Request().Context(), c.Param(orderIDParamName), user)
+	if err != nil {
+		return shipmentsError(c, "failed to fetch shipment", err)
+	}
+
+	return r.JSONSuccess(c, "fetched shipment", resp)
+}
+
+func shipmentsError(c echo.Context, msg string, err error) error {
+	switch {
+	case errors.Is(err, services.ErrForbidden):
+		return r.JSONError(c, "forbidden", err, http.StatusForbidden)
+	case errors.Is(err, services.ErrOrderNotFound),
+		errors.Is(err, services.ErrShipmentNotFound):
+		return r.JSONError(c, err.Error(), err, http.StatusNotFound)
+	case errors.Is(err, services.ErrOrderRefunded),
+		errors.Is(err, services.ErrOrderDelivered):
+		return r.JSONError(c, err.Error(), err, http.StatusConflict)
+	case errors.Is(err, services.ErrOrderNotShippable):
+		return r.JSONError(c, err.Error(), err)
+	default:
+		return r.JSONError(c, msg, err, http.StatusInternalServerError)
+	}
+}
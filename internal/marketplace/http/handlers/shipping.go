@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"golang-connect-marketplace/internal/auth/middleware"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/internal/marketplace/services"
+	r "golang-connect-marketplace/pkg/responses"
+	"golang-connect-marketplace/pkg/validation"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+const shippingProfileIDParamName = "shipping_profile_id"
+
+// HandleCreateShippingProfile handles requests from sellers to create a shipping profile.
+func (h *ListingsHandler) HandleCreateShippingProfile(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	var reqDto dto.CreateShippingProfileRequest
+
+	reqDto.UserID = user.ID
+
+	err = validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	resp, err := h.svc.CreateShippingProfile(c.Request().Context(), &reqDto)
+	if err != nil {
+		return shippingError(c, "failed to create shipping profile", err)
+	}
+
+	return r.JSONSuccess(c, "created shipping profile", resp)
+}
+
+// HandleGetShippingProfiles handles requests to fetch shipping profiles of the authenticated
+// user.
+func (h *ListingsHandler) HandleGetShippingProfiles(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.svc.GetShippingProfiles(c.Request().Context(), user.ID)
+	if err != nil {
+		return shippingError(c, "failed to fetch shipping profiles", err)
+	}
+
+	return r.JSONSuccess(c, "fetched shipping profiles", resp)
+}
+
+// HandleUpdateShippingProfile handles requests to update a shipping profile.
+func (h *ListingsHandler) HandleUpdateShippingProfile(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	var reqDto dto.UpdateShippingProfileRequest
+
+	reqDto.ID = c.Param(shippingProfileIDParamName)
+	reqDto.UserID = user.ID
+
+	err = validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	resp, err := h.svc.UpdateShippingProfile(c.Request().Context(), &reqDto)
+	if err != nil {
+		return shippingError(c, "failed to update shipping profile", err)
+	}
+
+	return r.JSONSuccess(c, "updated shipping profile", resp)
+}
+
+// HandleDeleteShippingProfile handles requests to delete a shipping profile.
+func (h *ListingsHandler) HandleDeleteShippingProfile(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	err = h.svc.DeleteShippingProfile(
+		c.Request().Context(), c.Param(shippingProfileIDParamName), user.ID,
+	)
+	if err != nil {
+		return shippingError(c, "failed to delete shipping profile", err)
+	}
+
+	return r.JSONSuccess(c, "deleted shipping profile", nil)
+}
+
+// HandleSetListingShipping handles requests from listing owners to choose the shipping
+// profile of a listing.
+func (h *ListingsHandler) HandleSetListingShipping(c echo.Context) error {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	var reqDto dto.SetListingShippingRequest
+
+	reqDto.UserID = user.ID
+	reqDto.ListingID = c.Param(listingIDParamName)
+
+	err = validation.ValidateDto(c, &reqDto)
+	if err != nil {
+		return r.JSONError(c, err.Error(), err)
+	}
+
+	resp, err := h.svc.SetListingShipping(c.Request().Context(), &reqDto)
+	if err != nil {
+		return shippingError(c, "failed to set listing shipping", err)
+	}
+
+	return r.JSONSuccess(c, "set listing shipping", resp)
+}
+
+func shippingError(c echo.Context, msg string, err error) error {
+	switch {
+	case errors.Is(err, services.ErrForbidden):
+		return r.JSONError(c, "forbidden", err, http.StatusForbidden)
+	case errors.Is(err, services.ErrShippingProfileNotFound):
+		return r.JSONError(c, err.Error(), err, http.StatusNotFound)
+	case errors.Is(err, services.ErrShippingCurrencyMismatch),
+		errors.Is(err, services.ErrUnsupportedCurrency):
+		return r.JSONError(c, err.Error(), err)
+	default:
+		return r.JSONError(c, msg, err, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	m "golang-connect-marketplace/internal/auth/middleware"
+	"golang-connect-marketplace/internal/auth/service"
+	"golang-connect-marketplace/internal/marketplace/http/handlers"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RegisterEventsRoutes registers the real-time events stream route.
+func RegisterEventsRoutes(e *echo.Echo, h *handlers.EventsHandler, authSvc *service.Service) {
+	api := e.Group("api/v1/events", m.AuthenticateMiddleware(authSvc))
+
+	api.GET("", h.HandleStream)
+}
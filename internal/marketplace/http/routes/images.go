@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"golang-connect-marketplace/internal/marketplace/http/handlers"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ImagesBasePath is the path under which locally stored images are served.
+const ImagesBasePath = "/api/v1/images"
+
+// RegisterImagesRoutes registers routes serving locally stored images through signed URLs.
+func RegisterImagesRoutes(e *echo.Echo, h *handlers.ImagesHandler) {
+	api := e.Group(ImagesBasePath)
+
+	api.GET("/*", h.HandleGetImage)
+}
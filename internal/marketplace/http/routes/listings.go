@@ -14,14 +14,109 @@ import (
 func RegisterListingsRoutes(e *echo.Echo, lh *handlers.ListingsHandler, authSvc *service.Service) {
 	listings := e.Group("api/v1/listings")
 	cats := e.Group("api/v1/categories")
+	sellers := e.Group("api/v1/sellers")
+	me := e.Group("api/v1/me")
 
-	listings.GET("", lh.HandleGetListings)
+	listings.GET("", lh.HandleGetListings, m.OptionalAuthenticateMiddleware(authSvc))
 	listings.POST("", lh.HandleCreateListing, m.AuthenticateMiddleware(authSvc))
-	listings.GET("/:listing_id", lh.HandleGetListing)
+	listings.POST("/import", lh.HandleImportListings, m.AuthenticateMiddleware(authSvc))
+	listings.GET("/import/:import_id", lh.HandleGetImport, m.AuthenticateMiddleware(authSvc))
+	listings.GET("/:listing_id", lh.HandleGetListing, m.OptionalAuthenticateMiddleware(authSvc))
 	listings.PATCH("/:listing_id", lh.HandleUpdateListing, m.AuthenticateMiddleware(authSvc))
+	listings.POST("/:listing_id/renew", lh.HandleRenewListing, m.AuthenticateMiddleware(authSvc))
+	listings.GET(
+		"/:listing_id/revisions",
+		lh.HandleGetListingRevisions,
+		m.AuthenticateMiddleware(authSvc),
+	)
+	listings.GET(
+		"/:listing_id/price-history",
+		lh.HandleGetPriceHistory,
+		m.OptionalAuthenticateMiddleware(authSvc),
+	)
 	listings.POST("/:listing_id/images", lh.HandleAddImages, m.AuthenticateMiddleware(authSvc))
 	listings.DELETE("/:listing_id/images", lh.HandleDeleteImages, m.AuthenticateMiddleware(authSvc))
+	listings.PUT(
+		"/:listing_id/shipping",
+		lh.HandleSetListingShipping,
+		m.AuthenticateMiddleware(authSvc),
+	)
+	listings.PUT(
+		"/:listing_id/location",
+		lh.HandleSetListingLocation,
+		m.AuthenticateMiddleware(authSvc),
+	)
+	listings.POST("/:listing_id/favorite", lh.HandleAddFavorite, m.AuthenticateMiddleware(authSvc))
+	listings.DELETE(
+		"/:listing_id/favorite",
+		lh.HandleRemoveFavorite,
+		m.AuthenticateMiddleware(authSvc),
+	)
+	listings.PUT(
+		"/:listing_id/images/order",
+		lh.HandleReorderImages,
+		m.AuthenticateMiddleware(authSvc),
+	)
+	listings.PATCH(
+		"/:listing_id/images/:image_id",
+		lh.HandleUpdateImage,
+		m.AuthenticateMiddleware(authSvc),
+	)
 
 	cats.POST("", lh.HandleCreateCategory, m.AuthenticateMiddleware(authSvc, dto.UserRoleAdmin))
 	cats.GET("", lh.HandleGetCategories)
+	cats.PATCH(
+		"/:category_id",
+		lh.HandleUpdateCategory,
+		m.AuthenticateMiddleware(authSvc, dto.UserRoleAdmin),
+	)
+	cats.DELETE(
+		"/:category_id",
+		lh.HandleDeleteCategory,
+		m.AuthenticateMiddleware(authSvc, dto.UserRoleAdmin),
+	)
+	cats.GET("/:category_id/attributes", lh.HandleGetCategoryAttributes)
+	cats.PUT(
+		"/:category_id/attributes",
+		lh.HandleSetCategoryAttributes,
+		m.AuthenticateMiddleware(authSvc, dto.UserRoleAdmin),
+	)
+
+	sellers.GET(
+		"/:username",
+		lh.HandleGetSellerProfile,
+		m.OptionalAuthenticateMiddleware(authSvc),
+	)
+
+	me.PATCH("/profile", lh.HandleUpdateSellerProfile, m.AuthenticateMiddleware(authSvc))
+	me.GET("/listings/export", lh.HandleExportListings, m.AuthenticateMiddleware(authSvc))
+	me.GET("/favorites", lh.HandleGetFavorites, m.AuthenticateMiddleware(authSvc))
+	me.GET("/shipping-profiles", lh.HandleGetShippingProfiles, m.AuthenticateMiddleware(authSvc))
+	me.POST(
+		"/shipping-profiles",
+		lh.HandleCreateShippingProfile,
+		m.AuthenticateMiddleware(authSvc),
+	)
+	me.PATCH(
+		"/shipping-profiles/:shipping_profile_id",
+		lh.HandleUpdateShippingProfile,
+		m.AuthenticateMiddleware(authSvc),
+	)
+	me.DELETE(
+		"/shipping-profiles/:shipping_profile_id",
+		lh.HandleDeleteShippingProfile,
+		m.AuthenticateMiddleware(authSvc),
+	)
+	me.GET("/saved-searches", lh.HandleGetSavedSearches, m.AuthenticateMiddleware(authSvc))
+	me.POST("/saved-searches", lh.HandleCreateSavedSearch, m.AuthenticateMiddleware(authSvc))
+	me.PATCH(
+		"/saved-searches/:saved_search_id",
+		lh.HandleUpdateSavedSearch,
+		m.AuthenticateMiddleware(authSvc),
+	)
+	me.DELETE(
+		"/saved-searches/:saved_search_id",
+		lh.HandleDeleteSavedSearch,
+		m.AuthenticateMiddleware(authSvc),
+	)
 }
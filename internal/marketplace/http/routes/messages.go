@@ -0,0 +1,25 @@
+package routes
+
+import (
+	m "golang-connect-marketplace/internal/auth/middleware"
+	"golang-connect-marketplace/internal/auth/service"
+	"golang-connect-marketplace/internal/marketplace/http/handlers"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RegisterMessagesRoutes registers buyer-seller messaging HTTP routes.
+func RegisterMessagesRoutes(e *echo.Echo, h *handlers.MessagesHandler, authSvc *service.Service) {
+	listings := e.Group("api/v1/listings", m.AuthenticateMiddleware(authSvc))
+	api := e.Group("api/v1/messages", m.AuthenticateMiddleware(authSvc))
+
+	listings.POST("/:listing_id/messages", h.HandleSendListingMessage)
+
+	api.GET("/threads", h.HandleGetThreads)
+	api.GET("/threads/:thread_id", h.HandleGetThread)
+	api.POST("/threads/:thread_id/messages", h.HandleSendThreadMessage)
+	api.POST("/threads/:thread_id/report", h.HandleReportAbuse)
+	api.GET("/unread", h.HandleGetUnreadCount)
+	api.POST("/blocks/:user_id", h.HandleBlockUser)
+	api.DELETE("/blocks/:user_id", h.HandleUnblockUser)
+}
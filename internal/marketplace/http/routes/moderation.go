@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"golang-connect-marketplace/internal/auth/dto"
+	m "golang-connect-marketplace/internal/auth/middleware"
+	"golang-connect-marketplace/internal/auth/service"
+	"golang-connect-marketplace/internal/marketplace/http/handlers"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RegisterModerationRoutes registers abuse reports and admin moderation HTTP routes.
+func RegisterModerationRoutes(
+	e *echo.Echo,
+	h *handlers.ModerationHandler,
+	authSvc *service.Service,
+) {
+	listings := e.Group("api/v1/listings", m.AuthenticateMiddleware(authSvc))
+	users := e.Group("api/v1/users", m.AuthenticateMiddleware(authSvc))
+	admin := e.Group(
+		"api/v1/admin/moderation",
+		m.AuthenticateMiddleware(authSvc, dto.UserRoleAdmin),
+	)
+
+	listings.POST("/:listing_id/report", h.HandleReportListing)
+	users.POST("/:user_id/report", h.HandleReportUser)
+
+	admin.GET("/reports", h.HandleGetReports)
+	admin.GET("/pending-listings", h.HandleGetPendingListings)
+	admin.GET("/actions", h.HandleGetActions)
+	admin.POST("/actions", h.HandleApplyAction)
+}
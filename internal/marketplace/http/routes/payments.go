@@ -11,10 +11,16 @@ import (
 // RegisterPaymentsRoutes registers marketplace-related HTTP routes.
 func RegisterPaymentsRoutes(e *echo.Echo, h *handlers.PaymentsHandler, authSvc *service.Service) {
 	api := e.Group("api/v1/payments")
+	orders := e.Group("api/v1/orders")
+	me := e.Group("api/v1/me")
 
 	api.POST("/link-seller", h.HandleLinkSellerAccount, m.AuthenticateMiddleware(authSvc))
 	api.POST("/:listing_id", h.HandleCreateCheckoutSession, m.AuthenticateMiddleware(authSvc))
 
 	api.POST("/webhook/success", h.HandlePaymentWebhookSuccess)
 	api.POST("/webhook/refund", h.HandlePaymentWebhookRefund)
+
+	orders.GET("/:order_id", h.HandleGetOrder, m.AuthenticateMiddleware(authSvc))
+
+	me.GET("/sales", h.HandleGetSales, m.AuthenticateMiddleware(authSvc))
 }
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	m "golang-connect-marketplace/internal/auth/middleware"
+	"golang-connect-marketplace/internal/auth/service"
+	"golang-connect-marketplace/internal/marketplace/http/handlers"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RegisterReviewsRoutes registers post-purchase reviews HTTP routes.
+func RegisterReviewsRoutes(e *echo.Echo, h *handlers.ReviewsHandler, authSvc *service.Service) {
+	reviews := e.Group("api/v1/reviews")
+	users := e.Group("api/v1/users")
+
+	reviews.POST("", h.HandleCreateReview, m.AuthenticateMiddleware(authSvc))
+
+	users.GET("/:user_id/reviews", h.HandleGetUserReviews)
+}
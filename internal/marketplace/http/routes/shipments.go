@@ -0,0 +1,21 @@
+package routes
+
+import (
+	m "golang-connect-marketplace/internal/auth/middleware"
+	"golang-connect-marketplace/internal/auth/service"
+	"golang-connect-marketplace/internal/marketplace/http/handlers"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RegisterShipmentsRoutes registers shipment tracking HTTP routes.
+func RegisterShipmentsRoutes(
+	e *echo.Echo,
+	h *handlers.ShipmentsHandler,
+	authSvc *service.Service,
+) {
+	orders := e.Group("api/v1/orders")
+
+	orders.POST("/:order_id/shipment", h.HandleCreateShipment, m.AuthenticateMiddleware(authSvc))
+	orders.GET("/:order_id/shipment", h.HandleGetShipment, m.AuthenticateMiddleware(authSvc))
+}
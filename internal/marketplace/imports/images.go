@@ -0,0 +1,97 @@
+package imports
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/storage"
+	"io"
+	"mime/multipart"
+	"strings"
+)
+
+var (
+	// ErrImageNotFound is returned when a row references an image missing from the archive.
+	ErrImageNotFound = errors.New("image not found in archive")
+	// ErrImageTooLarge is returned when an image in the archive exceeds the size limit.
+	ErrImageTooLarge = errors.New("image in archive is too large")
+)
+
+// Images holds images of a ZIP archive uploaded with an import, keyed by their path
+// in the archive.
+type Images struct {
+	files   map[string]*zip.File
+	maxSize int64
+}
+
+// OpenImages reads the list of files of a ZIP archive. Images larger than maxSize bytes
+// are refused when opened.
+func OpenImages(data []byte, maxSize int64) (*Images, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("reading zip archive: %w", err)
+	}
+
+	images := &Images{
+		files:   make(map[string]*zip.File, len(reader.File)),
+		maxSize: maxSize,
+	}
+
+	for _, f := range reader.File {
+		if !f.FileInfo().IsDir() {
+			images.files[f.Name] = f
+		}
+	}
+
+	return images, nil
+}
+
+// Upload returns the image with the given path in the archive.
+func (i *Images) Upload(name string) (storage.Upload, error) {
+	f, ok := i.files[strings.TrimPrefix(name, "./")]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrImageNotFound, name)
+	}
+
+	if f.UncompressedSize64 > uint64(i.maxSize) { //nolint:gosec
+		return nil, fmt.Errorf("%w: %s", ErrImageTooLarge, name)
+	}
+
+	return &archivedImage{file: f, maxSize: i.maxSize}, nil
+}
+
+// archivedImage is an image in a ZIP archive, read into memory when opened.
+type archivedImage struct {
+	file    *zip.File
+	maxSize int64
+}
+
+// Open reads the image, refusing archives which lie about its size.
+func (a *archivedImage) Open() (multipart.File, error) {
+	rc, err := a.file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("opening %s in archive: %w", a.file.Name, err)
+	}
+	defer rc.Close() //nolint:errcheck
+
+	data, err := io.ReadAll(io.LimitReader(rc, a.maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s from archive: %w", a.file.Name, err)
+	}
+
+	if int64(len(data)) > a.maxSize {
+		return nil, fmt.Errorf("%w: %s", ErrImageTooLarge, a.file.Name)
+	}
+
+	return imageFile{Reader: bytes.NewReader(data)}, nil
+}
+
+// imageFile implements multipart.File for an image read into memory.
+type imageFile struct {
+	*bytes.Reader
+}
+
+func (imageFile) Close() error {
+	return nil
+}
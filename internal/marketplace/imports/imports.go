@@ -0,0 +1,283 @@
+// Package imports reads and writes listings in CSV and NDJSON files used for bulk import
+// and export, and reads images from ZIP archives uploaded with imports.
+package imports
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	// listSeparator separates images in a CSV cell.
+	listSeparator = ";"
+	// maxLineSize caps the size of one NDJSON line.
+	maxLineSize = 1 << 20
+)
+
+var (
+	// ErrUnknownFormat is returned for files which are neither CSV nor NDJSON.
+	ErrUnknownFormat = errors.New("unknown import format")
+	// ErrMissingColumn is returned when a CSV header lacks a required column.
+	ErrMissingColumn = errors.New("missing required column")
+)
+
+// requiredColumns must be present in the header of CSV imports.
+var requiredColumns = []string{"category_id", "title", "description", "price_in_cents", "currency"}
+
+// exportColumns is the header of CSV exports. Imports ignore id, status and image_urls,
+// so an export can be imported again.
+var exportColumns = []string{
+	"id",
+	"status",
+	"category_id",
+	"title",
+	"description",
+	"price_in_cents",
+	"currency",
+	"attributes",
+	"image_urls",
+}
+
+// Row is a parsed row of an import file. Line is the 1-based number of the row, not counting
+// the CSV header and blank lines. Err is set when the row couldn't be parsed.
+type Row struct {
+	Line    int
+	Listing dto.ImportRow
+	Err     error
+}
+
+// DetectFormat returns the format of a file by its extension.
+func DetectFormat(filename string) (dto.ImportFormat, error) {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".csv":
+		return dto.ImportFormatCSV, nil
+	case ".ndjson", ".jsonl":
+		return dto.ImportFormatNDJSON, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownFormat, filename)
+	}
+}
+
+// ContentType returns the MIME type of files in the format.
+func ContentType(format dto.ImportFormat) string {
+	if format == dto.ImportFormatNDJSON {
+		return "application/x-ndjson"
+	}
+
+	return "text/csv"
+}
+
+// Parse reads all rows of an import file. Errors of single rows are reported in their Row,
+// an error is returned only when the file as a whole can't be read.
+func Parse(format dto.ImportFormat, r io.Reader) ([]Row, error) {
+	switch format {
+	case dto.ImportFormatCSV:
+		return parseCSV(r)
+	case dto.ImportFormatNDJSON:
+		return parseNDJSON(r)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+}
+
+func parseCSV(r io.Reader) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading csv header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, name := range requiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrMissingColumn, name)
+		}
+	}
+
+	var rows []Row
+
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("reading csv row %d: %w", line, err)
+			}
+
+			rows = append(rows, Row{Line: line, Listing: dto.ImportRow{}, Err: err})
+
+			continue
+		}
+
+		listing, err := csvRow(columns, record)
+		rows = append(rows, Row{Line: line, Listing: listing, Err: err})
+	}
+}
+
+func csvRow(columns map[string]int, record []string) (dto.ImportRow, error) {
+	cell := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+
+		return strings.TrimSpace(record[i])
+	}
+
+	row := dto.ImportRow{
+		CategoryID:   cell("category_id"),
+		Title:        cell("title"),
+		Description:  cell("description"),
+		PriceInCents: 0,
+		Currency:     cell("currency"),
+		Attributes:   dto.ListingAttributes{},
+		Images:       nil,
+	}
+
+	if price := cell("price_in_cents"); price != "" {
+		cents, err := strconv.Atoi(price)
+		if err != nil {
+			return row, fmt.Errorf("invalid price_in_cents %q", price)
+		}
+
+		row.PriceInCents = cents
+	}
+
+	if attributes := cell("attributes"); attributes != "" {
+		err := json.Unmarshal([]byte(attributes), &row.Attributes)
+		if err != nil {
+			return row, fmt.Errorf("invalid attributes: %w", err)
+		}
+	}
+
+	for image := range strings.SplitSeq(cell("images"), listSeparator) {
+		if image = strings.TrimSpace(image); image != "" {
+			row.Images = append(row.Images, image)
+		}
+	}
+
+	return row, nil
+}
+
+func parseNDJSON(r io.Reader) ([]Row, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+
+	var rows []Row
+
+	for line := 1; scanner.Scan(); {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var listing dto.ImportRow
+
+		err := json.Unmarshal(data, &listing)
+		if err != nil {
+			err = fmt.Errorf("invalid json: %w", err)
+		}
+
+		rows = append(rows, Row{Line: line, Listing: listing, Err: err})
+		line++
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("reading ndjson: %w", err)
+	}
+
+	return rows, nil
+}
+
+// Write writes exported listings in the format.
+func Write(format dto.ImportFormat, w io.Writer, rows []dto.ExportRow) error {
+	switch format {
+	case dto.ImportFormatCSV:
+		return writeCSV(w, rows)
+	case dto.ImportFormatNDJSON:
+		return writeNDJSON(w, rows)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+}
+
+func writeCSV(w io.Writer, rows []dto.ExportRow) error {
+	writer := csv.NewWriter(w)
+
+	err := writer.Write(exportColumns)
+	if err != nil {
+		return fmt.Errorf("writing csv header: %w", err)
+	}
+
+	for _, row := range rows {
+		attributes, err := json.Marshal(row.Attributes)
+		if err != nil {
+			return fmt.Errorf("marshaling attributes of listing %s: %w", row.ID, err)
+		}
+
+		err = writer.Write([]string{
+			row.ID,
+			string(row.Status),
+			row.CategoryID,
+			row.Title,
+			row.Description,
+			strconv.Itoa(row.PriceInCents),
+			row.Currency,
+			string(attributes),
+			strings.Join(row.ImageURLs, listSeparator),
+		})
+		if err != nil {
+			return fmt.Errorf("writing csv row of listing %s: %w", row.ID, err)
+		}
+	}
+
+	writer.Flush()
+
+	err = writer.Error()
+	if err != nil {
+		return fmt.Errorf("flushing csv: %w", err)
+	}
+
+	return nil
+}
+
+func writeNDJSON(w io.Writer, rows []dto.ExportRow) error {
+	encoder := json.NewEncoder(w)
+
+	for _, row := range rows {
+		if row.Attributes == nil {
+			row.Attributes = dto.ListingAttributes{}
+		}
+
+		if row.ImageURLs == nil {
+			row.ImageURLs = []string{}
+		}
+
+		err := encoder.Encode(row)
+		if err != nil {
+			return fmt.Errorf("writing ndjson row of listing %s: %w", row.ID, err)
+		}
+	}
+
+	return nil
+}
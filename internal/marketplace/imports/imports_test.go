@@ -0,0 +1,154 @@
+package imports
+
+import (
+	"archive/zip"
+	"bytes"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_CSV(t *testing.T) {
+	t.Parallel()
+
+	file := "title,description,category_id,price_in_cents,currency,attributes,images\n" +
+		`Road bike 56cm,"Carbon frame, barely used",cat_1,120000,EUR,"{""size"":56}",a.jpg; b.jpg` +
+		"\n" +
+		"Kids scooter,Blue,cat_2,cheap,EUR,,\n"
+
+	rows, err := Parse(dto.ImportFormatCSV, strings.NewReader(file))
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	require.NoError(t, rows[0].Err)
+	assert.Equal(t, 1, rows[0].Line)
+	assert.Equal(t, dto.ImportRow{
+		CategoryID:   "cat_1",
+		Title:        "Road bike 56cm",
+		Description:  "Carbon frame, barely used",
+		PriceInCents: 120000,
+		Currency:     "EUR",
+		Attributes:   dto.ListingAttributes{"size": float64(56)},
+		Images:       []string{"a.jpg", "b.jpg"},
+	}, rows[0].Listing)
+
+	assert.Equal(t, 2, rows[1].Line)
+	require.Error(t, rows[1].Err)
+	assert.Contains(t, rows[1].Err.Error(), "price_in_cents")
+}
+
+func TestParse_CSVMissingColumn(t *testing.T) {
+	t.Parallel()
+
+	_, err := Parse(dto.ImportFormatCSV, strings.NewReader("title,description\nBike,Red\n"))
+	require.ErrorIs(t, err, ErrMissingColumn)
+}
+
+func TestParse_NDJSON(t *testing.T) {
+	t.Parallel()
+
+	file := `{"title":"Road bike 56cm","category_id":"cat_1","price_in_cents":120000}` + "\n" +
+		"\n" +
+		`{"title": broken}` + "\n"
+
+	rows, err := Parse(dto.ImportFormatNDJSON, strings.NewReader(file))
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	require.NoError(t, rows[0].Err)
+	assert.Equal(t, "Road bike 56cm", rows[0].Listing.Title)
+	assert.Equal(t, 120000, rows[0].Listing.PriceInCents)
+
+	assert.Equal(t, 2, rows[1].Line)
+	require.Error(t, rows[1].Err)
+}
+
+func TestWrite_ExportCanBeImported(t *testing.T) {
+	t.Parallel()
+
+	exported := []dto.ExportRow{{
+		ID:     "item_1",
+		Status: dto.ListingStatusOpen,
+		ImportRow: dto.ImportRow{
+			CategoryID:   "cat_1",
+			Title:        "Road bike 56cm",
+			Description:  "Carbon frame,\n\"barely\" used",
+			PriceInCents: 120000,
+			Currency:     "EUR",
+			Attributes:   dto.ListingAttributes{"size": float64(56)},
+			Images:       nil,
+		},
+		ImageURLs: []string{"https://cdn.example.com/a.jpg"},
+	}}
+
+	for _, format := range []dto.ImportFormat{dto.ImportFormatCSV, dto.ImportFormatNDJSON} {
+		var buf bytes.Buffer
+
+		require.NoError(t, Write(format, &buf, exported))
+
+		rows, err := Parse(format, &buf)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.NoError(t, rows[0].Err)
+		assert.Equal(t, exported[0].ImportRow, rows[0].Listing, format)
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	t.Parallel()
+
+	format, err := DetectFormat("listings.CSV")
+	require.NoError(t, err)
+	assert.Equal(t, dto.ImportFormatCSV, format)
+
+	format, err = DetectFormat("listings.jsonl")
+	require.NoError(t, err)
+	assert.Equal(t, dto.ImportFormatNDJSON, format)
+
+	_, err = DetectFormat("listings.xlsx")
+	require.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestImages_Upload(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	zw := zip.NewWriter(&buf)
+
+	w, err := zw.Create("photos/a.jpg")
+	require.NoError(t, err)
+
+	_, err = w.Write([]byte("image data"))
+	require.NoError(t, err)
+
+	w, err = zw.Create("big.jpg")
+	require.NoError(t, err)
+
+	_, err = w.Write(bytes.Repeat([]byte("x"), 100))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	images, err := OpenImages(buf.Bytes(), 50)
+	require.NoError(t, err)
+
+	upload, err := images.Upload("./photos/a.jpg")
+	require.NoError(t, err)
+
+	file, err := upload.Open()
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(file)
+	require.NoError(t, err)
+	assert.Equal(t, "image data", string(data))
+
+	_, err = images.Upload("missing.jpg")
+	require.ErrorIs(t, err, ErrImageNotFound)
+
+	_, err = images.Upload("big.jpg")
+	require.ErrorIs(t, err, ErrImageTooLarge)
+}
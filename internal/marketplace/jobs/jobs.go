@@ -0,0 +1,56 @@
+// Package jobs runs periodic background tasks.
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Task is a unit of background work.
+type Task func(ctx context.Context) error
+
+// RunPeriodically runs task every interval until ctx is canceled.
+func RunPeriodically(
+	ctx context.Context,
+	logger *slog.Logger,
+	name string,
+	interval time.Duration,
+	task Task,
+) {
+	if interval <= 0 {
+		logger.Warn("background job disabled, interval is not set", "job", name)
+
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		runOnce(ctx, logger, name, task)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func runOnce(ctx context.Context, logger *slog.Logger, name string, task Task) {
+	start := time.Now()
+
+	err := task(ctx)
+	if err != nil {
+		logger.Error("background job failed",
+			"job", name,
+			"duration", time.Since(start),
+			"error", err,
+		)
+
+		return
+	}
+
+	logger.Debug("background job finished", "job", name, "duration", time.Since(start))
+}
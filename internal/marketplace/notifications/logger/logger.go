@@ -0,0 +1,32 @@
+// Package logger implements a notifier that writes notifications to the application log.
+package logger
+
+import (
+	"context"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"log/slog"
+)
+
+type loggerNotifier struct {
+	logger *slog.Logger
+}
+
+// NewLoggerNotifier creates a new notifier which logs every notification.
+func NewLoggerNotifier(logger *slog.Logger) *loggerNotifier { //nolint:revive
+	return &loggerNotifier{
+		logger: logger,
+	}
+}
+
+// Notify logs the notification.
+func (n *loggerNotifier) Notify(ctx context.Context, notification *dto.Notification) error {
+	n.logger.InfoContext(ctx, "sending notification",
+		"user_id", notification.UserID,
+		"type", notification.Type,
+		"title", notification.Title,
+		"body", notification.Body,
+		"data", notification.Data,
+	)
+
+	return nil
+}
@@ -0,0 +1,12 @@
+// Package notifications handles delivering notifications to users.
+package notifications
+
+import (
+	"context"
+	"golang-connect-marketplace/internal/marketplace/dto"
+)
+
+// Notifier defines methods for delivering notifications to users.
+type Notifier interface {
+	Notify(ctx context.Context, n *dto.Notification) error
+}
@@ -3,6 +3,7 @@ package paymentproviders
 import (
 	"context"
 	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/pkg/money"
 	"net/http"
 )
 
@@ -22,7 +23,8 @@ type PaymentProvider interface {
 		ctx context.Context,
 		req *dto.CheckoutSessionRequest,
 		listing *dto.Listing,
-		feeAmount int64,
+		shipping *dto.ShippingOption,
+		fee money.Money,
 	) (*dto.CheckoutSessionResponse, error)
 	VerifySuccessWebhook(
 		ctx context.Context,
@@ -34,4 +36,9 @@ type PaymentProvider interface {
 		payload []byte,
 		header http.Header,
 	) (*dto.Payment, error)
+	// SupportsCurrency reports whether buyers can be charged in the currency.
+	SupportsCurrency(currency money.Currency) bool
+	// SellerCurrencies returns codes of currencies the seller account can be paid out in,
+	// it is empty until the seller finishes onboarding.
+	SellerCurrencies(ctx context.Context, sellerID string) ([]string, error)
 }
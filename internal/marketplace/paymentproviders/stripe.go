@@ -8,7 +8,11 @@ import (
 	"fmt"
 	"golang-connect-marketplace/internal/marketplace/dto"
 	"golang-connect-marketplace/pkg/generate"
+	"golang-connect-marketplace/pkg/money"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/stripe/stripe-go/v84"
 	"github.com/stripe/stripe-go/v84/account"
@@ -25,13 +29,28 @@ var (
 	ErrWebhookMetadataHasMissingFields = errors.New("order_id missing from payment metadata")
 )
 
+// stripeCurrencies lists currencies Stripe can charge in, the card presentment currencies
+// of its Connect platforms. Amounts are sent in ISO 4217 minor units, so currencies whose
+// Stripe units or amount rules differ from them are left out: ISK, the three-decimal
+// currencies and HUF, which Stripe charges only in whole forints.
+var stripeCurrencies = map[string]bool{
+	"AED": true, "AUD": true, "BGN": true, "BRL": true, "CAD": true, "CHF": true,
+	"CLP": true, "CZK": true, "DKK": true, "EUR": true, "GBP": true, "HKD": true,
+	"ILS": true, "INR": true, "JPY": true, "KRW": true, "MXN": true, "NOK": true,
+	"NZD": true, "PLN": true, "RON": true, "SEK": true, "SGD": true, "TRY": true,
+	"USD": true, "VND": true, "ZAR": true,
+}
+
 type stripePaymentProvider struct {
 	webhookSecret string
 }
 
 const (
-	metadataKeyListingID = "listing_id"
-	metadataKeyBuyerID   = "buyer_id"
+	metadataKeyListingID      = "listing_id"
+	metadataKeyBuyerID        = "buyer_id"
+	metadataKeyShippingOption = "shipping_option_id"
+	metadataKeyShippingMethod = "shipping_method"
+	metadataKeyShippingAmount = "shipping_amount"
 )
 
 // NewStripePaymentProvider returns stripePaymentProvider which implements the PaymentProvider interface using stripe.
@@ -115,8 +134,15 @@ func (p *stripePaymentProvider) CreateCheckoutSession(
 	_ context.Context,
 	req *dto.CheckoutSessionRequest,
 	listing *dto.Listing,
-	feeAmount int64,
+	shipping *dto.ShippingOption,
+	fee money.Money,
 ) (*dto.CheckoutSessionResponse, error) {
+	price, err := listing.Price()
+	if err != nil {
+		return nil, err
+	}
+
+	currency := strings.ToLower(price.Currency.Code)
 	checkoutItemName := fmt.Sprintf("Buying %s from @%s", listing.Title, listing.Seller.Username)
 
 	params := &stripe.CheckoutSessionParams{
@@ -125,7 +151,7 @@ func (p *stripePaymentProvider) CreateCheckoutSession(
 		CancelURL:  stripe.String(req.CancelURL),
 
 		PaymentIntentData: &stripe.CheckoutSessionPaymentIntentDataParams{
-			ApplicationFeeAmount: stripe.Int64(feeAmount),
+			ApplicationFeeAmount: stripe.Int64(fee.Amount),
 			TransferData: &stripe.CheckoutSessionPaymentIntentDataTransferDataParams{
 				Destination: stripe.String(*listing.Seller.SellerID),
 			},
@@ -138,27 +164,31 @@ func (p *stripePaymentProvider) CreateCheckoutSession(
 		LineItems: []*stripe.CheckoutSessionLineItemParams{
 			{
 				PriceData: &stripe.CheckoutSessionLineItemPriceDataParams{
-					Currency: stripe.String(listing.Currency),
+					Currency: stripe.String(currency),
 					ProductData: &stripe.CheckoutSessionLineItemPriceDataProductDataParams{
 						Name: stripe.String(checkoutItemName),
 					},
-					UnitAmount: stripe.Int64(int64(listing.PriceInCents)),
+					UnitAmount: stripe.Int64(price.Amount),
 				},
 				Quantity: stripe.Int64(1),
 			},
 			{
 				PriceData: &stripe.CheckoutSessionLineItemPriceDataParams{
-					Currency: stripe.String(listing.Currency),
+					Currency: stripe.String(currency),
 					ProductData: &stripe.CheckoutSessionLineItemPriceDataProductDataParams{
 						Name: stripe.String("marketplace fee"),
 					},
-					UnitAmount: stripe.Int64(feeAmount),
+					UnitAmount: stripe.Int64(fee.Amount),
 				},
 				Quantity: stripe.Int64(1),
 			},
 		},
 	}
 
+	if shipping != nil {
+		addShipping(params, currency, shipping)
+	}
+
 	s, err := session.New(params)
 	if err != nil {
 		return nil, fmt.Errorf("craeting stripe checkout session: %w", err)
@@ -212,7 +242,12 @@ func (p *stripePaymentProvider) VerifySuccessWebhook(
 		ProviderPaymentID: pi.ID,
 		AmountInCents:     int(pi.Amount) - int(pi.ApplicationFeeAmount),
 		FeeAmountInCents:  int(pi.ApplicationFeeAmount),
-		Currency:          string(pi.Currency),
+		Currency:          strings.ToUpper(string(pi.Currency)),
+	}
+
+	err = withShipping(payment, &pi)
+	if err != nil {
+		return nil, err
 	}
 
 	return payment, nil
@@ -266,8 +301,113 @@ func (p *stripePaymentProvider) VerifyRefundWebhook(
 		ProviderPaymentID: pi.ID,
 		AmountInCents:     int(pi.Amount) - int(pi.ApplicationFeeAmount),
 		FeeAmountInCents:  int(pi.ApplicationFeeAmount),
-		Currency:          string(pi.Currency),
+		Currency:          strings.ToUpper(string(pi.Currency)),
 	}
 
 	return payment, nil
 }
+
+func (p *stripePaymentProvider) SupportsCurrency(currency money.Currency) bool {
+	return stripeCurrencies[currency.Code]
+}
+
+func (p *stripePaymentProvider) SellerCurrencies(
+	_ context.Context,
+	sellerID string,
+) ([]string, error) {
+	acc, err := account.GetByID(sellerID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetching stripe seller account: %w", err)
+	}
+
+	currencies := []string{}
+
+	add := func(currency stripe.Currency) {
+		code := strings.ToUpper(string(currency))
+		if code != "" && !slices.Contains(currencies, code) {
+			currencies = append(currencies, code)
+		}
+	}
+
+	add(acc.DefaultCurrency)
+
+	if acc.ExternalAccounts != nil {
+		for _, ext := range acc.ExternalAccounts.Data {
+			switch {
+			case ext.BankAccount != nil:
+				add(ext.BankAccount.Currency)
+			case ext.Card != nil:
+				add(ext.Card.Currency)
+			}
+		}
+	}
+
+	return currencies, nil
+}
+
+// addShipping charges the shipping option as a separate line item and, unless the buyer
+// picks the item up, makes Checkout collect a delivery address in one of its regions.
+func addShipping(
+	params *stripe.CheckoutSessionParams,
+	currency string,
+	shipping *dto.ShippingOption,
+) {
+	metadata := params.PaymentIntentData.Metadata
+	metadata[metadataKeyShippingOption] = shipping.ID
+	metadata[metadataKeyShippingMethod] = string(shipping.Method)
+	metadata[metadataKeyShippingAmount] = strconv.Itoa(shipping.PriceInCents)
+
+	if shipping.PriceInCents > 0 {
+		params.LineItems = append(params.LineItems, &stripe.CheckoutSessionLineItemParams{
+			PriceData: &stripe.CheckoutSessionLineItemPriceDataParams{
+				Currency: stripe.String(currency),
+				ProductData: &stripe.CheckoutSessionLineItemPriceDataProductDataParams{
+					Name: stripe.String("Shipping: " + shipping.Name),
+				},
+				UnitAmount: stripe.Int64(int64(shipping.PriceInCents)),
+			},
+			Quantity: stripe.Int64(1),
+		})
+	}
+
+	if shipping.Method != dto.ShippingMethodLocalPickup {
+		params.ShippingAddressCollection = &stripe.CheckoutSessionShippingAddressCollectionParams{
+			AllowedCountries: stripe.StringSlice(shipping.Regions),
+		}
+	}
+}
+
+// withShipping copies the shipping option picked at checkout and the collected address
+// from the payment intent into the payment.
+func withShipping(payment *dto.Payment, pi *stripe.PaymentIntent) error {
+	optionID := pi.Metadata[metadataKeyShippingOption]
+	if optionID == "" {
+		return nil
+	}
+
+	amount, err := strconv.Atoi(pi.Metadata[metadataKeyShippingAmount])
+	if err != nil {
+		return fmt.Errorf("parsing shipping amount from payment metadata: %w", err)
+	}
+
+	method := dto.ShippingMethod(pi.Metadata[metadataKeyShippingMethod])
+
+	payment.ShippingOptionID = &optionID
+	payment.ShippingMethod = &method
+	payment.ShippingAmountInCents = amount
+
+	if pi.Shipping != nil && pi.Shipping.Address != nil {
+		payment.ShippingAddress = &dto.ShippingAddress{
+			Name:       pi.Shipping.Name,
+			Phone:      pi.Shipping.Phone,
+			Line1:      pi.Shipping.Address.Line1,
+			Line2:      pi.Shipping.Address.Line2,
+			City:       pi.Shipping.Address.City,
+			State:      pi.Shipping.Address.State,
+			PostalCode: pi.Shipping.Address.PostalCode,
+			Country:    pi.Shipping.Address.Country,
+		}
+	}
+
+	return nil
+}
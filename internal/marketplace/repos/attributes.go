@@ -0,0 +1,90 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/pkg/generate"
+)
+
+func (r *listingsRepo) GetCategoryAttributes(
+	ctx context.Context,
+	categoryID string,
+) ([]dto.CategoryAttribute, error) {
+	query := `
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id, 0 AS depth FROM listings.categories WHERE id = $1
+			UNION
+			SELECT c.id, c.parent_id, ancestors.depth + 1 FROM listings.categories c
+				JOIN ancestors ON c.id = ancestors.parent_id
+		)
+		SELECT
+			a.id, a.category_id, a.key, a.label, a.type, a.required, a.unit, a.options,
+			a.display_order
+		FROM listings.category_attributes a
+			JOIN ancestors ON ancestors.id = a.category_id
+		ORDER BY ancestors.depth DESC, a.display_order, a.key
+	`
+
+	attributes := []dto.CategoryAttribute{}
+
+	err := r.db.SelectContext(ctx, &attributes, query, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching category attributes from database: %w", err)
+	}
+
+	return attributes, nil
+}
+
+func (r *listingsRepo) SetCategoryAttributes(
+	ctx context.Context,
+	categoryID string,
+	attributes []dto.CategoryAttribute,
+) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting db transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	_, err = tx.ExecContext(
+		ctx,
+		`DELETE FROM listings.category_attributes WHERE category_id = $1`,
+		categoryID,
+	)
+	if err != nil {
+		return fmt.Errorf("deleting category attributes from database: %w", err)
+	}
+
+	query := `
+		INSERT INTO listings.category_attributes
+			(id, category_id, key, label, type, required, unit, options, display_order)
+		VALUES
+			(
+				:id, :category_id, :key, :label, :type, :required, :unit,
+				COALESCE(CAST(:options AS TEXT[]), '{}'), :display_order
+			)
+	`
+
+	for i := range attributes {
+		attributes[i].ID = generate.ID("attr")
+		attributes[i].CategoryID = categoryID
+
+		_, err = tx.NamedExecContext(ctx, query, attributes[i])
+		if err != nil {
+			return fmt.Errorf("inserting category attribute into database: %w", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("committing transaction for setting category attributes: %w", err)
+	}
+
+	return nil
+}
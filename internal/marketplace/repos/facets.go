@@ -0,0 +1,152 @@
+package repos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+)
+
+// maxSellerFacets limits seller facet to sellers with the most matching listings.
+const maxSellerFacets = 20
+
+// GetListingFacets calculates all facets of listings matching request filters with a single
+// query. Prices are split per currency into priceBuckets buckets of equal width.
+func (r *listingsRepo) GetListingFacets(
+	ctx context.Context,
+	req *dto.GetListingsRequest,
+	priceBuckets int,
+) (*dto.ListingFacets, error) {
+	query := `
+		WITH filtered AS (
+			SELECT l.id, l.user_id, l.category_id, l.price_in_cents, l.currency, l.attributes
+			FROM listings.listings l
+			WHERE ` + listingsFilter + `
+		),
+		category_counts AS (
+			SELECT category_id, COUNT(*) AS count
+			FROM filtered
+			GROUP BY category_id
+		),
+		price_bounds AS (
+			SELECT
+				currency,
+				MIN(price_in_cents) AS low,
+				GREATEST(
+					CEIL((MAX(price_in_cents) - MIN(price_in_cents) + 1)::numeric / $9),
+					1
+				)::int AS width
+			FROM filtered
+			GROUP BY currency
+		),
+		price_buckets AS (
+			SELECT
+				f.currency,
+				b.low + (f.price_in_cents - b.low) / b.width * b.width AS min_in_cents,
+				b.width,
+				COUNT(*) AS count
+			FROM filtered f
+				JOIN price_bounds b ON b.currency = f.currency
+			GROUP BY f.currency, min_in_cents, b.width
+		),
+		currency_counts AS (
+			SELECT currency, COUNT(*) AS count
+			FROM filtered
+			GROUP BY currency
+		),
+		seller_counts AS (
+			SELECT f.user_id, a.username, COUNT(*) AS count
+			FROM filtered f
+				JOIN auth.users a ON a.id = f.user_id
+			GROUP BY f.user_id, a.username
+			ORDER BY count DESC, a.username
+			LIMIT $10
+		),
+		attribute_counts AS (
+			SELECT e.key, e.value #>> '{}' AS value, COUNT(*) AS count
+			FROM filtered f
+				CROSS JOIN LATERAL jsonb_each(f.attributes) AS e(key, value)
+			WHERE
+				jsonb_typeof(e.value) IN ('string', 'boolean')
+				AND EXISTS (
+					SELECT 1 FROM listings.category_attributes ca
+					WHERE ca.key = e.key AND ca.type IN ('enum', 'boolean')
+				)
+			GROUP BY e.key, e.value #>> '{}'
+		),
+		attribute_facets AS (
+			SELECT
+				key,
+				json_agg(
+					json_build_object('value', value, 'count', count)
+					ORDER BY count DESC, value
+				) AS counts
+			FROM attribute_counts
+			GROUP BY key
+		)
+		SELECT json_build_object(
+			'categories', COALESCE((
+				SELECT json_agg(
+					json_build_object(
+						'category_id', c.id,
+						'parent_id', c.parent_id,
+						'title', c.title,
+						'slug', c.slug,
+						'count', cc.count
+					) ORDER BY cc.count DESC, c.title
+				)
+				FROM category_counts cc
+					JOIN listings.categories c ON c.id = cc.category_id
+			), '[]'),
+			'prices', COALESCE((
+				SELECT json_agg(
+					json_build_object(
+						'currency', currency,
+						'min_in_cents', min_in_cents,
+						'max_in_cents', min_in_cents + width - 1,
+						'count', count
+					) ORDER BY currency, min_in_cents
+				)
+				FROM price_buckets
+			), '[]'),
+			'currencies', COALESCE((
+				SELECT json_agg(
+					json_build_object('value', currency, 'count', count)
+					ORDER BY count DESC, currency
+				)
+				FROM currency_counts
+			), '[]'),
+			'sellers', COALESCE((
+				SELECT json_agg(
+					json_build_object('user_id', user_id, 'username', username, 'count', count)
+					ORDER BY count DESC, username
+				)
+				FROM seller_counts
+			), '[]'),
+			'attributes', COALESCE((
+				SELECT json_object_agg(key, counts) FROM attribute_facets
+			), '{}')
+		)
+	`
+
+	args, err := listingsFilterArgs(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw []byte
+
+	err = r.db.GetContext(ctx, &raw, query, append(args, priceBuckets, maxSellerFacets)...)
+	if err != nil {
+		return nil, fmt.Errorf("fetching listing facets from database: %w", err)
+	}
+
+	var facets dto.ListingFacets
+
+	err = json.Unmarshal(raw, &facets)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling listing facets: %w", err)
+	}
+
+	return &facets, nil
+}
@@ -0,0 +1,104 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+
+	"github.com/lib/pq"
+)
+
+func (r *listingsRepo) AddFavorite(ctx context.Context, req *dto.FavoriteRequest) error {
+	query := `
+		INSERT INTO listings.favorites (user_id, listing_id)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, listing_id) DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, query, req.UserID, req.ListingID)
+	if err != nil {
+		return fmt.Errorf("inserting favorite into database: %w", err)
+	}
+
+	return nil
+}
+
+func (r *listingsRepo) RemoveFavorite(ctx context.Context, req *dto.FavoriteRequest) error {
+	query := `
+		DELETE FROM listings.favorites WHERE user_id = $1 AND listing_id = $2
+	`
+
+	_, err := r.db.ExecContext(ctx, query, req.UserID, req.ListingID)
+	if err != nil {
+		return fmt.Errorf("deleting favorite from database: %w", err)
+	}
+
+	return nil
+}
+
+// GetFavoriteListings returns listings favorited by the user, newest favorites first.
+// Moderated listings of other sellers are left out, as they are only visible to their owner.
+func (r *listingsRepo) GetFavoriteListings(
+	ctx context.Context,
+	req *dto.GetFavoritesRequest,
+) ([]dto.Listing, error) {
+	query := listingsSelect + `
+			JOIN listings.favorites fav ON fav.listing_id = l.id AND fav.user_id = $1
+		WHERE
+			l.deleted_at IS NULL
+			AND (l.status NOT IN ('pending_review', 'hidden', 'removed') OR l.user_id = $1)
+		GROUP BY l.id, a.id, sa.id, c.title, fav.created_at
+		ORDER BY fav.created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	listings := []dto.Listing{}
+
+	err := r.db.SelectContext(ctx, &listings, query, req.UserID, req.Limit, req.Page*req.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("fetching favorite listings from database: %w", err)
+	}
+
+	return listings, nil
+}
+
+func (r *listingsRepo) GetFavoritedListingIDs(
+	ctx context.Context,
+	userID string,
+	listingIDs []string,
+) ([]string, error) {
+	query := `
+		SELECT listing_id FROM listings.favorites
+		WHERE user_id = $1 AND listing_id = ANY($2)
+	`
+
+	favorited := []string{}
+
+	err := r.db.SelectContext(ctx, &favorited, query, userID, pq.Array(listingIDs))
+	if err != nil {
+		return nil, fmt.Errorf("fetching favorited listing ids from database: %w", err)
+	}
+
+	return favorited, nil
+}
+
+func (r *listingsRepo) GetListingWatchers(
+	ctx context.Context,
+	listingID string,
+) ([]dto.Watcher, error) {
+	query := `
+		SELECT a.id as user_id, a.email
+		FROM listings.favorites f
+			JOIN auth.users a ON a.id = f.user_id
+		WHERE f.listing_id = $1
+	`
+
+	watchers := []dto.Watcher{}
+
+	err := r.db.SelectContext(ctx, &watchers, query, listingID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching listing watchers from database: %w", err)
+	}
+
+	return watchers, nil
+}
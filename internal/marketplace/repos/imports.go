@@ -0,0 +1,187 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/pkg/generate"
+	"time"
+)
+
+// importColumns selects import jobs without the uploaded files.
+const importColumns = `
+	id, user_id, format, status, total_rows, created_count, failed_count, results, error,
+	created_at, started_at, progressed_at, finished_at
+`
+
+func (r *listingsRepo) CreateImport(
+	ctx context.Context,
+	job *dto.ImportJob,
+) (*dto.ImportJob, error) {
+	query := `
+		INSERT INTO listings.imports (id, user_id, format, source, images, total_rows)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + importColumns
+
+	var created dto.ImportJob
+
+	err := r.db.GetContext(ctx, &created, query,
+		generate.ID("import"),
+		job.UserID,
+		job.Format,
+		job.Source,
+		job.Images,
+		job.TotalRows,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("inserting import into database: %w", err)
+	}
+
+	return &created, nil
+}
+
+func (r *listingsRepo) GetImport(
+	ctx context.Context,
+	importID, userID string,
+) (*dto.ImportJob, error) {
+	query := `SELECT ` + importColumns + ` FROM listings.imports WHERE id = $1 AND user_id = $2`
+
+	var job dto.ImportJob
+
+	err := r.db.GetContext(ctx, &job, query, importID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching import from database: %w", err)
+	}
+
+	return &job, nil
+}
+
+// ClaimImport marks the oldest pending import, or a running import without progress for
+// staleAfter, as running and returns it with its uploaded files and recorded results,
+// returning sql.ErrNoRows when there is nothing to process. Concurrent workers never claim
+// the same import.
+func (r *listingsRepo) ClaimImport(
+	ctx context.Context,
+	staleAfter time.Duration,
+) (*dto.ImportJob, error) {
+	query := `
+		UPDATE listings.imports
+		SET status = 'running', started_at = COALESCE(started_at, NOW()), progressed_at = NOW()
+		WHERE id = (
+			SELECT id FROM listings.imports
+			WHERE
+				status = 'pending'
+				OR (
+					status = 'running'
+					AND (
+						progressed_at IS NULL
+						OR progressed_at < NOW() - make_interval(secs => $1)
+					)
+				)
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING source, images, ` + importColumns
+
+	var job dto.ImportJob
+
+	err := r.db.GetContext(ctx, &job, query, staleAfter.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("claiming import in database: %w", err)
+	}
+
+	return &job, nil
+}
+
+// SaveImportProgress stores results of the rows processed so far, keeping the import from
+// being resumed by another worker.
+func (r *listingsRepo) SaveImportProgress(ctx context.Context, job *dto.ImportJob) error {
+	query := `
+		UPDATE listings.imports
+		SET created_count = $2, failed_count = $3, results = $4, progressed_at = NOW()
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		job.ID,
+		job.CreatedCount,
+		job.FailedCount,
+		job.Results,
+	)
+	if err != nil {
+		return fmt.Errorf("saving import progress in database: %w", err)
+	}
+
+	return nil
+}
+
+// GetImportedListingID returns the id of the listing created from the import row, returning
+// sql.ErrNoRows when the row didn't create a listing.
+func (r *listingsRepo) GetImportedListingID(
+	ctx context.Context,
+	importID string,
+	row int,
+) (string, error) {
+	query := `
+		SELECT id FROM listings.listings WHERE import_id = $1 AND import_row = $2
+	`
+
+	var listingID string
+
+	err := r.db.GetContext(ctx, &listingID, query, importID, row)
+	if err != nil {
+		return "", fmt.Errorf("fetching imported listing from database: %w", err)
+	}
+
+	return listingID, nil
+}
+
+// FinishImport stores results of a processed import and drops its uploaded files.
+func (r *listingsRepo) FinishImport(ctx context.Context, job *dto.ImportJob) error {
+	query := `
+		UPDATE listings.imports
+		SET
+			status = $2,
+			created_count = $3,
+			failed_count = $4,
+			results = $5,
+			error = $6,
+			source = NULL,
+			images = NULL,
+			finished_at = NOW()
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		job.ID,
+		job.Status,
+		job.CreatedCount,
+		job.FailedCount,
+		job.Results,
+		job.Error,
+	)
+	if err != nil {
+		return fmt.Errorf("finishing import in database: %w", err)
+	}
+
+	return nil
+}
+
+// GetUserListings returns all listings of the user which weren't deleted, oldest first.
+func (r *listingsRepo) GetUserListings(ctx context.Context, userID string) ([]dto.Listing, error) {
+	query := listingsSelect + `
+		WHERE l.user_id = $1 AND l.deleted_at IS NULL
+		GROUP BY l.id, a.id, sa.id, c.title
+		ORDER BY l.created_at
+	`
+
+	listings := []dto.Listing{}
+
+	err := r.db.SelectContext(ctx, &listings, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching user listings from database: %w", err)
+	}
+
+	return listings, nil
+}
@@ -3,28 +3,147 @@ package repos
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"golang-connect-marketplace/internal/marketplace/dto"
 	"golang-connect-marketplace/pkg/generate"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
-// ErrNoRowsReturned is returned if database query returns no results.
-var ErrNoRowsReturned = errors.New("no rows returned")
+// uniqueViolation is the postgres error code of unique constraint violations.
+const uniqueViolation = "23505"
+
+var (
+	// ErrNoRowsReturned is returned if database query returns no results.
+	ErrNoRowsReturned = errors.New("no rows returned")
+	// ErrCategoryConflict is returned when an active category already has the slug, or the
+	// title under the same parent.
+	ErrCategoryConflict = errors.New("category already exists")
+)
 
 // ListingsRepo defines methods for accessing and managing listings data.
 type ListingsRepo interface {
 	CreateCategory(ctx context.Context, req *dto.Category) (*dto.Category, error)
 	GetCategories(ctx context.Context) ([]dto.Category, error)
-	CreateListing(ctx context.Context, req *dto.Listing) (*dto.Listing, error)
+	GetCategoryByID(ctx context.Context, categoryID string) (*dto.Category, error)
+	GetCategoryListingDuration(ctx context.Context, categoryID string) (*int, error)
+	UpdateCategory(ctx context.Context, req *dto.UpdateCategoryRequest) (*dto.Category, error)
+	DeleteCategory(ctx context.Context, categoryID string) error
+	CreateListing(
+		ctx context.Context,
+		req *dto.Listing,
+		findings []dto.ScreeningFinding,
+	) (*dto.Listing, error)
 	CheckIfUserOwnsListing(ctx context.Context, listingID, userID string) error
 	GetListingByID(ctx context.Context, listingID string) (*dto.Listing, error)
-	AddListingImage(ctx context.Context, listingID, path string) (*dto.ListingImage, error)
+	AddListingImages(
+		ctx context.Context,
+		listingID string,
+		images []dto.ListingImage,
+	) ([]dto.ListingImage, error)
+	GetReferencedImageKeys(ctx context.Context) ([]string, error)
 	DeleteListingImage(ctx context.Context, req *dto.DeleteImageRequest) error
-	UpdateListing(ctx context.Context, req *dto.UpdateListingRequest) (*dto.Listing, error)
+	ReorderListingImages(ctx context.Context, req *dto.ReorderImagesRequest) error
+	UpdateListingImage(ctx context.Context, req *dto.UpdateImageRequest) error
+	UpdateListing(
+		ctx context.Context,
+		req *dto.UpdateListingRequest,
+		findings []dto.ScreeningFinding,
+	) (*dto.Listing, error)
+	GetListingRevisions(
+		ctx context.Context,
+		req *dto.GetListingRevisionsRequest,
+	) ([]dto.ListingRevision, error)
+	GetPriceHistory(ctx context.Context, listingID string) ([]dto.PriceChange, error)
+	GetUserListings(ctx context.Context, userID string) ([]dto.Listing, error)
+	CreateImport(ctx context.Context, job *dto.ImportJob) (*dto.ImportJob, error)
+	GetImport(ctx context.Context, importID, userID string) (*dto.ImportJob, error)
+	ClaimImport(ctx context.Context, staleAfter time.Duration) (*dto.ImportJob, error)
+	SaveImportProgress(ctx context.Context, job *dto.ImportJob) error
+	GetImportedListingID(ctx context.Context, importID string, row int) (string, error)
+	FinishImport(ctx context.Context, job *dto.ImportJob) error
 	GetListings(ctx context.Context, req *dto.GetListingsRequest) ([]dto.Listing, error)
+	GetListingFacets(
+		ctx context.Context,
+		req *dto.GetListingsRequest,
+		priceBuckets int,
+	) (*dto.ListingFacets, error)
+	GetCategoryAttributes(ctx context.Context, categoryID string) ([]dto.CategoryAttribute, error)
+	AddFavorite(ctx context.Context, req *dto.FavoriteRequest) error
+	RemoveFavorite(ctx context.Context, req *dto.FavoriteRequest) error
+	GetFavoriteListings(ctx context.Context, req *dto.GetFavoritesRequest) ([]dto.Listing, error)
+	GetFavoritedListingIDs(
+		ctx context.Context,
+		userID string,
+		listingIDs []string,
+	) ([]string, error)
+	GetListingWatchers(ctx context.Context, listingID string) ([]dto.Watcher, error)
+	CreateSavedSearch(
+		ctx context.Context,
+		req *dto.CreateSavedSearchRequest,
+	) (*dto.SavedSearch, error)
+	CountSavedSearches(ctx context.Context, userID string) (int, error)
+	GetSavedSearches(ctx context.Context, userID string) ([]dto.SavedSearch, error)
+	GetAllSavedSearches(ctx context.Context) ([]dto.SavedSearch, error)
+	UpdateSavedSearch(
+		ctx context.Context,
+		req *dto.UpdateSavedSearchRequest,
+	) (*dto.SavedSearch, error)
+	DeleteSavedSearch(ctx context.Context, searchID, userID string) error
+	CreateShippingProfile(
+		ctx context.Context,
+		req *dto.CreateShippingProfileRequest,
+	) (*dto.ShippingProfile, error)
+	GetShippingProfiles(ctx context.Context, userID string) ([]dto.ShippingProfile, error)
+	GetShippingProfile(ctx context.Context, profileID, userID string) (*dto.ShippingProfile, error)
+	UpdateShippingProfile(
+		ctx context.Context,
+		req *dto.UpdateShippingProfileRequest,
+	) (*dto.ShippingProfile, error)
+	DeleteShippingProfile(ctx context.Context, profileID, userID string) error
+	SetListingShippingProfile(ctx context.Context, req *dto.SetListingShippingRequest) error
+	SetListingLocation(ctx context.Context, req *dto.SetListingLocationRequest) error
+	GetUnmatchedListingIDs(ctx context.Context) ([]string, error)
+	MarkListingsSearchMatched(ctx context.Context, listingIDs []string) error
+	MatchSavedSearch(
+		ctx context.Context,
+		search *dto.SavedSearch,
+		listingIDs []string,
+	) ([]dto.SavedSearchMatch, error)
+	GetPendingDigestMatches(ctx context.Context) ([]dto.SavedSearchMatch, error)
+	MarkSavedSearchMatchesNotified(ctx context.Context, matches []dto.SavedSearchMatch) error
+	GetSellerProfile(ctx context.Context, username string) (*dto.SellerProfile, error)
+	GetSellerProfileByID(ctx context.Context, userID string) (*dto.SellerProfile, error)
+	UpdateSellerProfile(ctx context.Context, req *dto.UpdateSellerProfileRequest) error
+	GetSellerStanding(ctx context.Context, userID string) (*dto.SellerStanding, error)
+	GetSellerAccountID(ctx context.Context, userID string) (*string, error)
+	GetCategoryPriceStats(
+		ctx context.Context,
+		categoryID, currency, excludeListingID string,
+	) (*dto.CategoryPriceStats, error)
+	GetSellerListingTexts(
+		ctx context.Context,
+		userID, excludeListingID string,
+		limit int,
+	) ([]dto.ListingText, error)
+	SetCategoryAttributes(
+		ctx context.Context,
+		categoryID string,
+		attributes []dto.CategoryAttribute,
+	) error
+	RenewListing(ctx context.Context, listingID string, expiresAt time.Time) (*dto.Listing, error)
+	ExpireListings(
+		ctx context.Context,
+		from []dto.ListingStatus,
+	) ([]dto.ListingExpiryNotice, error)
+	MarkListingsExpiringBefore(
+		ctx context.Context,
+		before time.Time,
+	) ([]dto.ListingExpiryNotice, error)
 }
 
 type listingsRepo struct {
@@ -43,20 +162,35 @@ func (r *listingsRepo) CreateCategory(
 	req.ID = generate.ID("cat")
 
 	query := `
-		INSERT INTO listings.categories (id, title, description, color, image_path) 
-		VALUES (:id, :title, :description, :color, :image_path)
-		RETURNING id, title, description, color, image_path
+		INSERT INTO listings.categories
+			(
+				id, parent_id, title, slug, description, color, display_order,
+				image_path, image_variants, listing_duration_days
+			)
+		VALUES
+			(
+				:id, :parent_id, :title, :slug, :description, :color, :display_order,
+				:image_path, :image_variants, :listing_duration_days
+			)
+		RETURNING
+			id, parent_id, title, slug, description, color, display_order,
+			image_path, image_variants, listing_duration_days
 	`
 
 	row, err := r.db.NamedQueryContext(ctx, query, req)
 	if err != nil {
-		return nil, fmt.Errorf("inserting category into database: %w", err)
+		return nil, fmt.Errorf("inserting category into database: %w", categoryError(err))
 	}
 
 	defer func() { _ = row.Close() }()
 
 	ok := row.Next()
 	if !ok {
+		err = row.Err()
+		if err != nil {
+			return nil, fmt.Errorf("inserting category into database: %w", categoryError(err))
+		}
+
 		return nil, ErrNoRowsReturned
 	}
 
@@ -74,10 +208,12 @@ func (r *listingsRepo) GetCategories(ctx context.Context) ([]dto.Category, error
 	categories := []dto.Category{}
 
 	query := `
-		SELECT id, title, description, color, image_path
+		SELECT
+			id, parent_id, title, slug, description, color, display_order,
+			image_path, image_variants, listing_duration_days
 		FROM listings.categories
 		WHERE deleted_at IS NULL
-		ORDER BY title
+		ORDER BY display_order, title
 	`
 
 	err := r.db.SelectContext(ctx, &categories, query)
@@ -88,32 +224,197 @@ func (r *listingsRepo) GetCategories(ctx context.Context) ([]dto.Category, error
 	return categories, nil
 }
 
-func (r *listingsRepo) CreateListing(ctx context.Context, req *dto.Listing) (*dto.Listing, error) {
-	req.ID = generate.ID("item")
+func (r *listingsRepo) GetCategoryByID(
+	ctx context.Context,
+	categoryID string,
+) (*dto.Category, error) {
+	query := `
+		SELECT
+			id, parent_id, title, slug, description, color, display_order,
+			image_path, image_variants, listing_duration_days
+		FROM listings.categories
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	var category dto.Category
+
+	err := r.db.GetContext(ctx, &category, query, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching category by id from database: %w", err)
+	}
+
+	return &category, nil
+}
 
+// GetCategoryListingDuration returns the listing duration of a category, including a deleted
+// one, since listings keep the category they were created in.
+func (r *listingsRepo) GetCategoryListingDuration(
+	ctx context.Context,
+	categoryID string,
+) (*int, error) {
 	query := `
-		INSERT INTO listings.listings (id, user_id, category_id, title, description, price_in_cents, currency) 
-		VALUES (:id, :user_id, :category_id, :title, :description, :price_in_cents, :currency)
-		RETURNING id, user_id, category_id, title, description, price_in_cents, currency, status, created_at, updated_at
+		SELECT listing_duration_days
+		FROM listings.categories
+		WHERE id = $1
 	`
 
-	row, err := r.db.NamedQueryContext(ctx, query, req)
+	var days *int
+
+	err := r.db.GetContext(ctx, &days, query, categoryID)
 	if err != nil {
-		return nil, fmt.Errorf("inserting new listing to database: %w", err)
+		return nil, fmt.Errorf("fetching category listing duration from database: %w", err)
 	}
 
-	defer func() { _ = row.Close() }()
+	return days, nil
+}
 
-	ok := row.Next()
-	if !ok {
+func (r *listingsRepo) UpdateCategory(
+	ctx context.Context,
+	req *dto.UpdateCategoryRequest,
+) (*dto.Category, error) {
+	query := `
+		UPDATE listings.categories
+		SET
+			parent_id = CASE WHEN $2::text IS NULL THEN parent_id ELSE NULLIF($2, '') END,
+			title = COALESCE($3, title),
+			slug = COALESCE($4, slug),
+			description = COALESCE($5, description),
+			color = COALESCE($6, color),
+			display_order = COALESCE($7, display_order),
+			listing_duration_days = COALESCE($8, listing_duration_days),
+			image_path = COALESCE($9, image_path),
+			image_variants = CASE WHEN $9::text IS NULL THEN image_variants ELSE $10 END,
+			updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		req.ID,
+		req.ParentID,
+		req.Title,
+		req.Slug,
+		req.Description,
+		req.Color,
+		req.DisplayOrder,
+		req.ListingDurationDays,
+		req.ImagePath,
+		req.ImageVariants,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("updating category in database: %w", categoryError(err))
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("reading affected rows of category update: %w", err)
+	}
+
+	if affected == 0 {
 		return nil, ErrNoRowsReturned
 	}
 
+	updated, err := r.GetCategoryByID(ctx, req.ID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching updated category: %w", err)
+	}
+
+	return updated, nil
+}
+
+func (r *listingsRepo) DeleteCategory(ctx context.Context, categoryID string) error {
+	query := `
+		UPDATE listings.categories
+		SET deleted_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, categoryID)
+	if err != nil {
+		return fmt.Errorf("deleting category from database: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("reading affected rows of category delete: %w", err)
+	}
+
+	if affected == 0 {
+		return ErrNoRowsReturned
+	}
+
+	return nil
+}
+
+// categoryError returns ErrCategoryConflict for unique violations, other errors are returned
+// unchanged.
+func categoryError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return fmt.Errorf("%w: %w", ErrCategoryConflict, err)
+	}
+
+	return err
+}
+
+// CreateListing inserts a listing together with reports of its screening findings, in one
+// transaction.
+func (r *listingsRepo) CreateListing(
+	ctx context.Context,
+	req *dto.Listing,
+	findings []dto.ScreeningFinding,
+) (*dto.Listing, error) {
+	req.ID = generate.ID("item")
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("starting db transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	query, args, err := tx.BindNamed(`
+		INSERT INTO listings.listings
+			(
+				id, user_id, category_id, title, description, price_in_cents, currency,
+				attributes, shipping_profile_id, latitude, longitude, location_city, status,
+				expires_at, import_id, import_row
+			)
+		VALUES
+			(
+				:id, :user_id, :category_id, :title, :description, :price_in_cents, :currency,
+				:attributes, :shipping_profile_id, :latitude, :longitude, :location_city, :status,
+				:expires_at, :import_id, :import_row
+			)
+		RETURNING
+			id, user_id, category_id, title, description, price_in_cents, currency, attributes,
+			shipping_profile_id, latitude, longitude, location_city, status, expires_at,
+			created_at, updated_at
+	`, req)
+	if err != nil {
+		return nil, fmt.Errorf("binding new listing: %w", err)
+	}
+
 	var resp dto.Listing
 
-	err = row.StructScan(&resp)
+	err = tx.GetContext(ctx, &resp, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("inserting new listing to database: %w", err)
+	}
+
+	err = insertScreeningReports(ctx, tx, resp.ID, findings)
 	if err != nil {
-		return nil, fmt.Errorf("scanning inserted listing row into struct: %w", err)
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("committing transaction for creating listing: %w", err)
 	}
 
 	return &resp, nil
@@ -136,30 +437,46 @@ func (r *listingsRepo) CheckIfUserOwnsListing(ctx context.Context, listingID, us
 	return nil
 }
 
+// listingsSelect selects listings along with their category, seller with reputation,
+// images, favorite count and shipping options. Queries using it must group by l.id, a.id,
+// sa.id, c.title.
+var listingsSelect = `
+	SELECT
+		l.*,
+		c.title as "category_title",
+		a.id as "seller.id",
+		a.username as "seller.username",
+		a.created_at as "seller.created_at",
+		sa.id as "seller.seller_id",
+		` + reputationColumns("a.id", "seller.reputation.") + `,
+		COALESCE(
+			json_agg(
+				json_build_object(
+					'id', i.id,
+					'listing_id', i.listing_id,
+					'path', i.path,
+					'position', i.position,
+					'is_primary', i.is_primary,
+					'alt_text', i.alt_text,
+					'variants', i.variants
+				) ORDER BY i.position, i.created_at
+			) FILTER (WHERE i.id IS NOT NULL),
+			'[]'
+		) AS images,
+		(SELECT COUNT(*) FROM listings.favorites f WHERE f.listing_id = l.id) AS favorite_count,
+		(
+			SELECT sp.options FROM listings.shipping_profiles sp
+			WHERE sp.id = l.shipping_profile_id AND sp.currency = l.currency
+		) AS shipping_options
+	FROM listings.listings l
+		LEFT JOIN listings.listings_images i ON i.listing_id = l.id
+		LEFT JOIN auth.users a on a.id = l.user_id
+		LEFT JOIN payments.seller_accounts sa on sa.user_id = a.id
+		LEFT JOIN listings.categories c on c.id = l.category_id
+`
+
 func (r *listingsRepo) GetListingByID(ctx context.Context, listingID string) (*dto.Listing, error) {
-	query := `
-		SELECT
-			l.*,
-			c.title as "category_title",
-			a.id as "seller.id",
-			a.username as "seller.username",
-			a.created_at as "seller.created_at",
-			sa.id as "seller.seller_id",
-			COALESCE(
-				json_agg(
-					json_build_object(
-						'id', i.id,
-						'listing_id', i.listing_id,
-						'path', i.path
-					) ORDER BY i.created_at
-				) FILTER (WHERE i.id IS NOT NULL),
-				'[]'
-			) AS images
-		FROM listings.listings l
-			LEFT JOIN listings.listings_images i ON i.listing_id = l.id
-			LEFT JOIN auth.users a on a.id = l.user_id
-			LEFT JOIN payments.seller_accounts sa on sa.user_id = a.id
-			LEFT JOIN listings.categories c on c.id = l.category_id
+	query := listingsSelect + `
 		WHERE l.id = $1
 		GROUP BY l.id, a.id, sa.id, c.title
 	`
@@ -174,47 +491,289 @@ func (r *listingsRepo) GetListingByID(ctx context.Context, listingID string) (*d
 	return &listing, nil
 }
 
-func (r *listingsRepo) AddListingImage(
+func (r *listingsRepo) AddListingImages(
 	ctx context.Context,
-	listingID, path string,
-) (*dto.ListingImage, error) {
-	id := generate.ID("img")
+	listingID string,
+	images []dto.ListingImage,
+) ([]dto.ListingImage, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("starting db transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
 
 	query := `
-		INSERT INTO listings.listings_images (id, listing_id, path) 
-		VALUES ($1, $2, $3)
-		RETURNING id, listing_id, path
+		INSERT INTO listings.listings_images (id, listing_id, path, variants, position, is_primary)
+		SELECT
+			$1, $2, $3, $4,
+			COALESCE(MAX(position) + 1, 0),
+			NOT COALESCE(BOOL_OR(is_primary), FALSE)
+		FROM listings.listings_images
+		WHERE listing_id = $2
+		RETURNING id, listing_id, path, position, is_primary, alt_text, variants
 	`
 
-	var img dto.ListingImage
+	inserted := make([]dto.ListingImage, 0, len(images))
+
+	for _, img := range images {
+		var row dto.ListingImage
+
+		err = tx.GetContext(
+			ctx,
+			&row,
+			query,
+			generate.ID("img"),
+			listingID,
+			img.Path,
+			img.Variants,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("inserting listing image: %w", err)
+		}
+
+		inserted = append(inserted, row)
+	}
 
-	err := r.db.GetContext(ctx, &img, query, id, listingID, path)
+	err = tx.Commit()
 	if err != nil {
-		return nil, fmt.Errorf("inserting listing image: %w", err)
+		return nil, fmt.Errorf("committing transaction for inserting listing images: %w", err)
 	}
 
-	return &img, nil
+	return inserted, nil
 }
 
-func (r *listingsRepo) DeleteListingImage(ctx context.Context, req *dto.DeleteImageRequest) error {
+func (r *listingsRepo) GetReferencedImageKeys(ctx context.Context) ([]string, error) {
 	query := `
+		SELECT path FROM listings.listings_images
+		UNION
+		SELECT v->>'path' FROM listings.listings_images, jsonb_each(variants) AS e(name, v)
+		UNION
+		SELECT image_path FROM listings.categories
+		UNION
+		SELECT v->>'path' FROM listings.categories, jsonb_each(image_variants) AS e(name, v)
+		UNION
+		SELECT avatar_path FROM listings.seller_profiles WHERE avatar_path IS NOT NULL
+		UNION
+		SELECT v->>'path'
+		FROM listings.seller_profiles, jsonb_each(avatar_variants) AS e(name, v)
+	`
+
+	keys := []string{}
+
+	err := r.db.SelectContext(ctx, &keys, query)
+	if err != nil {
+		return nil, fmt.Errorf("fetching referenced image keys from database: %w", err)
+	}
+
+	return keys, nil
+}
+
+func (r *listingsRepo) DeleteListingImage(ctx context.Context, req *dto.DeleteImageRequest) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting db transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	deleteQ := `
 		DELETE FROM listings.listings_images
 		WHERE listing_id = $1 and id = $2
 	`
 
-	_, err := r.db.ExecContext(ctx, query, req.ListingID, req.ImageID)
+	_, err = tx.ExecContext(ctx, deleteQ, req.ListingID, req.ImageID)
 	if err != nil {
 		return fmt.Errorf("deleting listing image from database: %w", err)
 	}
 
+	err = r.ensurePrimaryImage(ctx, tx, req.ListingID)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("committing transaction for deleting listing image: %w", err)
+	}
+
 	return nil
 }
 
+func (r *listingsRepo) ReorderListingImages(
+	ctx context.Context,
+	req *dto.ReorderImagesRequest,
+) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting db transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	// primary flag is cleared first, the unique index allows only one primary image per listing.
+	unsetPrimaryQ := `
+		UPDATE listings.listings_images
+		SET is_primary = FALSE
+		WHERE listing_id = $1 AND is_primary
+	`
+
+	_, err = tx.ExecContext(ctx, unsetPrimaryQ, req.ListingID)
+	if err != nil {
+		return fmt.Errorf("unsetting primary listing image: %w", err)
+	}
+
+	reorderQ := `
+		UPDATE listings.listings_images i
+		SET
+			position = o.position - 1,
+			is_primary = i.id = $3,
+			updated_at = NOW()
+		FROM unnest($2::text[]) WITH ORDINALITY AS o(id, position)
+		WHERE i.id = o.id AND i.listing_id = $1
+	`
+
+	_, err = tx.ExecContext(
+		ctx,
+		reorderQ,
+		req.ListingID,
+		pq.Array(req.ImageIDs),
+		req.PrimaryImageID,
+	)
+	if err != nil {
+		return fmt.Errorf("reordering listing images in database: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("committing transaction for reordering listing images: %w", err)
+	}
+
+	return nil
+}
+
+func (r *listingsRepo) UpdateListingImage(ctx context.Context, req *dto.UpdateImageRequest) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting db transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if req.IsPrimary != nil && *req.IsPrimary {
+		unsetPrimaryQ := `
+			UPDATE listings.listings_images
+			SET is_primary = FALSE
+			WHERE listing_id = $1 AND id <> $2 AND is_primary
+		`
+
+		_, err = tx.ExecContext(ctx, unsetPrimaryQ, req.ListingID, req.ImageID)
+		if err != nil {
+			return fmt.Errorf("unsetting primary listing image: %w", err)
+		}
+	}
+
+	updateQ := `
+		UPDATE listings.listings_images
+		SET
+			alt_text = CASE WHEN $3::text IS NULL THEN alt_text ELSE NULLIF($3, '') END,
+			is_primary = COALESCE($4, is_primary),
+			updated_at = NOW()
+		WHERE listing_id = $1 AND id = $2
+	`
+
+	_, err = tx.ExecContext(ctx, updateQ, req.ListingID, req.ImageID, req.AltText, req.IsPrimary)
+	if err != nil {
+		return fmt.Errorf("updating listing image in database: %w", err)
+	}
+
+	err = r.ensurePrimaryImage(ctx, tx, req.ListingID)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("committing transaction for updating listing image: %w", err)
+	}
+
+	return nil
+}
+
+// ensurePrimaryImage flags the first image of a listing as primary if none is flagged.
+func (r *listingsRepo) ensurePrimaryImage(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	listingID string,
+) error {
+	query := `
+		UPDATE listings.listings_images
+		SET is_primary = TRUE
+		WHERE id = (
+			SELECT id
+			FROM listings.listings_images
+			WHERE listing_id = $1
+			ORDER BY position, created_at
+			LIMIT 1
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM listings.listings_images WHERE listing_id = $1 AND is_primary
+		)
+	`
+
+	_, err := tx.ExecContext(ctx, query, listingID)
+	if err != nil {
+		return fmt.Errorf("ensuring listing has primary image: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateListing updates a listing and records the changed fields as a revision, together with
+// reports of its screening findings, in one transaction. Updates which don't change anything
+// leave no revision.
 func (r *listingsRepo) UpdateListing(
 	ctx context.Context,
 	req *dto.UpdateListingRequest,
+	findings []dto.ScreeningFinding,
 ) (*dto.Listing, error) {
-	query := `
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("starting db transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var before revisionFields
+
+	err = tx.GetContext(ctx, &before, `
+		SELECT `+revisionColumns+` FROM listings.listings WHERE id = $1 FOR UPDATE
+	`, req.ID)
+	if err != nil {
+		return nil, fmt.Errorf("locking listing for update: %w", err)
+	}
+
+	updateQ, args, err := tx.BindNamed(`
 		UPDATE listings.listings
 		SET
 			category_id = COALESCE(NULLIF(:category_id, ''), category_id),
@@ -222,24 +781,129 @@ func (r *listingsRepo) UpdateListing(
 			description  = COALESCE(NULLIF(:description, ''), description),
 			price_in_cents  = COALESCE(NULLIF(:price_in_cents, 0), price_in_cents),
 			currency  = COALESCE(NULLIF(:currency, ''), currency),
+			attributes = COALESCE(:attributes, attributes),
 			status = COALESCE(:status, status),
+			expires_at = COALESCE(:expires_at, expires_at),
+			expiry_notified_at = CASE
+				WHEN CAST(:expires_at AS timestamptz) IS NULL THEN expiry_notified_at
+			END,
 			updated_at = NOW()
 		WHERE id = :id
-	`
+		RETURNING `+revisionColumns, req)
+	if err != nil {
+		return nil, fmt.Errorf("binding listing update: %w", err)
+	}
 
-	row, err := r.db.NamedQueryContext(ctx, query, req)
+	var after revisionFields
+
+	err = tx.GetContext(ctx, &after, updateQ, args...)
 	if err != nil {
 		return nil, fmt.Errorf("updating listing in database: %w", err)
 	}
 
-	defer func() { _ = row.Close() }()
+	changes := before.changes(&after)
+	if len(changes) > 0 {
+		err = insertRevision(ctx, tx, req, changes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = insertScreeningReports(ctx, tx, req.ID, findings)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("committing transaction for updating listing: %w", err)
+	}
 
 	updatedListing, err := r.GetListingByID(ctx, req.ID)
 	if err != nil {
 		return nil, fmt.Errorf("fetching updated listing: %w", err)
 	}
 
-	return updatedListing, err
+	return updatedListing, nil
+}
+
+// listingsFilter selects open listings matching GetListingsRequest filters,
+// its arguments are built by listingsFilterArgs. The radius prefilters listings by latitude,
+// a degree of latitude being 111.195 km on the sphere used by listingDistance.
+var listingsFilter = `
+	l.status = 'open'
+	AND (l.expires_at IS NULL OR l.expires_at > NOW())
+	AND NOT EXISTS (SELECT 1 FROM moderation.suspensions s WHERE s.user_id = l.user_id)
+	AND ($1::text IS NULL OR l.category_id IN (
+		WITH RECURSIVE matched AS (
+			SELECT id FROM listings.categories
+			WHERE
+				deleted_at IS NULL
+				AND (slug = lower($1) OR title ILIKE '%' || $1 || '%')
+			UNION
+			SELECT child.id FROM listings.categories child
+				JOIN matched ON child.parent_id = matched.id
+			WHERE child.deleted_at IS NULL
+		)
+		SELECT id FROM matched
+	))
+	AND ($2::text IS NULL OR l.title ILIKE '%' || $2 || '%')
+	AND ($5::text IS NULL OR l.user_id = $5)
+	AND NOT EXISTS (
+		SELECT 1 FROM jsonb_each($3::jsonb) AS f(key, vals)
+		WHERE NOT COALESCE(
+			l.attributes ->> f.key IN (SELECT jsonb_array_elements_text(f.vals)),
+			FALSE
+		)
+	)
+	AND NOT EXISTS (
+		SELECT 1 FROM jsonb_each($4::jsonb) AS r(key, bounds)
+		WHERE CASE
+			WHEN jsonb_typeof(l.attributes -> r.key) = 'number' THEN
+				(l.attributes ->> r.key)::numeric
+					< COALESCE((r.bounds ->> 'min')::numeric, (l.attributes ->> r.key)::numeric)
+				OR (l.attributes ->> r.key)::numeric
+					> COALESCE((r.bounds ->> 'max')::numeric, (l.attributes ->> r.key)::numeric)
+			ELSE TRUE
+		END
+	)
+	AND ($6::float8 IS NULL OR $8::float8 IS NULL OR (
+		l.latitude BETWEEN $6::float8 - $8::float8 / 111.195 AND $6::float8 + $8::float8 / 111.195
+		AND ` + listingDistance("l") + ` <= $8::float8
+	))
+`
+
+func listingsFilterArgs(req *dto.GetListingsRequest) ([]any, error) {
+	attributeFilters := req.Attributes
+	if attributeFilters == nil {
+		attributeFilters = map[string][]string{}
+	}
+
+	rangeFilters := req.AttributeRanges
+	if rangeFilters == nil {
+		rangeFilters = map[string]dto.AttributeRange{}
+	}
+
+	attributes, err := json.Marshal(attributeFilters)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling attribute filters: %w", err)
+	}
+
+	ranges, err := json.Marshal(rangeFilters)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling attribute range filters: %w", err)
+	}
+
+	return []any{
+		req.Category,
+		req.Keyword,
+		string(attributes),
+		string(ranges),
+		req.SellerID,
+		req.NearLatitude,
+		req.NearLongitude,
+		req.RadiusKm,
+	}, nil
 }
 
 func (r *listingsRepo) GetListings(
@@ -247,51 +911,110 @@ func (r *listingsRepo) GetListings(
 	req *dto.GetListingsRequest,
 ) ([]dto.Listing, error) {
 	query := `
-		SELECT
-			l.*,
-			c.title as "category_title",
-			a.id as "seller.id",
-			a.username as "seller.username",
-			a.created_at as "seller.created_at",
-			sa.id as "seller.seller_id",
-			COALESCE(
-				json_agg(
-					json_build_object(
-						'id', i.id,
-						'listing_id', i.listing_id,
-						'path', i.path
-					) ORDER BY i.created_at
-				) FILTER (WHERE i.id IS NOT NULL),
-				'[]'
-			) AS images
-		FROM listings.listings l
-			LEFT JOIN listings.listings_images i ON i.listing_id = l.id
-			LEFT JOIN auth.users a on a.id = l.user_id
-			LEFT JOIN payments.seller_accounts sa on sa.user_id = a.id
-			LEFT JOIN listings.categories c on c.id = l.category_id
-		WHERE 
-			l.status = 'open'
-			AND ($3::text IS NULL OR c.title ILIKE '%' || $3 || '%')
-			AND ($4::text IS NULL OR l.title ILIKE '%' || $4 || '%')
-		GROUP BY l.id, a.id, sa.id, c.title
-		ORDER BY l.created_at DESC
-		LIMIT $1 OFFSET $2;
+		SELECT fl.*, ` + listingDistance("fl") + ` AS distance_km
+		FROM (
+			` + listingsSelect + `
+			WHERE ` + listingsFilter + `
+			GROUP BY l.id, a.id, sa.id, c.title
+		) fl
+		ORDER BY distance_km ASC NULLS LAST, fl.created_at DESC
+		LIMIT $9 OFFSET $10;
 	`
 
+	args, err := listingsFilterArgs(req)
+	if err != nil {
+		return nil, err
+	}
+
 	listings := []dto.Listing{}
 
-	err := r.db.SelectContext(
-		ctx,
-		&listings,
-		query,
-		req.Limit,
-		req.Page*req.Limit,
-		req.Category,
-		req.Keyword,
-	)
+	err = r.db.SelectContext(ctx, &listings, query, append(args, req.Limit, req.Page*req.Limit)...)
 	if err != nil {
 		return nil, fmt.Errorf("fetching listings from database: %w", err)
 	}
 
 	return listings, nil
 }
+
+func (r *listingsRepo) RenewListing(
+	ctx context.Context,
+	listingID string,
+	expiresAt time.Time,
+) (*dto.Listing, error) {
+	query := `
+		UPDATE listings.listings
+		SET
+			status = 'open',
+			expires_at = $2,
+			expiry_notified_at = NULL,
+			search_matched_at = CASE WHEN status = 'expired' THEN NULL ELSE search_matched_at END,
+			updated_at = NOW()
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, listingID, expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("renewing listing in database: %w", err)
+	}
+
+	renewedListing, err := r.GetListingByID(ctx, listingID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching renewed listing: %w", err)
+	}
+
+	return renewedListing, nil
+}
+
+// ExpireListings moves listings in one of the from statuses past their expiry date
+// to expired status.
+func (r *listingsRepo) ExpireListings(
+	ctx context.Context,
+	from []dto.ListingStatus,
+) ([]dto.ListingExpiryNotice, error) {
+	query := `
+		UPDATE listings.listings l
+		SET status = 'expired', updated_at = NOW()
+		FROM auth.users a
+		WHERE
+			a.id = l.user_id
+			AND l.status = ANY($1::listings.listing_status[])
+			AND l.expires_at <= NOW()
+		RETURNING l.id as listing_id, l.title, l.user_id, a.email as seller_email, l.expires_at
+	`
+
+	notices := []dto.ListingExpiryNotice{}
+
+	err := r.db.SelectContext(ctx, &notices, query, pq.Array(from))
+	if err != nil {
+		return nil, fmt.Errorf("expiring listings in database: %w", err)
+	}
+
+	return notices, nil
+}
+
+func (r *listingsRepo) MarkListingsExpiringBefore(
+	ctx context.Context,
+	before time.Time,
+) ([]dto.ListingExpiryNotice, error) {
+	query := `
+		UPDATE listings.listings l
+		SET expiry_notified_at = NOW()
+		FROM auth.users a
+		WHERE
+			a.id = l.user_id
+			AND l.status = 'open'
+			AND l.expiry_notified_at IS NULL
+			AND l.expires_at > NOW()
+			AND l.expires_at <= $1
+		RETURNING l.id as listing_id, l.title, l.user_id, a.email as seller_email, l.expires_at
+	`
+
+	notices := []dto.ListingExpiryNotice{}
+
+	err := r.db.SelectContext(ctx, &notices, query, before)
+	if err != nil {
+		return nil, fmt.Errorf("marking listings expiring soon in database: %w", err)
+	}
+
+	return notices, nil
+}
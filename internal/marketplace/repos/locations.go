@@ -0,0 +1,44 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+)
+
+// listingDistance calculates the great-circle distance in kilometers between the location of
+// the listing aliased as table and the point given by listingsFilter parameters $6 and $7,
+// using the haversine formula on a sphere with the mean Earth radius.
+func listingDistance(table string) string {
+	return `(6371 * 2 * ASIN(LEAST(1, SQRT(
+		POWER(SIN(RADIANS(` + table + `.latitude - $6::float8) / 2), 2)
+		+ COS(RADIANS($6::float8)) * COS(RADIANS(` + table + `.latitude))
+			* POWER(SIN(RADIANS(` + table + `.longitude - $7::float8) / 2), 2)
+	))))`
+}
+
+func (r *listingsRepo) SetListingLocation(
+	ctx context.Context,
+	req *dto.SetListingLocationRequest,
+) error {
+	query := `
+		UPDATE listings.listings
+		SET latitude = $3, longitude = $4, location_city = $5, updated_at = NOW()
+		WHERE id = $1 AND user_id = $2
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		req.ListingID,
+		req.UserID,
+		req.Latitude,
+		req.Longitude,
+		req.City,
+	)
+	if err != nil {
+		return fmt.Errorf("setting listing location in database: %w", err)
+	}
+
+	return nil
+}
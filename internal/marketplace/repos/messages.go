@@ -0,0 +1,336 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/pkg/generate"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// MessagesRepo defines methods for accessing and managing message threads data.
+type MessagesRepo interface {
+	GetOrCreateThread(ctx context.Context, listingID, buyerID, sellerID string) (string, error)
+	GetThreadByID(ctx context.Context, threadID, userID string) (*dto.Thread, error)
+	GetThreads(ctx context.Context, userID string) ([]dto.Thread, error)
+	CreateMessage(ctx context.Context, msg *dto.Message) (*dto.Message, error)
+	GetMessages(ctx context.Context, req *dto.GetThreadRequest) ([]dto.Message, error)
+	MarkThreadRead(ctx context.Context, threadID, userID string) error
+	CountUnreadMessages(ctx context.Context, userID string) (int, error)
+	BlockUser(ctx context.Context, req *dto.BlockUserRequest) error
+	UnblockUser(ctx context.Context, req *dto.BlockUserRequest) error
+	IsBlocked(ctx context.Context, userID, otherUserID string) (bool, error)
+	CreateAbuseReport(ctx context.Context, req *dto.ReportAbuseRequest) (*dto.AbuseReport, error)
+}
+
+type messagesRepo struct {
+	db *sqlx.DB
+}
+
+// NewMessagesRepo create new instance of messages repository.
+func NewMessagesRepo(db *sqlx.DB) *messagesRepo { //nolint:revive
+	return &messagesRepo{db: db}
+}
+
+// threadsSelect selects threads with unread messages count of the participant passed as $1.
+const threadsSelect = `
+	SELECT
+		t.id,
+		t.listing_id,
+		l.title AS listing_title,
+		b.id AS "buyer.id",
+		b.username AS "buyer.username",
+		b.email AS "buyer.email",
+		s.id AS "seller.id",
+		s.username AS "seller.username",
+		s.email AS "seller.email",
+		t.last_message_at,
+		t.created_at,
+		(
+			SELECT COUNT(*) FROM messaging.messages m
+			WHERE
+				m.thread_id = t.id
+				AND m.sender_id <> $1
+				AND $1 IN (t.buyer_id, t.seller_id)
+				AND m.created_at > COALESCE(
+					CASE WHEN t.buyer_id = $1 THEN t.buyer_last_read_at
+					ELSE t.seller_last_read_at END,
+					'-infinity'
+				)
+		) AS unread_count
+	FROM messaging.threads t
+		JOIN listings.listings l ON l.id = t.listing_id
+		JOIN auth.users b ON b.id = t.buyer_id
+		JOIN auth.users s ON s.id = t.seller_id
+`
+
+func (r *messagesRepo) GetOrCreateThread(
+	ctx context.Context,
+	listingID, buyerID, sellerID string,
+) (string, error) {
+	query := `
+		WITH inserted AS (
+			INSERT INTO messaging.threads (id, listing_id, buyer_id, seller_id)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (listing_id, buyer_id) DO NOTHING
+			RETURNING id
+		)
+		SELECT id FROM inserted
+		UNION ALL
+		SELECT id FROM messaging.threads WHERE listing_id = $2 AND buyer_id = $3
+		LIMIT 1
+	`
+
+	var threadID string
+
+	err := r.db.GetContext(
+		ctx, &threadID, query, generate.ID("thread"), listingID, buyerID, sellerID,
+	)
+	if err != nil {
+		return "", fmt.Errorf("creating thread in database: %w", err)
+	}
+
+	return threadID, nil
+}
+
+func (r *messagesRepo) GetThreadByID(
+	ctx context.Context,
+	threadID, userID string,
+) (*dto.Thread, error) {
+	query := threadsSelect + `
+		WHERE t.id = $2
+	`
+
+	var thread dto.Thread
+
+	err := r.db.GetContext(ctx, &thread, query, userID, threadID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching thread by id from database: %w", err)
+	}
+
+	return &thread, nil
+}
+
+func (r *messagesRepo) GetThreads(ctx context.Context, userID string) ([]dto.Thread, error) {
+	query := threadsSelect + `
+		WHERE $1 IN (t.buyer_id, t.seller_id) AND t.last_message_at IS NOT NULL
+		ORDER BY t.last_message_at DESC
+	`
+
+	threads := []dto.Thread{}
+
+	err := r.db.SelectContext(ctx, &threads, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching threads from database: %w", err)
+	}
+
+	return threads, nil
+}
+
+// CreateMessage stores a message and marks the thread as read for the sender.
+func (r *messagesRepo) CreateMessage(
+	ctx context.Context,
+	msg *dto.Message,
+) (*dto.Message, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("starting db transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	insertMessageQ := `
+		INSERT INTO messaging.messages (id, thread_id, sender_id, body)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, thread_id, sender_id, body, created_at
+	`
+
+	var created dto.Message
+
+	err = tx.GetContext(
+		ctx,
+		&created,
+		insertMessageQ,
+		generate.ID("msg"),
+		msg.ThreadID,
+		msg.SenderID,
+		msg.Body,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("inserting message into database: %w", err)
+	}
+
+	updateThreadQ := `
+		UPDATE messaging.threads
+		SET
+			last_message_at = $2,
+			buyer_last_read_at = CASE WHEN buyer_id = $3 THEN $2 ELSE buyer_last_read_at END,
+			seller_last_read_at = CASE WHEN seller_id = $3 THEN $2 ELSE seller_last_read_at END,
+			updated_at = NOW()
+		WHERE id = $1
+	`
+
+	_, err = tx.ExecContext(ctx, updateThreadQ, msg.ThreadID, created.CreatedAt, msg.SenderID)
+	if err != nil {
+		return nil, fmt.Errorf("updating thread last message: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("committing transaction for creating message: %w", err)
+	}
+
+	return &created, nil
+}
+
+func (r *messagesRepo) GetMessages(
+	ctx context.Context,
+	req *dto.GetThreadRequest,
+) ([]dto.Message, error) {
+	query := `
+		SELECT id, thread_id, sender_id, body, created_at
+		FROM messaging.messages
+		WHERE thread_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	messages := []dto.Message{}
+
+	err := r.db.SelectContext(ctx, &messages, query, req.ThreadID, req.Limit, req.Page*req.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("fetching messages from database: %w", err)
+	}
+
+	return messages, nil
+}
+
+func (r *messagesRepo) MarkThreadRead(ctx context.Context, threadID, userID string) error {
+	query := `
+		UPDATE messaging.threads
+		SET
+			buyer_last_read_at = CASE WHEN buyer_id = $2 THEN NOW() ELSE buyer_last_read_at END,
+			seller_last_read_at = CASE WHEN seller_id = $2 THEN NOW() ELSE seller_last_read_at END
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, threadID, userID)
+	if err != nil {
+		return fmt.Errorf("marking thread as read in database: %w", err)
+	}
+
+	return nil
+}
+
+func (r *messagesRepo) CountUnreadMessages(ctx context.Context, userID string) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM messaging.messages m
+			JOIN messaging.threads t ON t.id = m.thread_id
+		WHERE
+			$1 IN (t.buyer_id, t.seller_id)
+			AND m.sender_id <> $1
+			AND m.created_at > COALESCE(
+				CASE WHEN t.buyer_id = $1 THEN t.buyer_last_read_at
+				ELSE t.seller_last_read_at END,
+				'-infinity'
+			)
+	`
+
+	var count int
+
+	err := r.db.GetContext(ctx, &count, query, userID)
+	if err != nil {
+		return 0, fmt.Errorf("counting unread messages in database: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *messagesRepo) BlockUser(ctx context.Context, req *dto.BlockUserRequest) error {
+	query := `
+		INSERT INTO messaging.blocks (blocker_id, blocked_id)
+		VALUES ($1, $2)
+		ON CONFLICT (blocker_id, blocked_id) DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, query, req.UserID, req.BlockedUserID)
+	if err != nil {
+		return fmt.Errorf("inserting block into database: %w", err)
+	}
+
+	return nil
+}
+
+func (r *messagesRepo) UnblockUser(ctx context.Context, req *dto.BlockUserRequest) error {
+	query := `
+		DELETE FROM messaging.blocks WHERE blocker_id = $1 AND blocked_id = $2
+	`
+
+	_, err := r.db.ExecContext(ctx, query, req.UserID, req.BlockedUserID)
+	if err != nil {
+		return fmt.Errorf("deleting block from database: %w", err)
+	}
+
+	return nil
+}
+
+// IsBlocked reports whether either of the users blocked the other one.
+func (r *messagesRepo) IsBlocked(ctx context.Context, userID, otherUserID string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM messaging.blocks
+			WHERE
+				(blocker_id = $1 AND blocked_id = $2)
+				OR (blocker_id = $2 AND blocked_id = $1)
+		)
+	`
+
+	var blocked bool
+
+	err := r.db.GetContext(ctx, &blocked, query, userID, otherUserID)
+	if err != nil {
+		return false, fmt.Errorf("checking blocks in database: %w", err)
+	}
+
+	return blocked, nil
+}
+
+// CreateAbuseReport stores an abuse report, returning sql.ErrNoRows when reported message
+// doesn't belong to the thread.
+func (r *messagesRepo) CreateAbuseReport(
+	ctx context.Context,
+	req *dto.ReportAbuseRequest,
+) (*dto.AbuseReport, error) {
+	query := `
+		INSERT INTO messaging.abuse_reports (id, thread_id, message_id, reporter_id, reason)
+		SELECT $1, $2, $3, $4, $5
+		WHERE $3::text IS NULL OR EXISTS (
+			SELECT 1 FROM messaging.messages WHERE id = $3 AND thread_id = $2
+		)
+		RETURNING id, thread_id, message_id, reporter_id, reason, created_at
+	`
+
+	var report dto.AbuseReport
+
+	err := r.db.GetContext(
+		ctx,
+		&report,
+		query,
+		generate.ID("report"),
+		req.ThreadID,
+		req.MessageID,
+		req.ReporterID,
+		req.Reason,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("inserting abuse report into database: %w", err)
+	}
+
+	return &report, nil
+}
@@ -0,0 +1,312 @@
+package repos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/pkg/generate"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrListingStatusConflict is returned when a moderation action or a payment event doesn't
+// apply to the current status of the listing.
+var ErrListingStatusConflict = errors.New("listing status doesn't allow this action")
+
+// ModerationRepo defines methods for accessing and managing reports and moderation actions.
+type ModerationRepo interface {
+	CreateReport(ctx context.Context, req *dto.CreateReportRequest) (*dto.Report, error)
+	GetReportByID(ctx context.Context, reportID string) (*dto.Report, error)
+	GetReports(ctx context.Context, req *dto.GetReportsRequest) ([]dto.Report, error)
+	GetListingsByStatus(
+		ctx context.Context,
+		status dto.ListingStatus,
+		limit, page int,
+	) ([]dto.Listing, error)
+	ApplyAction(ctx context.Context, action *dto.ModerationAction) (*dto.ModerationAction, error)
+	GetActions(
+		ctx context.Context,
+		req *dto.GetModerationActionsRequest,
+	) ([]dto.ModerationAction, error)
+}
+
+type moderationRepo struct {
+	db *sqlx.DB
+}
+
+// NewModerationRepo create new instance of moderation repository.
+func NewModerationRepo(db *sqlx.DB) *moderationRepo { //nolint:revive
+	return &moderationRepo{db: db}
+}
+
+// CreateReport stores a report, returning sql.ErrNoRows when the reported user doesn't exist.
+func (r *moderationRepo) CreateReport(
+	ctx context.Context,
+	req *dto.CreateReportRequest,
+) (*dto.Report, error) {
+	query := `
+		INSERT INTO moderation.reports
+			(id, target_type, listing_id, user_id, reporter_id, reason, details)
+		SELECT
+			$1,
+			CAST($2 AS moderation.report_target),
+			$3,
+			u.id,
+			$5,
+			CAST($6 AS moderation.report_reason),
+			$7
+		FROM auth.users u
+		WHERE u.id = $4 AND u.deleted_at IS NULL
+		RETURNING *
+	`
+
+	var report dto.Report
+
+	err := r.db.GetContext(ctx, &report, query,
+		generate.ID("rep"),
+		req.TargetType,
+		req.ListingID,
+		req.UserID,
+		req.ReporterID,
+		req.Reason,
+		req.Details,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("inserting report into database: %w", err)
+	}
+
+	return &report, nil
+}
+
+func (r *moderationRepo) GetReportByID(ctx context.Context, reportID string) (*dto.Report, error) {
+	query := `SELECT * FROM moderation.reports WHERE id = $1`
+
+	var report dto.Report
+
+	err := r.db.GetContext(ctx, &report, query, reportID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching report from database: %w", err)
+	}
+
+	return &report, nil
+}
+
+func (r *moderationRepo) GetReports(
+	ctx context.Context,
+	req *dto.GetReportsRequest,
+) ([]dto.Report, error) {
+	query := `
+		SELECT * FROM moderation.reports
+		WHERE $1 = '' OR CAST(status AS TEXT) = $1
+		ORDER BY created_at
+		LIMIT $2 OFFSET $3
+	`
+
+	reports := []dto.Report{}
+
+	err := r.db.SelectContext(ctx, &reports, query,
+		string(req.Status), req.Limit, req.Page*req.Limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("fetching reports from database: %w", err)
+	}
+
+	return reports, nil
+}
+
+func (r *moderationRepo) GetListingsByStatus(
+	ctx context.Context,
+	status dto.ListingStatus,
+	limit, page int,
+) ([]dto.Listing, error) {
+	query := listingsSelect + `
+		WHERE l.status = $1 AND l.deleted_at IS NULL
+		GROUP BY l.id, a.id, sa.id, c.title
+		ORDER BY l.created_at
+		LIMIT $2 OFFSET $3
+	`
+
+	listings := []dto.Listing{}
+
+	err := r.db.SelectContext(ctx, &listings, query, status, limit, page*limit)
+	if err != nil {
+		return nil, fmt.Errorf("fetching listings by status from database: %w", err)
+	}
+
+	return listings, nil
+}
+
+// ApplyAction applies the effect of a moderation action, records it in the audit trail and
+// closes the report it was taken on, all in one transaction.
+func (r *moderationRepo) ApplyAction(
+	ctx context.Context,
+	action *dto.ModerationAction,
+) (*dto.ModerationAction, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("starting db transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	err = applyModerationEffect(ctx, tx, action)
+	if err != nil {
+		return nil, err
+	}
+
+	auditQ := `
+		WITH inserted AS (
+			INSERT INTO moderation.actions
+				(id, admin_id, action, report_id, listing_id, user_id, note)
+			VALUES
+				($1, $2, $3, $4, $5, $6, $7)
+			RETURNING *
+		)
+		SELECT i.*, u.email AS user_email
+		FROM inserted i
+			LEFT JOIN auth.users u ON u.id = i.user_id
+	`
+
+	var recorded dto.ModerationAction
+
+	err = tx.GetContext(ctx, &recorded, auditQ,
+		generate.ID("mod"),
+		action.AdminID,
+		action.Action,
+		action.ReportID,
+		action.ListingID,
+		action.UserID,
+		action.Note,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("inserting moderation action into database: %w", err)
+	}
+
+	if action.ReportID != nil {
+		status := dto.ReportStatusResolved
+		if action.Action == dto.ModerationActionDismissReport {
+			status = dto.ReportStatusDismissed
+		}
+
+		reportQ := `
+			UPDATE moderation.reports
+			SET status = $2, resolved_by = $3, resolved_at = NOW()
+			WHERE id = $1 AND status = 'open'
+		`
+
+		_, err = tx.ExecContext(ctx, reportQ, *action.ReportID, status, action.AdminID)
+		if err != nil {
+			return nil, fmt.Errorf("resolving report in database: %w", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("committing transaction for moderation action: %w", err)
+	}
+
+	return &recorded, nil
+}
+
+// applyModerationEffect changes the listing status or the seller suspension according to
+// the action. Warnings and dismissals only end up in the audit trail.
+func applyModerationEffect(ctx context.Context, tx *sqlx.Tx, action *dto.ModerationAction) error {
+	var listingQ string
+
+	switch action.Action {
+	case dto.ModerationActionApproveListing:
+		// the listing keeps its full duration, counted from the approval
+		listingQ = `
+			UPDATE listings.listings
+			SET
+				status = 'open',
+				expires_at = NOW() + (expires_at - created_at),
+				updated_at = NOW()
+			WHERE id = $1 AND status = 'pending_review'
+		`
+	case dto.ModerationActionHideListing:
+		listingQ = `
+			UPDATE listings.listings SET status = 'hidden', updated_at = NOW()
+			WHERE id = $1 AND status IN ('open', 'pending_review')
+		`
+	case dto.ModerationActionRestoreListing:
+		listingQ = `
+			UPDATE listings.listings SET status = 'open', updated_at = NOW()
+			WHERE id = $1 AND status = 'hidden'
+		`
+	case dto.ModerationActionRemoveListing:
+		listingQ = `
+			UPDATE listings.listings SET status = 'removed', updated_at = NOW()
+			WHERE id = $1 AND status NOT IN ('sold', 'refunded', 'removed')
+		`
+	case dto.ModerationActionSuspendUser:
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO moderation.suspensions (user_id, suspended_by) VALUES ($1, $2)
+			ON CONFLICT (user_id) DO NOTHING
+		`, action.UserID, action.AdminID)
+		if err != nil {
+			return fmt.Errorf("suspending user in database: %w", err)
+		}
+
+		return nil
+	case dto.ModerationActionUnsuspendUser:
+		_, err := tx.ExecContext(ctx,
+			`DELETE FROM moderation.suspensions WHERE user_id = $1`, action.UserID,
+		)
+		if err != nil {
+			return fmt.Errorf("lifting user suspension in database: %w", err)
+		}
+
+		return nil
+	case dto.ModerationActionWarnUser, dto.ModerationActionDismissReport:
+		return nil
+	}
+
+	res, err := tx.ExecContext(ctx, listingQ, action.ListingID)
+	if err != nil {
+		return fmt.Errorf("updating moderated listing in database: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("reading rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return ErrListingStatusConflict
+	}
+
+	return nil
+}
+
+func (r *moderationRepo) GetActions(
+	ctx context.Context,
+	req *dto.GetModerationActionsRequest,
+) ([]dto.ModerationAction, error) {
+	query := `
+		SELECT ma.*, u.email AS user_email
+		FROM moderation.actions ma
+			LEFT JOIN auth.users u ON u.id = ma.user_id
+		WHERE
+			($1::text IS NULL OR ma.listing_id = $1)
+			AND ($2::text IS NULL OR ma.user_id = $2)
+		ORDER BY ma.created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	actions := []dto.ModerationAction{}
+
+	err := r.db.SelectContext(ctx, &actions, query,
+		req.ListingID, req.UserID, req.Limit, req.Page*req.Limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("fetching moderation actions from database: %w", err)
+	}
+
+	return actions, nil
+}
@@ -2,6 +2,8 @@ package repos
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"golang-connect-marketplace/internal/marketplace/dto"
 
@@ -16,8 +18,15 @@ type PaymentsRepo interface {
 		userID, sellerID string,
 		provider dto.Provider,
 	) (*dto.SellerAccount, error)
-	SavePayment(ctx context.Context, payment *dto.Payment) (*dto.Payment, error)
+	SavePayment(ctx context.Context, payment *dto.Payment) (*dto.Payment, bool, error)
+	GetPaymentByProviderID(
+		ctx context.Context,
+		provider dto.Provider,
+		providerPaymentID string,
+	) (*dto.Payment, error)
 	RefundPayment(ctx context.Context, payment *dto.Payment) (*dto.Payment, error)
+	GetOrder(ctx context.Context, orderID string) (*dto.Order, error)
+	GetSales(ctx context.Context, sellerID string) ([]dto.Order, error)
 }
 
 type paymentsRepo struct {
@@ -76,13 +85,17 @@ func (r *paymentsRepo) UpdateSellerID(
 	return &resp, nil
 }
 
+// SavePayment saves a payment and marks the paid listing as sold, in one transaction.
+// Payments are saved once per provider payment, repeated events return the saved payment
+// with created set to false. Payments flagged with NeedsReview, or for listings which are
+// not open anymore, leave the listing unchanged and are saved flagged.
 func (r *paymentsRepo) SavePayment(
 	ctx context.Context,
 	payment *dto.Payment,
-) (*dto.Payment, error) {
+) (*dto.Payment, bool, error) {
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("starting db transaction: %w", err)
+		return nil, false, fmt.Errorf("starting db transaction: %w", err)
 	}
 
 	defer func() {
@@ -91,37 +104,98 @@ func (r *paymentsRepo) SavePayment(
 		}
 	}()
 
-	insertPaymentQ := `
-		INSERT INTO payments.payments 
-			(id, listing_id, buyer_id, provider_payment_id, provider, amount_in_cents, fee_amount_in_cents, currency) 
-		VALUES 
-			(:id, :listing_id, :buyer_id, :provider_payment_id, :provider, :amount_in_cents, :fee_amount_in_cents, :currency) 
+	insertPaymentQ, args, err := tx.BindNamed(`
+		INSERT INTO payments.payments
+			(
+				id, listing_id, buyer_id, provider_payment_id, provider, amount_in_cents,
+				fee_amount_in_cents, currency, shipping_option_id, shipping_method,
+				shipping_amount_in_cents, shipping_address, needs_review
+			)
+		VALUES
+			(
+				:id, :listing_id, :buyer_id, :provider_payment_id, :provider, :amount_in_cents,
+				:fee_amount_in_cents, :currency, :shipping_option_id, :shipping_method,
+				:shipping_amount_in_cents, :shipping_address, :needs_review
+			)
+		ON CONFLICT (provider, provider_payment_id) DO NOTHING
 		RETURNING *
-	`
-
-	_, err = tx.NamedExecContext(ctx, insertPaymentQ, payment)
+	`, payment)
 	if err != nil {
-		return nil, fmt.Errorf("inserting payment into database: %w", err)
+		return nil, false, fmt.Errorf("binding payment insert: %w", err)
 	}
 
-	updateListingQ := `
-		UPDATE listings.listings SET status = 'sold' WHERE id = $1 
-	`
+	var saved dto.Payment
+
+	err = tx.GetContext(ctx, &saved, insertPaymentQ, args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = tx.GetContext(ctx, &saved, `
+			SELECT * FROM payments.payments WHERE provider = $1 AND provider_payment_id = $2
+		`, payment.Provider, payment.ProviderPaymentID)
+		if err != nil {
+			return nil, false, fmt.Errorf("fetching saved payment from database: %w", err)
+		}
+
+		err = tx.Commit()
+		if err != nil {
+			return nil, false, fmt.Errorf("committing transaction for saved payment: %w", err)
+		}
+
+		return &saved, false, nil
+	}
 
-	_, err = tx.ExecContext(ctx, updateListingQ, payment.ListingID)
 	if err != nil {
-		return nil, fmt.Errorf("setting listing status to sold: %w", err)
+		return nil, false, fmt.Errorf("inserting payment into database: %w", err)
+	}
+
+	if !saved.NeedsReview {
+		updateListingQ := `
+			UPDATE listings.listings SET status = 'sold', updated_at = NOW()
+			WHERE id = $1 AND status = 'open'
+		`
+
+		err = updateListingStatus(ctx, tx, updateListingQ, payment.ListingID)
+		if errors.Is(err, ErrListingStatusConflict) {
+			err = tx.GetContext(ctx, &saved, `
+				UPDATE payments.payments SET needs_review = TRUE, updated_at = NOW()
+				WHERE id = $1
+				RETURNING *
+			`, saved.ID)
+		}
+
+		if err != nil {
+			return nil, false, fmt.Errorf("setting listing status to sold: %w", err)
+		}
 	}
 
 	err = tx.Commit()
 	if err != nil {
-		return nil, fmt.Errorf(
+		return nil, false, fmt.Errorf(
 			"committing transcation for saving payment + uptading listing: %w",
 			err,
 		)
 	}
 
-	return payment, nil
+	return &saved, true, nil
+}
+
+// GetPaymentByProviderID fetches the payment saved for a payment of the provider.
+func (r *paymentsRepo) GetPaymentByProviderID(
+	ctx context.Context,
+	provider dto.Provider,
+	providerPaymentID string,
+) (*dto.Payment, error) {
+	query := `
+		SELECT * FROM payments.payments WHERE provider = $1 AND provider_payment_id = $2
+	`
+
+	var payment dto.Payment
+
+	err := r.db.GetContext(ctx, &payment, query, provider, providerPaymentID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching payment from database: %w", err)
+	}
+
+	return &payment, nil
 }
 
 func (r *paymentsRepo) RefundPayment(
@@ -151,13 +225,17 @@ func (r *paymentsRepo) RefundPayment(
 		return nil, fmt.Errorf("setting refunded_at for a payment in database: %w", err)
 	}
 
-	updateListingQ := `
-		UPDATE listings.listings SET status = 'refunded' WHERE id = $1 
-	`
+	// flagged payments never sold the listing, which may have been sold to someone else
+	if !updatedPayment.NeedsReview {
+		updateListingQ := `
+			UPDATE listings.listings SET status = 'refunded', updated_at = NOW()
+			WHERE id = $1 AND status = 'sold'
+		`
 
-	_, err = tx.ExecContext(ctx, updateListingQ, payment.ListingID)
-	if err != nil {
-		return nil, fmt.Errorf("setting listing status to refunded: %w", err)
+		err = updateListingStatus(ctx, tx, updateListingQ, payment.ListingID)
+		if err != nil {
+			return nil, fmt.Errorf("setting listing status to refunded: %w", err)
+		}
 	}
 
 	err = tx.Commit()
@@ -168,5 +246,61 @@ func (r *paymentsRepo) RefundPayment(
 		)
 	}
 
-	return payment, nil
+	return &updatedPayment, nil
+}
+
+// updateListingStatus runs the status update of a paid listing, which only applies
+// to listings in the status the payment event moves them from.
+func updateListingStatus(ctx context.Context, tx *sqlx.Tx, query, listingID string) error {
+	res, err := tx.ExecContext(ctx, query, listingID)
+	if err != nil {
+		return fmt.Errorf("updating listing status in database: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("reading rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return ErrListingStatusConflict
+	}
+
+	return nil
+}
+
+// ordersSelect selects payments along with the title and seller of the paid listing.
+const ordersSelect = `
+	SELECT p.*, l.title AS listing_title, l.user_id AS seller_id
+	FROM payments.payments p
+		JOIN listings.listings l ON l.id = p.listing_id
+`
+
+func (r *paymentsRepo) GetOrder(ctx context.Context, orderID string) (*dto.Order, error) {
+	query := ordersSelect + `WHERE p.id = $1`
+
+	var order dto.Order
+
+	err := r.db.GetContext(ctx, &order, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching order from database: %w", err)
+	}
+
+	return &order, nil
+}
+
+func (r *paymentsRepo) GetSales(ctx context.Context, sellerID string) ([]dto.Order, error) {
+	query := ordersSelect + `
+		WHERE l.user_id = $1
+		ORDER BY p.created_at DESC
+	`
+
+	orders := []dto.Order{}
+
+	err := r.db.SelectContext(ctx, &orders, query, sellerID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching sales from database: %w", err)
+	}
+
+	return orders, nil
 }
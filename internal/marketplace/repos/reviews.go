@@ -0,0 +1,143 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/pkg/generate"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ReviewsRepo defines methods for accessing and managing post-purchase reviews data.
+type ReviewsRepo interface {
+	GetReviewablePayment(ctx context.Context, paymentID string) (*dto.ReviewablePayment, error)
+	CreateReview(ctx context.Context, review *dto.Review) (*dto.Review, error)
+	GetReviews(ctx context.Context, req *dto.GetReviewsRequest) ([]dto.Review, error)
+	GetReputation(ctx context.Context, userID string) (*dto.Reputation, error)
+}
+
+type reviewsRepo struct {
+	db *sqlx.DB
+}
+
+// NewReviewsRepo create new instance of reviews repository.
+func NewReviewsRepo(db *sqlx.DB) *reviewsRepo { //nolint:revive
+	return &reviewsRepo{db: db}
+}
+
+func (r *reviewsRepo) GetReviewablePayment(
+	ctx context.Context,
+	paymentID string,
+) (*dto.ReviewablePayment, error) {
+	query := `
+		SELECT p.id, p.buyer_id, l.user_id AS seller_id, p.created_at, p.refunded_at
+		FROM payments.payments p
+			JOIN listings.listings l ON l.id = p.listing_id
+		WHERE p.id = $1
+	`
+
+	var payment dto.ReviewablePayment
+
+	err := r.db.GetContext(ctx, &payment, query, paymentID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching reviewable payment from database: %w", err)
+	}
+
+	return &payment, nil
+}
+
+// CreateReview stores a review, returning sql.ErrNoRows when the reviewer already
+// reviewed this payment.
+func (r *reviewsRepo) CreateReview(ctx context.Context, review *dto.Review) (*dto.Review, error) {
+	query := `
+		WITH inserted AS (
+			INSERT INTO payments.reviews
+				(id, payment_id, reviewer_id, reviewee_id, reviewer_role, rating, body)
+			VALUES
+				($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (payment_id, reviewer_id) DO NOTHING
+			RETURNING *
+		)
+		SELECT i.*, a.username AS reviewer_username
+		FROM inserted i
+			JOIN auth.users a ON a.id = i.reviewer_id
+	`
+
+	var created dto.Review
+
+	err := r.db.GetContext(ctx, &created, query,
+		generate.ID("rev"),
+		review.PaymentID,
+		review.ReviewerID,
+		review.RevieweeID,
+		review.ReviewerRole,
+		review.Rating,
+		review.Body,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("inserting review into database: %w", err)
+	}
+
+	return &created, nil
+}
+
+func (r *reviewsRepo) GetReviews(
+	ctx context.Context,
+	req *dto.GetReviewsRequest,
+) ([]dto.Review, error) {
+	query := `
+		SELECT rv.*, a.username AS reviewer_username
+		FROM payments.reviews rv
+			JOIN auth.users a ON a.id = rv.reviewer_id
+		WHERE
+			rv.reviewee_id = $1
+			AND ($2 = '' OR CAST(rv.reviewer_role AS TEXT) = $2)
+		ORDER BY rv.created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	reviews := []dto.Review{}
+
+	err := r.db.SelectContext(ctx, &reviews, query,
+		req.UserID, string(req.Role), req.Limit, req.Page*req.Limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("fetching reviews from database: %w", err)
+	}
+
+	return reviews, nil
+}
+
+// GetReputation aggregates reviews a user received from buyers and their completed sales.
+func (r *reviewsRepo) GetReputation(ctx context.Context, userID string) (*dto.Reputation, error) {
+	query := `SELECT ` + reputationColumns("$1", "")
+
+	var reputation dto.Reputation
+
+	err := r.db.GetContext(ctx, &reputation, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching reputation from database: %w", err)
+	}
+
+	return &reputation, nil
+}
+
+// reputationColumns selects the reputation of the user identified by userID, which is
+// a column or a query parameter. Column names are prefixed with prefix.
+func reputationColumns(userID, prefix string) string {
+	return fmt.Sprintf(`
+		(
+			SELECT ROUND(AVG(rv.rating), 2) FROM payments.reviews rv
+			WHERE rv.reviewee_id = %[1]s AND rv.reviewer_role = 'buyer'
+		) AS "%[2]srating",
+		(
+			SELECT COUNT(*) FROM payments.reviews rv
+			WHERE rv.reviewee_id = %[1]s AND rv.reviewer_role = 'buyer'
+		) AS "%[2]sreview_count",
+		(
+			SELECT COUNT(*) FROM payments.payments p
+				JOIN listings.listings sl ON sl.id = p.listing_id
+			WHERE sl.user_id = %[1]s AND p.refunded_at IS NULL
+		) AS "%[2]ssales_count"`, userID, prefix)
+}
@@ -0,0 +1,138 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/pkg/generate"
+	"reflect"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// revisionColumns selects listing fields tracked by revisions.
+const revisionColumns = `
+	category_id, title, description, price_in_cents, currency, attributes, status
+`
+
+// revisionFields holds listing fields tracked by revisions.
+type revisionFields struct {
+	CategoryID   string                `db:"category_id"`
+	Title        string                `db:"title"`
+	Description  string                `db:"description"`
+	PriceInCents int                   `db:"price_in_cents"`
+	Currency     string                `db:"currency"`
+	Attributes   dto.ListingAttributes `db:"attributes"`
+	Status       dto.ListingStatus     `db:"status"`
+}
+
+// changes returns fields which differ between f and the updated fields.
+func (f *revisionFields) changes(updated *revisionFields) dto.RevisionChanges {
+	changes := dto.RevisionChanges{}
+
+	add := func(field string, before, after any) {
+		if !reflect.DeepEqual(before, after) {
+			changes[field] = dto.FieldChange{Old: before, New: after}
+		}
+	}
+
+	add("category_id", f.CategoryID, updated.CategoryID)
+	add("title", f.Title, updated.Title)
+	add("description", f.Description, updated.Description)
+	add("price_in_cents", f.PriceInCents, updated.PriceInCents)
+	add("currency", f.Currency, updated.Currency)
+	add("attributes", f.Attributes, updated.Attributes)
+	add("status", f.Status, updated.Status)
+
+	return changes
+}
+
+func insertRevision(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	req *dto.UpdateListingRequest,
+	changes dto.RevisionChanges,
+) error {
+	query := `
+		INSERT INTO listings.listing_revisions (id, listing_id, actor_id, actor_role, changes)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := tx.ExecContext(ctx, query,
+		generate.ID("lrev"),
+		req.ID,
+		req.ActorID,
+		req.ActorRole,
+		changes,
+	)
+	if err != nil {
+		return fmt.Errorf("inserting listing revision into database: %w", err)
+	}
+
+	return nil
+}
+
+func (r *listingsRepo) GetListingRevisions(
+	ctx context.Context,
+	req *dto.GetListingRevisionsRequest,
+) ([]dto.ListingRevision, error) {
+	query := `
+		SELECT id, listing_id, actor_id, actor_role, changes, created_at
+		FROM listings.listing_revisions
+		WHERE listing_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	revisions := []dto.ListingRevision{}
+
+	err := r.db.SelectContext(ctx, &revisions, query,
+		req.ListingID, req.Limit, req.Page*req.Limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("fetching listing revisions from database: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// GetPriceHistory returns price changes of a listing, oldest first. The currency of each
+// change is the one the listing had right after it.
+func (r *listingsRepo) GetPriceHistory(
+	ctx context.Context,
+	listingID string,
+) ([]dto.PriceChange, error) {
+	query := `
+		SELECT
+			(rev.changes -> 'price_in_cents' ->> 'old')::int AS old_price_in_cents,
+			(rev.changes -> 'price_in_cents' ->> 'new')::int AS new_price_in_cents,
+			COALESCE(
+				rev.changes -> 'currency' ->> 'new',
+				(
+					SELECT later.changes -> 'currency' ->> 'old'
+					FROM listings.listing_revisions later
+					WHERE
+						later.listing_id = rev.listing_id
+						AND later.created_at > rev.created_at
+						AND later.changes -> 'currency' IS NOT NULL
+					ORDER BY later.created_at
+					LIMIT 1
+				),
+				l.currency
+			) AS currency,
+			rev.created_at AS changed_at
+		FROM listings.listing_revisions rev
+			JOIN listings.listings l ON l.id = rev.listing_id
+		WHERE rev.listing_id = $1 AND rev.changes -> 'price_in_cents' IS NOT NULL
+		ORDER BY rev.created_at
+	`
+
+	history := []dto.PriceChange{}
+
+	err := r.db.SelectContext(ctx, &history, query, listingID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching price history from database: %w", err)
+	}
+
+	return history, nil
+}
@@ -0,0 +1,285 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/pkg/generate"
+
+	"github.com/lib/pq"
+)
+
+func (r *listingsRepo) CreateSavedSearch(
+	ctx context.Context,
+	req *dto.CreateSavedSearchRequest,
+) (*dto.SavedSearch, error) {
+	query := `
+		INSERT INTO listings.saved_searches (id, user_id, name, filters, frequency)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, user_id, name, filters, frequency, created_at, updated_at
+	`
+
+	var created dto.SavedSearch
+
+	err := r.db.GetContext(
+		ctx,
+		&created,
+		query,
+		generate.ID("search"),
+		req.UserID,
+		req.Name,
+		req.Filters,
+		req.Frequency,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("inserting saved search into database: %w", err)
+	}
+
+	return &created, nil
+}
+
+func (r *listingsRepo) CountSavedSearches(ctx context.Context, userID string) (int, error) {
+	query := `SELECT COUNT(*) FROM listings.saved_searches WHERE user_id = $1`
+
+	var count int
+
+	err := r.db.GetContext(ctx, &count, query, userID)
+	if err != nil {
+		return 0, fmt.Errorf("counting saved searches in database: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *listingsRepo) GetSavedSearches(
+	ctx context.Context,
+	userID string,
+) ([]dto.SavedSearch, error) {
+	query := `
+		SELECT id, user_id, name, filters, frequency, created_at, updated_at
+		FROM listings.saved_searches
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+
+	searches := []dto.SavedSearch{}
+
+	err := r.db.SelectContext(ctx, &searches, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching saved searches from database: %w", err)
+	}
+
+	return searches, nil
+}
+
+func (r *listingsRepo) GetAllSavedSearches(ctx context.Context) ([]dto.SavedSearch, error) {
+	query := `
+		SELECT
+			s.id, s.user_id, a.email, s.name, s.filters, s.frequency, s.created_at, s.updated_at
+		FROM listings.saved_searches s
+			JOIN auth.users a ON a.id = s.user_id
+	`
+
+	searches := []dto.SavedSearch{}
+
+	err := r.db.SelectContext(ctx, &searches, query)
+	if err != nil {
+		return nil, fmt.Errorf("fetching all saved searches from database: %w", err)
+	}
+
+	return searches, nil
+}
+
+func (r *listingsRepo) UpdateSavedSearch(
+	ctx context.Context,
+	req *dto.UpdateSavedSearchRequest,
+) (*dto.SavedSearch, error) {
+	query := `
+		UPDATE listings.saved_searches
+		SET
+			name = COALESCE($3, name),
+			filters = COALESCE($4, filters),
+			frequency = COALESCE($5, frequency),
+			updated_at = NOW()
+		WHERE id = $1 AND user_id = $2
+		RETURNING id, user_id, name, filters, frequency, created_at, updated_at
+	`
+
+	var updated dto.SavedSearch
+
+	err := r.db.GetContext(
+		ctx,
+		&updated,
+		query,
+		req.ID,
+		req.UserID,
+		req.Name,
+		req.Filters,
+		req.Frequency,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("updating saved search in database: %w", err)
+	}
+
+	return &updated, nil
+}
+
+func (r *listingsRepo) DeleteSavedSearch(ctx context.Context, searchID, userID string) error {
+	query := `DELETE FROM listings.saved_searches WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, searchID, userID)
+	if err != nil {
+		return fmt.Errorf("deleting saved search from database: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("reading affected rows of saved search delete: %w", err)
+	}
+
+	if affected == 0 {
+		return ErrNoRowsReturned
+	}
+
+	return nil
+}
+
+func (r *listingsRepo) GetUnmatchedListingIDs(ctx context.Context) ([]string, error) {
+	query := `
+		SELECT id FROM listings.listings
+		WHERE search_matched_at IS NULL AND status = 'open'
+		ORDER BY created_at
+	`
+
+	ids := []string{}
+
+	err := r.db.SelectContext(ctx, &ids, query)
+	if err != nil {
+		return nil, fmt.Errorf("fetching unmatched listings from database: %w", err)
+	}
+
+	return ids, nil
+}
+
+func (r *listingsRepo) MarkListingsSearchMatched(ctx context.Context, listingIDs []string) error {
+	query := `
+		UPDATE listings.listings SET search_matched_at = NOW() WHERE id = ANY($1)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, pq.Array(listingIDs))
+	if err != nil {
+		return fmt.Errorf("marking listings as matched in database: %w", err)
+	}
+
+	return nil
+}
+
+// MatchSavedSearch stores which of the listings match the saved search and returns the new
+// matches. Listings of the user who saved the search and already stored matches are skipped.
+func (r *listingsRepo) MatchSavedSearch(
+	ctx context.Context,
+	search *dto.SavedSearch,
+	listingIDs []string,
+) ([]dto.SavedSearchMatch, error) {
+	query := `
+		WITH matched AS (
+			INSERT INTO listings.saved_search_matches (saved_search_id, listing_id)
+			SELECT $9, l.id
+			FROM listings.listings l
+			WHERE
+				l.id = ANY($10)
+				AND l.user_id <> $11
+				AND ` + listingsFilter + `
+			ON CONFLICT (saved_search_id, listing_id) DO NOTHING
+			RETURNING saved_search_id, listing_id
+		)
+		SELECT
+			m.saved_search_id,
+			$12 AS search_name,
+			$11 AS user_id,
+			$13 AS email,
+			m.listing_id,
+			l.title
+		FROM matched m
+			JOIN listings.listings l ON l.id = m.listing_id
+	`
+
+	args, err := listingsFilterArgs(search.Filters.ListingsRequest())
+	if err != nil {
+		return nil, err
+	}
+
+	args = append(
+		args,
+		search.ID,
+		pq.Array(listingIDs),
+		search.UserID,
+		search.Name,
+		search.Email,
+	)
+
+	matches := []dto.SavedSearchMatch{}
+
+	err = r.db.SelectContext(ctx, &matches, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("matching saved search in database: %w", err)
+	}
+
+	return matches, nil
+}
+
+func (r *listingsRepo) GetPendingDigestMatches(
+	ctx context.Context,
+) ([]dto.SavedSearchMatch, error) {
+	query := `
+		SELECT
+			m.saved_search_id,
+			s.name AS search_name,
+			s.user_id,
+			a.email,
+			m.listing_id,
+			l.title
+		FROM listings.saved_search_matches m
+			JOIN listings.saved_searches s ON s.id = m.saved_search_id
+			JOIN auth.users a ON a.id = s.user_id
+			JOIN listings.listings l ON l.id = m.listing_id
+		WHERE m.notified_at IS NULL AND s.frequency = 'daily'
+		ORDER BY s.user_id, m.saved_search_id, m.created_at
+	`
+
+	matches := []dto.SavedSearchMatch{}
+
+	err := r.db.SelectContext(ctx, &matches, query)
+	if err != nil {
+		return nil, fmt.Errorf("fetching pending digest matches from database: %w", err)
+	}
+
+	return matches, nil
+}
+
+func (r *listingsRepo) MarkSavedSearchMatchesNotified(
+	ctx context.Context,
+	matches []dto.SavedSearchMatch,
+) error {
+	searchIDs := make([]string, 0, len(matches))
+	listingIDs := make([]string, 0, len(matches))
+
+	for _, m := range matches {
+		searchIDs = append(searchIDs, m.SavedSearchID)
+		listingIDs = append(listingIDs, m.ListingID)
+	}
+
+	query := `
+		UPDATE listings.saved_search_matches m
+		SET notified_at = NOW()
+		FROM unnest($1::text[], $2::text[]) AS n(saved_search_id, listing_id)
+		WHERE m.saved_search_id = n.saved_search_id AND m.listing_id = n.listing_id
+	`
+
+	_, err := r.db.ExecContext(ctx, query, pq.Array(searchIDs), pq.Array(listingIDs))
+	if err != nil {
+		return fmt.Errorf("marking saved search matches as notified in database: %w", err)
+	}
+
+	return nil
+}
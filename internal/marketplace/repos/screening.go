@@ -0,0 +1,111 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/pkg/generate"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// GetCategoryPriceStats returns the median price of open and sold listings in the category
+// with the same currency, not counting the excluded listing.
+func (r *listingsRepo) GetCategoryPriceStats(
+	ctx context.Context,
+	categoryID, currency, excludeListingID string,
+) (*dto.CategoryPriceStats, error) {
+	query := `
+		SELECT
+			COALESCE(
+				percentile_cont(0.5) WITHIN GROUP (ORDER BY price_in_cents), 0
+			) AS median_price_in_cents,
+			COUNT(*) AS sample_size
+		FROM listings.listings
+		WHERE
+			category_id = $1
+			AND currency = $2
+			AND id <> $3
+			AND status IN ('open', 'sold')
+			AND deleted_at IS NULL
+	`
+
+	var stats dto.CategoryPriceStats
+
+	err := r.db.GetContext(ctx, &stats, query, categoryID, currency, excludeListingID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching category price stats from database: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// GetSellerListingTexts returns title and description of the most recent open or pending
+// listings of the seller, not counting the excluded listing.
+func (r *listingsRepo) GetSellerListingTexts(
+	ctx context.Context,
+	userID, excludeListingID string,
+	limit int,
+) ([]dto.ListingText, error) {
+	query := `
+		SELECT id, title, description
+		FROM listings.listings
+		WHERE
+			user_id = $1
+			AND id <> $2
+			AND status IN ('open', 'pending_review')
+			AND deleted_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT $3
+	`
+
+	texts := []dto.ListingText{}
+
+	err := r.db.SelectContext(ctx, &texts, query, userID, excludeListingID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("fetching seller listings from database: %w", err)
+	}
+
+	return texts, nil
+}
+
+// insertScreeningReports files findings of automated screening as reports in the moderation
+// queue, in the transaction saving the listing. Findings already reported and still open are
+// skipped.
+func insertScreeningReports(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	listingID string,
+	findings []dto.ScreeningFinding,
+) error {
+	query := `
+		INSERT INTO moderation.reports
+			(id, target_type, listing_id, user_id, reporter_id, reason, details)
+		SELECT $1, 'listing', l.id, l.user_id, NULL, CAST($3 AS moderation.report_reason), $4
+		FROM listings.listings l
+		WHERE
+			l.id = $2
+			AND NOT EXISTS (
+				SELECT 1 FROM moderation.reports
+				WHERE
+					listing_id = $2
+					AND reporter_id IS NULL
+					AND status = 'open'
+					AND details = $4
+			)
+	`
+
+	for _, f := range findings {
+		_, err := tx.ExecContext(ctx, query,
+			generate.ID("rep"),
+			listingID,
+			f.Reason,
+			f.Screener+": "+f.Details,
+		)
+		if err != nil {
+			return fmt.Errorf("inserting screening report into database: %w", err)
+		}
+	}
+
+	return nil
+}
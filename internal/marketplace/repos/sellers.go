@@ -0,0 +1,131 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+)
+
+// sellerProfileSelect selects public profiles of users, including users who never
+// edited their profile.
+var sellerProfileSelect = `
+	SELECT
+		a.id,
+		a.username,
+		a.created_at AS member_since,
+		COALESCE(p.bio, '') AS bio,
+		p.avatar_path,
+		COALESCE(p.avatar_variants, '{}') AS avatar_variants,
+		` + reputationColumns("a.id", "reputation.") + `
+	FROM auth.users a
+		LEFT JOIN listings.seller_profiles p ON p.user_id = a.id
+	WHERE a.deleted_at IS NULL
+`
+
+// GetSellerProfile fetches the profile of the user with username, ignoring letter case.
+func (r *listingsRepo) GetSellerProfile(
+	ctx context.Context,
+	username string,
+) (*dto.SellerProfile, error) {
+	query := sellerProfileSelect + `
+		AND LOWER(a.username) = LOWER($1)
+	`
+
+	var profile dto.SellerProfile
+
+	err := r.db.GetContext(ctx, &profile, query, username)
+	if err != nil {
+		return nil, fmt.Errorf("fetching seller profile from database: %w", err)
+	}
+
+	return &profile, nil
+}
+
+func (r *listingsRepo) GetSellerProfileByID(
+	ctx context.Context,
+	userID string,
+) (*dto.SellerProfile, error) {
+	query := sellerProfileSelect + `
+		AND a.id = $1
+	`
+
+	var profile dto.SellerProfile
+
+	err := r.db.GetContext(ctx, &profile, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching seller profile by id from database: %w", err)
+	}
+
+	return &profile, nil
+}
+
+func (r *listingsRepo) UpdateSellerProfile(
+	ctx context.Context,
+	req *dto.UpdateSellerProfileRequest,
+) error {
+	query := `
+		INSERT INTO listings.seller_profiles (user_id, bio, avatar_path, avatar_variants)
+		VALUES ($1, COALESCE($2::text, ''), $3::text, $4)
+		ON CONFLICT (user_id) DO UPDATE SET
+			bio = COALESCE($2::text, seller_profiles.bio),
+			avatar_path = COALESCE($3::text, seller_profiles.avatar_path),
+			avatar_variants = CASE
+				WHEN $3::text IS NULL THEN seller_profiles.avatar_variants
+				ELSE EXCLUDED.avatar_variants
+			END,
+			updated_at = NOW()
+	`
+
+	_, err := r.db.ExecContext(ctx, query, req.UserID, req.Bio, req.AvatarPath, req.AvatarVariants)
+	if err != nil {
+		return fmt.Errorf("updating seller profile in database: %w", err)
+	}
+
+	return nil
+}
+
+func (r *listingsRepo) GetSellerStanding(
+	ctx context.Context,
+	userID string,
+) (*dto.SellerStanding, error) {
+	query := `
+		SELECT
+			EXISTS (SELECT 1 FROM moderation.suspensions WHERE user_id = $1) AS suspended,
+			(
+				SELECT COUNT(*) FROM payments.payments p
+					JOIN listings.listings l ON l.id = p.listing_id
+				WHERE l.user_id = $1 AND p.refunded_at IS NULL
+			) AS sales_count
+	`
+
+	var standing dto.SellerStanding
+
+	err := r.db.GetContext(ctx, &standing, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching seller standing from database: %w", err)
+	}
+
+	return &standing, nil
+}
+
+// GetSellerAccountID returns the payment provider account of the user, nil if the user
+// hasn't linked one.
+func (r *listingsRepo) GetSellerAccountID(ctx context.Context, userID string) (*string, error) {
+	query := `
+		SELECT (
+			SELECT id FROM payments.seller_accounts
+			WHERE user_id = $1 AND deleted_at IS NULL
+			ORDER BY created_at DESC
+			LIMIT 1
+		)
+	`
+
+	var sellerID *string
+
+	err := r.db.GetContext(ctx, &sellerID, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching seller account id from database: %w", err)
+	}
+
+	return sellerID, nil
+}
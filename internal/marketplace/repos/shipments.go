@@ -0,0 +1,266 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/pkg/generate"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ShipmentsRepo defines methods for accessing and managing shipments data.
+type ShipmentsRepo interface {
+	GetShippableOrder(ctx context.Context, orderID string) (*dto.ShippableOrder, error)
+	ShipOrder(ctx context.Context, req *dto.CreateShipmentRequest) (*dto.Shipment, error)
+	GetShipment(ctx context.Context, orderID string) (*dto.Shipment, error)
+	GetShipmentsToTrack(ctx context.Context, limit int) ([]dto.Shipment, error)
+	RecordShipmentEvents(
+		ctx context.Context,
+		shipment *dto.Shipment,
+		events []dto.ShipmentEvent,
+	) error
+}
+
+type shipmentsRepo struct {
+	db *sqlx.DB
+}
+
+// NewShipmentsRepo create new instance of shipments repository.
+func NewShipmentsRepo(db *sqlx.DB) *shipmentsRepo { //nolint:revive
+	return &shipmentsRepo{db: db}
+}
+
+// shipmentColumns selects shipments without their events.
+const shipmentColumns = `
+	id, payment_id, carrier, tracking_number, status, shipped_at, delivered_at, checked_at,
+	created_at, updated_at
+`
+
+func (r *shipmentsRepo) GetShippableOrder(
+	ctx context.Context,
+	orderID string,
+) (*dto.ShippableOrder, error) {
+	query := `
+		SELECT
+			p.id,
+			l.title AS listing_title,
+			p.buyer_id,
+			b.email AS buyer_email,
+			l.user_id AS seller_id,
+			s.email AS seller_email,
+			p.shipping_method,
+			sh.status AS shipment_status,
+			p.refunded_at
+		FROM payments.payments p
+			JOIN listings.listings l ON l.id = p.listing_id
+			JOIN auth.users b ON b.id = p.buyer_id
+			JOIN auth.users s ON s.id = l.user_id
+			LEFT JOIN payments.shipments sh ON sh.payment_id = p.id
+		WHERE p.id = $1
+	`
+
+	var order dto.ShippableOrder
+
+	err := r.db.GetContext(ctx, &order, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching shippable order from database: %w", err)
+	}
+
+	return &order, nil
+}
+
+// ShipOrder stores the shipment of an order with a single shipped event. Shipping an order
+// again replaces its carrier, tracking number and events, returning sql.ErrNoRows when the
+// order has already been delivered.
+func (r *shipmentsRepo) ShipOrder(
+	ctx context.Context,
+	req *dto.CreateShipmentRequest,
+) (*dto.Shipment, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("starting db transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	upsertShipmentQ := `
+		INSERT INTO payments.shipments (id, payment_id, carrier, tracking_number)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (payment_id) DO UPDATE
+		SET
+			carrier = EXCLUDED.carrier,
+			tracking_number = EXCLUDED.tracking_number,
+			status = 'shipped',
+			shipped_at = NOW(),
+			delivered_at = NULL,
+			checked_at = NULL,
+			updated_at = NOW()
+		WHERE payments.shipments.status <> 'delivered'
+		RETURNING ` + shipmentColumns
+
+	var shipment dto.Shipment
+
+	err = tx.GetContext(ctx, &shipment, upsertShipmentQ,
+		generate.ID("shipment"),
+		req.OrderID,
+		req.Carrier,
+		req.TrackingNumber,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("upserting shipment in database: %w", err)
+	}
+
+	_, err = tx.ExecContext(
+		ctx, `DELETE FROM payments.shipment_events WHERE shipment_id = $1`, shipment.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("deleting previous shipment events: %w", err)
+	}
+
+	insertEventQ := `
+		INSERT INTO payments.shipment_events (id, shipment_id, status, description, occurred_at)
+		VALUES ($1, $2, 'shipped', $3, $4)
+		RETURNING id, shipment_id, status, description, location, occurred_at, created_at
+	`
+
+	var event dto.ShipmentEvent
+
+	err = tx.GetContext(ctx, &event, insertEventQ,
+		generate.ID("shipevt"),
+		shipment.ID,
+		fmt.Sprintf("Shipped with %s", req.Carrier),
+		shipment.ShippedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("inserting shipped event into database: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("committing transaction for shipping order: %w", err)
+	}
+
+	shipment.Events = []dto.ShipmentEvent{event}
+
+	return &shipment, nil
+}
+
+// GetShipment returns the shipment of an order along with its events, newest first.
+func (r *shipmentsRepo) GetShipment(ctx context.Context, orderID string) (*dto.Shipment, error) {
+	query := `SELECT ` + shipmentColumns + ` FROM payments.shipments WHERE payment_id = $1`
+
+	var shipment dto.Shipment
+
+	err := r.db.GetContext(ctx, &shipment, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching shipment from database: %w", err)
+	}
+
+	eventsQ := `
+		SELECT id, shipment_id, status, description, location, occurred_at, created_at
+		FROM payments.shipment_events
+		WHERE shipment_id = $1
+		ORDER BY occurred_at DESC, created_at DESC
+	`
+
+	shipment.Events = []dto.ShipmentEvent{}
+
+	err = r.db.SelectContext(ctx, &shipment.Events, eventsQ, shipment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching shipment events from database: %w", err)
+	}
+
+	return &shipment, nil
+}
+
+// GetShipmentsToTrack returns undelivered shipments, least recently checked first.
+func (r *shipmentsRepo) GetShipmentsToTrack(
+	ctx context.Context,
+	limit int,
+) ([]dto.Shipment, error) {
+	query := `
+		SELECT ` + shipmentColumns + `
+		FROM payments.shipments
+		WHERE status <> 'delivered'
+		ORDER BY checked_at NULLS FIRST
+		LIMIT $1
+	`
+
+	shipments := []dto.Shipment{}
+
+	err := r.db.SelectContext(ctx, &shipments, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("fetching shipments to track from database: %w", err)
+	}
+
+	return shipments, nil
+}
+
+// RecordShipmentEvents stores events reported by the carrier, skipping known ones, and
+// saves status of the shipment as checked now.
+func (r *shipmentsRepo) RecordShipmentEvents(
+	ctx context.Context,
+	shipment *dto.Shipment,
+	events []dto.ShipmentEvent,
+) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting db transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	insertEventQ := `
+		INSERT INTO payments.shipment_events
+			(id, shipment_id, status, description, location, occurred_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (shipment_id, status, occurred_at) DO NOTHING
+	`
+
+	for _, event := range events {
+		_, err = tx.ExecContext(ctx, insertEventQ,
+			generate.ID("shipevt"),
+			shipment.ID,
+			event.Status,
+			event.Description,
+			event.Location,
+			event.OccurredAt,
+		)
+		if err != nil {
+			return fmt.Errorf("inserting shipment event into database: %w", err)
+		}
+	}
+
+	updateShipmentQ := `
+		UPDATE payments.shipments
+		SET
+			status = $2,
+			delivered_at = $3,
+			checked_at = NOW(),
+			updated_at = CASE WHEN status <> $2 THEN NOW() ELSE updated_at END
+		WHERE id = $1
+	`
+
+	_, err = tx.ExecContext(
+		ctx, updateShipmentQ, shipment.ID, shipment.Status, shipment.DeliveredAt,
+	)
+	if err != nil {
+		return fmt.Errorf("updating shipment status in database: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("committing transaction for recording shipment events: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/pkg/generate"
+)
+
+func (r *listingsRepo) CreateShippingProfile(
+	ctx context.Context,
+	req *dto.CreateShippingProfileRequest,
+) (*dto.ShippingProfile, error) {
+	query := `
+		INSERT INTO listings.shipping_profiles (id, user_id, name, options, currency)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, user_id, name, options, currency, created_at, updated_at
+	`
+
+	var created dto.ShippingProfile
+
+	err := r.db.GetContext(
+		ctx,
+		&created,
+		query,
+		generate.ID("shipprof"),
+		req.UserID,
+		req.Name,
+		req.Options,
+		req.Currency,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("inserting shipping profile into database: %w", err)
+	}
+
+	return &created, nil
+}
+
+func (r *listingsRepo) GetShippingProfiles(
+	ctx context.Context,
+	userID string,
+) ([]dto.ShippingProfile, error) {
+	query := `
+		SELECT id, user_id, name, options, currency, created_at, updated_at
+		FROM listings.shipping_profiles
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+
+	profiles := []dto.ShippingProfile{}
+
+	err := r.db.SelectContext(ctx, &profiles, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching shipping profiles from database: %w", err)
+	}
+
+	return profiles, nil
+}
+
+func (r *listingsRepo) GetShippingProfile(
+	ctx context.Context,
+	profileID, userID string,
+) (*dto.ShippingProfile, error) {
+	query := `
+		SELECT id, user_id, name, options, currency, created_at, updated_at
+		FROM listings.shipping_profiles
+		WHERE id = $1 AND user_id = $2
+	`
+
+	var profile dto.ShippingProfile
+
+	err := r.db.GetContext(ctx, &profile, query, profileID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching shipping profile from database: %w", err)
+	}
+
+	return &profile, nil
+}
+
+func (r *listingsRepo) UpdateShippingProfile(
+	ctx context.Context,
+	req *dto.UpdateShippingProfileRequest,
+) (*dto.ShippingProfile, error) {
+	query := `
+		UPDATE listings.shipping_profiles
+		SET
+			name = COALESCE($3, name),
+			options = COALESCE($4, options),
+			updated_at = NOW()
+		WHERE id = $1 AND user_id = $2
+		RETURNING id, user_id, name, options, currency, created_at, updated_at
+	`
+
+	var updated dto.ShippingProfile
+
+	err := r.db.GetContext(
+		ctx,
+		&updated,
+		query,
+		req.ID,
+		req.UserID,
+		req.Name,
+		req.Options,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("updating shipping profile in database: %w", err)
+	}
+
+	return &updated, nil
+}
+
+// DeleteShippingProfile deletes a shipping profile, listings using it are left without
+// shipping options.
+func (r *listingsRepo) DeleteShippingProfile(ctx context.Context, profileID, userID string) error {
+	query := `DELETE FROM listings.shipping_profiles WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, profileID, userID)
+	if err != nil {
+		return fmt.Errorf("deleting shipping profile from database: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("reading affected rows of shipping profile delete: %w", err)
+	}
+
+	if affected == 0 {
+		return ErrNoRowsReturned
+	}
+
+	return nil
+}
+
+func (r *listingsRepo) SetListingShippingProfile(
+	ctx context.Context,
+	req *dto.SetListingShippingRequest,
+) error {
+	query := `
+		UPDATE listings.listings
+		SET shipping_profile_id = $3, updated_at = NOW()
+		WHERE id = $1 AND user_id = $2
+	`
+
+	_, err := r.db.ExecContext(ctx, query, req.ListingID, req.UserID, req.ShippingProfileID)
+	if err != nil {
+		return fmt.Errorf("setting listing shipping profile in database: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package screening
+
+import (
+	"context"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+)
+
+const (
+	duplicatesScreenerName = "duplicate_listing"
+	// duplicateCandidatesLimit caps how many recent listings of the seller are compared.
+	duplicateCandidatesLimit = 200
+)
+
+// SellerListingsSource provides other active listings of a seller.
+type SellerListingsSource interface {
+	GetSellerListingTexts(
+		ctx context.Context,
+		userID, excludeListingID string,
+		limit int,
+	) ([]dto.ListingText, error)
+}
+
+// DuplicatesScreener flags listings whose title and description are nearly the same as
+// another active listing of the seller.
+type DuplicatesScreener struct {
+	source        SellerListingsSource
+	minSimilarity float64
+}
+
+// NewDuplicatesScreener creates a DuplicatesScreener flagging listings with at least
+// minSimilarity, between 0 and 1, to another listing of the seller.
+func NewDuplicatesScreener(source SellerListingsSource, minSimilarity float64) *DuplicatesScreener {
+	return &DuplicatesScreener{
+		source:        source,
+		minSimilarity: minSimilarity,
+	}
+}
+
+// Screen compares the listing with other active listings of its seller.
+func (s *DuplicatesScreener) Screen(
+	ctx context.Context,
+	listing *dto.Listing,
+) ([]dto.ScreeningFinding, error) {
+	others, err := s.source.GetSellerListingTexts(
+		ctx, listing.UserID, listing.ID, duplicateCandidatesLimit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("fetching seller listings: %w", err)
+	}
+
+	text := listing.Title + "\n" + listing.Description
+
+	var (
+		duplicateID string
+		best        float64
+	)
+
+	for _, other := range others {
+		score := similarity(text, other.Title+"\n"+other.Description)
+		if score >= s.minSimilarity && score > best {
+			duplicateID, best = other.ID, score
+		}
+	}
+
+	if duplicateID == "" {
+		return nil, nil
+	}
+
+	return []dto.ScreeningFinding{{
+		Screener: duplicatesScreenerName,
+		Verdict:  dto.ScreeningVerdictFlag,
+		Reason:   dto.ReportReasonSpam,
+		Details: fmt.Sprintf(
+			"listing is %.0f%% similar to listing %s", best*percent, duplicateID,
+		),
+	}}, nil
+}
+
+// similarity returns the Jaccard similarity of the sets of words of both texts.
+func similarity(a, b string) float64 {
+	setA := make(map[string]struct{})
+	for _, w := range words(a) {
+		setA[w] = struct{}{}
+	}
+
+	setB := make(map[string]struct{})
+	for _, w := range words(b) {
+		setB[w] = struct{}{}
+	}
+
+	if len(setA) == 0 && len(setB) == 0 {
+		return 0
+	}
+
+	common := 0
+
+	for w := range setA {
+		if _, ok := setB[w]; ok {
+			common++
+		}
+	}
+
+	return float64(common) / float64(len(setA)+len(setB)-common)
+}
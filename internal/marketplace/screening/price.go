@@ -0,0 +1,76 @@
+package screening
+
+import (
+	"context"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+)
+
+const (
+	priceScreenerName = "price_anomaly"
+	percent           = 100
+)
+
+// PriceStatsSource provides prices of other listings in a category.
+type PriceStatsSource interface {
+	GetCategoryPriceStats(
+		ctx context.Context,
+		categoryID, currency, excludeListingID string,
+	) (*dto.CategoryPriceStats, error)
+}
+
+// PriceAnomalyScreener flags listings priced far below other listings of the same category,
+// a common sign of scams.
+type PriceAnomalyScreener struct {
+	source     PriceStatsSource
+	minRatio   float64
+	minSamples int
+}
+
+// NewPriceAnomalyScreener creates a PriceAnomalyScreener flagging listings priced below
+// minRatio of the category median. Categories with less than minSamples listings
+// in the same currency are not checked.
+func NewPriceAnomalyScreener(
+	source PriceStatsSource,
+	minRatio float64,
+	minSamples int,
+) *PriceAnomalyScreener {
+	return &PriceAnomalyScreener{
+		source:     source,
+		minRatio:   minRatio,
+		minSamples: minSamples,
+	}
+}
+
+// Screen compares the listing price with the median price of its category.
+func (s *PriceAnomalyScreener) Screen(
+	ctx context.Context,
+	listing *dto.Listing,
+) ([]dto.ScreeningFinding, error) {
+	stats, err := s.source.GetCategoryPriceStats(
+		ctx, listing.CategoryID, listing.Currency, listing.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("fetching category price stats: %w", err)
+	}
+
+	if stats.SampleSize < s.minSamples || stats.SampleSize == 0 {
+		return nil, nil
+	}
+
+	if float64(listing.PriceInCents) >= stats.MedianPriceInCents*s.minRatio {
+		return nil, nil
+	}
+
+	return []dto.ScreeningFinding{{
+		Screener: priceScreenerName,
+		Verdict:  dto.ScreeningVerdictFlag,
+		Reason:   dto.ReportReasonFraud,
+		Details: fmt.Sprintf(
+			"price %d is %.0f%% of category median %.0f",
+			listing.PriceInCents,
+			float64(listing.PriceInCents)/stats.MedianPriceInCents*percent,
+			stats.MedianPriceInCents,
+		),
+	}}, nil
+}
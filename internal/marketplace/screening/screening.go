@@ -0,0 +1,56 @@
+// Package screening implements automated checks of listing content, which can flag listings
+// for the moderation queue or block them.
+package screening
+
+import (
+	"context"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"strings"
+	"unicode"
+)
+
+// ListingScreener checks a new or updated listing and returns the problems it found.
+type ListingScreener interface {
+	Screen(ctx context.Context, listing *dto.Listing) ([]dto.ScreeningFinding, error)
+}
+
+// Chain is a ListingScreener running multiple screeners and collecting all their findings.
+type Chain []ListingScreener
+
+// Screen runs every screener of the chain on the listing.
+func (c Chain) Screen(ctx context.Context, listing *dto.Listing) ([]dto.ScreeningFinding, error) {
+	var findings []dto.ScreeningFinding
+
+	for _, screener := range c {
+		found, err := screener.Screen(ctx, listing)
+		if err != nil {
+			return nil, fmt.Errorf("running screener: %w", err)
+		}
+
+		findings = append(findings, found...)
+	}
+
+	return findings, nil
+}
+
+// Blocked returns details of findings which block the listing, joined in one message.
+// It returns an empty string when the listing is not blocked.
+func Blocked(findings []dto.ScreeningFinding) string {
+	var details []string
+
+	for _, f := range findings {
+		if f.Verdict == dto.ScreeningVerdictBlock {
+			details = append(details, f.Details)
+		}
+	}
+
+	return strings.Join(details, "; ")
+}
+
+// words splits text into lowercase words, dropping punctuation.
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
@@ -0,0 +1,165 @@
+package screening
+
+import (
+	"context"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePriceStats struct {
+	stats dto.CategoryPriceStats
+}
+
+func (f *fakePriceStats) GetCategoryPriceStats(
+	_ context.Context,
+	_, _, _ string,
+) (*dto.CategoryPriceStats, error) {
+	return &f.stats, nil
+}
+
+type fakeSellerListings struct {
+	listings []dto.ListingText
+}
+
+func (f *fakeSellerListings) GetSellerListingTexts(
+	_ context.Context,
+	_, _ string,
+	_ int,
+) ([]dto.ListingText, error) {
+	return f.listings, nil
+}
+
+func newListing(title, description string, price int) *dto.Listing {
+	return &dto.Listing{ //nolint:exhaustruct
+		ID:           "listing_1",
+		UserID:       "user_1",
+		CategoryID:   "cat_1",
+		Title:        title,
+		Description:  description,
+		PriceInCents: price,
+		Currency:     "EUR",
+	}
+}
+
+func TestWordsScreener_MatchesWholeWordsAndPhrases(t *testing.T) {
+	t.Parallel()
+
+	s, err := NewWordsScreener([]string{"Replica", "fake id"}, nil, dto.ScreeningVerdictBlock)
+	require.NoError(t, err)
+
+	findings, err := s.Screen(
+		context.Background(),
+		newListing("Designer bag REPLICA", "Comes with a FAKE-ID card", 5000),
+	)
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, dto.ScreeningVerdictBlock, findings[0].Verdict)
+	assert.Contains(t, findings[0].Details, "replica")
+	assert.Contains(t, findings[0].Details, "fake id")
+
+	findings, err = s.Screen(
+		context.Background(),
+		newListing("Replicated vinyl records", "Mint condition", 5000),
+	)
+	require.NoError(t, err)
+	assert.Empty(t, findings)
+}
+
+func TestWordsScreener_MatchesPatterns(t *testing.T) {
+	t.Parallel()
+
+	s, err := NewWordsScreener(nil, []string{`(?i)whats?app\s*\+?\d+`}, dto.ScreeningVerdictFlag)
+	require.NoError(t, err)
+
+	findings, err := s.Screen(
+		context.Background(),
+		newListing("Mountain bike", "Contact me on WhatsApp +37060000000", 5000),
+	)
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, dto.ScreeningVerdictFlag, findings[0].Verdict)
+
+	_, err = NewWordsScreener(nil, []string{"("}, dto.ScreeningVerdictFlag)
+	require.Error(t, err)
+}
+
+func TestPriceAnomalyScreener_FlagsPricesFarBelowMedian(t *testing.T) {
+	t.Parallel()
+
+	source := &fakePriceStats{
+		stats: dto.CategoryPriceStats{MedianPriceInCents: 80000, SampleSize: 20},
+	}
+	s := NewPriceAnomalyScreener(source, 0.2, 10)
+
+	findings, err := s.Screen(context.Background(), newListing("iPhone 15 Pro", "New", 1000))
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, dto.ReportReasonFraud, findings[0].Reason)
+
+	findings, err = s.Screen(context.Background(), newListing("iPhone 15 Pro", "New", 60000))
+	require.NoError(t, err)
+	assert.Empty(t, findings)
+}
+
+func TestPriceAnomalyScreener_SkipsSmallCategories(t *testing.T) {
+	t.Parallel()
+
+	source := &fakePriceStats{
+		stats: dto.CategoryPriceStats{MedianPriceInCents: 80000, SampleSize: 3},
+	}
+	s := NewPriceAnomalyScreener(source, 0.2, 10)
+
+	findings, err := s.Screen(context.Background(), newListing("iPhone 15 Pro", "New", 1000))
+	require.NoError(t, err)
+	assert.Empty(t, findings)
+}
+
+func TestDuplicatesScreener_FlagsMostSimilarListing(t *testing.T) {
+	t.Parallel()
+
+	source := &fakeSellerListings{listings: []dto.ListingText{
+		{ID: "other_1", Title: "Red road bike", Description: "Carbon frame, size 56"},
+		{ID: "other_2", Title: "Blue road bike", Description: "Aluminium frame, size 54"},
+	}}
+	s := NewDuplicatesScreener(source, 0.8)
+
+	findings, err := s.Screen(
+		context.Background(),
+		newListing("Red road bike!", "Carbon frame, size 56", 5000),
+	)
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Contains(t, findings[0].Details, "other_1")
+
+	findings, err = s.Screen(
+		context.Background(),
+		newListing("Kids scooter", "Barely used", 5000),
+	)
+	require.NoError(t, err)
+	assert.Empty(t, findings)
+}
+
+func TestChain_CollectsFindingsAndBlocked(t *testing.T) {
+	t.Parallel()
+
+	blocking, err := NewWordsScreener([]string{"replica"}, nil, dto.ScreeningVerdictBlock)
+	require.NoError(t, err)
+
+	flagging := NewPriceAnomalyScreener(
+		&fakePriceStats{stats: dto.CategoryPriceStats{MedianPriceInCents: 80000, SampleSize: 20}},
+		0.2,
+		1,
+	)
+
+	findings, err := Chain{blocking, flagging}.Screen(
+		context.Background(),
+		newListing("Replica watch", "Looks real", 1000),
+	)
+	require.NoError(t, err)
+	require.Len(t, findings, 2)
+	assert.Contains(t, Blocked(findings), "replica")
+	assert.Empty(t, Blocked(findings[1:]))
+}
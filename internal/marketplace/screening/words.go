@@ -0,0 +1,93 @@
+package screening
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"regexp"
+	"strings"
+)
+
+const wordsScreenerName = "banned_words"
+
+// ErrUnknownVerdict is returned when a screener is configured with an unknown verdict.
+var ErrUnknownVerdict = errors.New("unknown screening verdict")
+
+// WordsScreener matches listing title and description against banned words and phrases,
+// which match whole words regardless of case, and banned regular expressions.
+type WordsScreener struct {
+	phrases  []string
+	patterns []*regexp.Regexp
+	verdict  dto.ScreeningVerdict
+}
+
+// NewWordsScreener creates a WordsScreener giving the verdict to listings containing any of
+// the banned words or matching any of the patterns.
+func NewWordsScreener(
+	banned, patterns []string,
+	verdict dto.ScreeningVerdict,
+) (*WordsScreener, error) {
+	if verdict != dto.ScreeningVerdictFlag && verdict != dto.ScreeningVerdictBlock {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownVerdict, verdict)
+	}
+
+	s := &WordsScreener{
+		phrases:  make([]string, 0, len(banned)),
+		patterns: make([]*regexp.Regexp, 0, len(patterns)),
+		verdict:  verdict,
+	}
+
+	for _, w := range banned {
+		phrase := strings.Join(words(w), " ")
+		if phrase != "" {
+			s.phrases = append(s.phrases, phrase)
+		}
+	}
+
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("compiling banned pattern %q: %w", p, err)
+		}
+
+		s.patterns = append(s.patterns, re)
+	}
+
+	return s, nil
+}
+
+// Screen reports banned words and patterns found in the listing.
+func (s *WordsScreener) Screen(
+	_ context.Context,
+	listing *dto.Listing,
+) ([]dto.ScreeningFinding, error) {
+	text := listing.Title + "\n" + listing.Description
+	padded := " " + strings.Join(words(text), " ") + " "
+
+	var matches []string
+
+	for _, phrase := range s.phrases {
+		if strings.Contains(padded, " "+phrase+" ") {
+			matches = append(matches, phrase)
+		}
+	}
+
+	for _, re := range s.patterns {
+		match := re.FindString(text)
+		if match != "" {
+			matches = append(matches, match)
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	return []dto.ScreeningFinding{{
+		Screener: wordsScreenerName,
+		Verdict:  s.verdict,
+		Reason:   dto.ReportReasonProhibitedItem,
+		Details:  "listing contains banned content: " + strings.Join(matches, ", "),
+	}}, nil
+}
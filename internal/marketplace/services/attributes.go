@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"regexp"
+	"slices"
+	"unicode/utf8"
+)
+
+const maxAttributeTextLength = 500
+
+var attributeKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// GetCategoryAttributes handles logic for fetching attribute schema of a category,
+// including attributes inherited from its parent categories.
+func (s *ListingsService) GetCategoryAttributes(
+	ctx context.Context,
+	categoryID string,
+) ([]dto.CategoryAttribute, error) {
+	_, err := s.repo.GetCategoryByID(ctx, categoryID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCategoryNotFound
+		}
+
+		return nil, fmt.Errorf("fetching category: %w", err)
+	}
+
+	attributes, err := s.repo.GetCategoryAttributes(ctx, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching category attributes: %w", err)
+	}
+
+	return attributes, nil
+}
+
+// SetCategoryAttributes handles logic for replacing attribute schema of a category.
+// Values already stored on listings are kept and validated again on their next update.
+func (s *ListingsService) SetCategoryAttributes(
+	ctx context.Context,
+	req *dto.SetCategoryAttributesRequest,
+) ([]dto.CategoryAttribute, error) {
+	category, err := s.repo.GetCategoryByID(ctx, req.CategoryID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCategoryNotFound
+		}
+
+		return nil, fmt.Errorf("fetching category: %w", err)
+	}
+
+	inherited := []dto.CategoryAttribute{}
+
+	if category.ParentID != nil {
+		inherited, err = s.repo.GetCategoryAttributes(ctx, *category.ParentID)
+		if err != nil {
+			return nil, fmt.Errorf("fetching parent category attributes: %w", err)
+		}
+	}
+
+	err = validateAttributeSchema(req.Attributes, inherited)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.SetCategoryAttributes(ctx, req.CategoryID, req.Attributes)
+	if err != nil {
+		return nil, fmt.Errorf("setting category attributes: %w", err)
+	}
+
+	return s.GetCategoryAttributes(ctx, req.CategoryID)
+}
+
+// validateListingAttributes checks listing attribute values against attribute schema
+// of the listing category.
+func (s *ListingsService) validateListingAttributes(
+	ctx context.Context,
+	categoryID string,
+	attributes dto.ListingAttributes,
+) error {
+	schema, err := s.repo.GetCategoryAttributes(ctx, categoryID)
+	if err != nil {
+		return fmt.Errorf("fetching category attributes: %w", err)
+	}
+
+	defined := make(map[string]bool, len(schema))
+
+	for _, attr := range schema {
+		defined[attr.Key] = true
+
+		value, ok := attributes[attr.Key]
+		if !ok || value == nil {
+			if attr.Required {
+				return fmt.Errorf("%w: %s is required", ErrInvalidAttributes, attr.Key)
+			}
+
+			continue
+		}
+
+		err = validateAttributeValue(&attr, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	for key := range attributes {
+		if !defined[key] {
+			return fmt.Errorf("%w: unknown attribute %s", ErrInvalidAttributes, key)
+		}
+	}
+
+	return nil
+}
+
+func validateAttributeValue(attr *dto.CategoryAttribute, value any) error {
+	valid := false
+
+	switch attr.Type {
+	case dto.AttributeTypeNumber:
+		_, valid = value.(float64)
+	case dto.AttributeTypeBoolean:
+		_, valid = value.(bool)
+	case dto.AttributeTypeText:
+		text, ok := value.(string)
+		valid = ok && utf8.RuneCountInString(text) <= maxAttributeTextLength
+	case dto.AttributeTypeEnum:
+		option, ok := value.(string)
+		valid = ok && slices.Contains(attr.Options, option)
+	}
+
+	if !valid {
+		return fmt.Errorf("%w: %s must be a valid %s", ErrInvalidAttributes, attr.Key, attr.Type)
+	}
+
+	return nil
+}
+
+func validateAttributeSchema(attributes, inherited []dto.CategoryAttribute) error {
+	keys := make(map[string]bool, len(attributes)+len(inherited))
+	for _, attr := range inherited {
+		keys[attr.Key] = true
+	}
+
+	for _, attr := range attributes {
+		if !attributeKeyPattern.MatchString(attr.Key) {
+			return fmt.Errorf(
+				"%w: key %s must contain only lowercase letters, digits and underscores",
+				ErrInvalidAttributeSchema,
+				attr.Key,
+			)
+		}
+
+		if keys[attr.Key] {
+			return fmt.Errorf("%w: key %s is already defined", ErrInvalidAttributeSchema, attr.Key)
+		}
+
+		if attr.Type != dto.AttributeTypeEnum && len(attr.Options) > 0 {
+			return fmt.Errorf(
+				"%w: only enum attributes can have options, got %s",
+				ErrInvalidAttributeSchema,
+				attr.Key,
+			)
+		}
+
+		keys[attr.Key] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,354 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/internal/marketplace/repos"
+	"golang-connect-marketplace/pkg/generate"
+)
+
+const categoriesImageFolder = "categories"
+
+// CreateCategory handles logic for creating new category. Slugs are normalized to lower case
+// letters, digits and dashes, missing ones are generated from the title and the parent.
+func (s *ListingsService) CreateCategory(
+	ctx context.Context,
+	req *dto.Category,
+) (*dto.Category, error) {
+	if req.ParentID != nil && *req.ParentID == "" {
+		req.ParentID = nil
+	}
+
+	categories, err := s.categoriesByID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var parent *dto.Category
+
+	if req.ParentID != nil {
+		p, ok := categories[*req.ParentID]
+		if !ok {
+			return nil, ErrInvalidCategoryParent
+		}
+
+		parent = &p
+
+		if s.exceedsMaxDepth(categoryDepth(categories, p.ID) + 1) {
+			return nil, ErrCategoryTooDeep
+		}
+	}
+
+	if req.Slug == "" {
+		req.Slug = categorySlug(parent, req.Title)
+	} else {
+		req.Slug = generate.Slug(req.Slug)
+	}
+
+	if req.Slug == "" {
+		return nil, ErrEmptyCategorySlug
+	}
+
+	stored, err := s.storage.StoreImage(ctx, req.FileHeader, categoriesImageFolder)
+	if err != nil {
+		return nil, fmt.Errorf("storing category image: %w", err)
+	}
+
+	req.ImagePath = stored.Path
+	req.ImageVariants = stored.Variants
+
+	resp, err := s.repo.CreateCategory(ctx, req)
+	if err != nil {
+		s.deleteStoredImage(ctx, stored.Path, stored.Variants)
+
+		if errors.Is(err, repos.ErrCategoryConflict) {
+			return nil, ErrCategoryConflict
+		}
+
+		return nil, fmt.Errorf("creating category: %w", err)
+	}
+
+	err = s.withCategoryImageURLs(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// GetCategories handles logic for fetching categories as a tree of top level categories
+// with their subcategories nested in display order.
+func (s *ListingsService) GetCategories(ctx context.Context) ([]dto.Category, error) {
+	resp, err := s.repo.GetCategories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetching categories: %w", err)
+	}
+
+	for i := range resp {
+		err = s.withCategoryImageURLs(ctx, &resp[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return categoryTree(resp, nil), nil
+}
+
+// UpdateCategory handles logic for updating a category. A new image replaces the old one,
+// which is deleted from storage only after the category row was updated. An empty slug
+// is regenerated from the category title and its parent, other slugs are normalized.
+func (s *ListingsService) UpdateCategory(
+	ctx context.Context,
+	req *dto.UpdateCategoryRequest,
+) (*dto.Category, error) {
+	categories, err := s.categoriesByID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	current, ok := categories[req.ID]
+	if !ok {
+		return nil, ErrCategoryNotFound
+	}
+
+	parentID := current.ParentID
+	if req.ParentID != nil {
+		parentID = req.ParentID
+		if *parentID == "" {
+			parentID = nil
+		}
+	}
+
+	var parent *dto.Category
+
+	if parentID != nil {
+		p, err := s.validateCategoryParent(categories, req.ID, *parentID)
+		if err != nil {
+			return nil, err
+		}
+
+		parent = p
+	}
+
+	if req.Slug != nil {
+		slug := generate.Slug(*req.Slug)
+
+		if *req.Slug == "" {
+			title := current.Title
+			if req.Title != nil {
+				title = *req.Title
+			}
+
+			slug = categorySlug(parent, title)
+		}
+
+		if slug == "" {
+			return nil, ErrEmptyCategorySlug
+		}
+
+		req.Slug = &slug
+	}
+
+	if req.FileHeader == nil {
+		return s.updateCategory(ctx, req)
+	}
+
+	stored, err := s.storage.StoreImage(ctx, req.FileHeader, categoriesImageFolder)
+	if err != nil {
+		return nil, fmt.Errorf("storing category image: %w", err)
+	}
+
+	req.ImagePath = &stored.Path
+	req.ImageVariants = stored.Variants
+
+	resp, err := s.updateCategory(ctx, req)
+	if err != nil {
+		s.deleteStoredImage(ctx, stored.Path, stored.Variants)
+
+		return nil, err
+	}
+
+	s.deleteStoredImage(ctx, current.ImagePath, current.ImageVariants)
+
+	return resp, nil
+}
+
+// DeleteCategory handles logic for deleting a category without subcategories.
+func (s *ListingsService) DeleteCategory(ctx context.Context, categoryID string) error {
+	categories, err := s.categoriesByID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := categories[categoryID]; !ok {
+		return ErrCategoryNotFound
+	}
+
+	for _, c := range categories {
+		if c.ParentID != nil && *c.ParentID == categoryID {
+			return ErrCategoryHasChildren
+		}
+	}
+
+	err = s.repo.DeleteCategory(ctx, categoryID)
+	if err != nil {
+		if errors.Is(err, repos.ErrNoRowsReturned) {
+			return ErrCategoryNotFound
+		}
+
+		return fmt.Errorf("deleting category: %w", err)
+	}
+
+	return nil
+}
+
+func (s *ListingsService) updateCategory(
+	ctx context.Context,
+	req *dto.UpdateCategoryRequest,
+) (*dto.Category, error) {
+	resp, err := s.repo.UpdateCategory(ctx, req)
+	if err != nil {
+		if errors.Is(err, repos.ErrNoRowsReturned) {
+			return nil, ErrCategoryNotFound
+		}
+
+		if errors.Is(err, repos.ErrCategoryConflict) {
+			return nil, ErrCategoryConflict
+		}
+
+		return nil, fmt.Errorf("updating category: %w", err)
+	}
+
+	err = s.withCategoryImageURLs(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// validateCategoryParent checks that category can be moved under parentID without creating
+// a cycle or exceeding configured max depth.
+func (s *ListingsService) validateCategoryParent(
+	categories map[string]dto.Category,
+	categoryID, parentID string,
+) (*dto.Category, error) {
+	parent, ok := categories[parentID]
+	if !ok {
+		return nil, ErrInvalidCategoryParent
+	}
+
+	for id := &parentID; id != nil; id = categories[*id].ParentID {
+		if *id == categoryID {
+			return nil, ErrInvalidCategoryParent
+		}
+	}
+
+	depth := categoryDepth(categories, parentID) + categoryHeight(categories, categoryID)
+	if s.exceedsMaxDepth(depth) {
+		return nil, ErrCategoryTooDeep
+	}
+
+	return &parent, nil
+}
+
+func (s *ListingsService) exceedsMaxDepth(depth int) bool {
+	return s.listingsCfg.CategoryMaxDepth > 0 && depth > s.listingsCfg.CategoryMaxDepth
+}
+
+func (s *ListingsService) categoriesByID(ctx context.Context) (map[string]dto.Category, error) {
+	categories, err := s.repo.GetCategories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetching categories: %w", err)
+	}
+
+	byID := make(map[string]dto.Category, len(categories))
+	for _, c := range categories {
+		byID[c.ID] = c
+	}
+
+	return byID, nil
+}
+
+func (s *ListingsService) withCategoryImageURLs(
+	ctx context.Context,
+	category *dto.Category,
+) error {
+	url, err := s.storage.URL(ctx, category.ImagePath, s.cfg.URLTTL)
+	if err != nil {
+		return fmt.Errorf("creating category image url: %w", err)
+	}
+
+	variants, err := s.variantsWithURLs(ctx, category.ImageVariants)
+	if err != nil {
+		return err
+	}
+
+	category.ImageURL = url
+	category.ImageVariants = variants
+
+	return nil
+}
+
+// categoryDepth returns the number of levels from the top of the tree down to category,
+// top level categories have depth 1.
+func categoryDepth(categories map[string]dto.Category, categoryID string) int {
+	depth := 0
+
+	for id := &categoryID; id != nil; id = categories[*id].ParentID {
+		depth++
+	}
+
+	return depth
+}
+
+// categoryHeight returns the number of levels in the subtree starting at category,
+// categories without subcategories have height 1.
+func categoryHeight(categories map[string]dto.Category, categoryID string) int {
+	height := 0
+
+	for _, c := range categories {
+		if c.ParentID != nil && *c.ParentID == categoryID {
+			height = max(height, categoryHeight(categories, c.ID))
+		}
+	}
+
+	return height + 1
+}
+
+// categoryTree nests categories under their parents, keeping the order they were fetched in.
+func categoryTree(categories []dto.Category, parentID *string) []dto.Category {
+	tree := []dto.Category{}
+
+	for _, c := range categories {
+		if !sameParent(c.ParentID, parentID) {
+			continue
+		}
+
+		c.Children = categoryTree(categories, &c.ID)
+		tree = append(tree, c)
+	}
+
+	return tree
+}
+
+func sameParent(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+// categorySlug generates the slug of a category, prefixed with the slug of its parent.
+// It is empty when the title has no letters or digits.
+func categorySlug(parent *dto.Category, title string) string {
+	slug := generate.Slug(title)
+	if parent == nil || slug == "" {
+		return slug
+	}
+
+	return parent.Slug + "-" + slug
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/pkg/money"
+	"slices"
+	"time"
+)
+
+const (
+	sellerCurrenciesTTL  = 10 * time.Minute
+	sellerCurrenciesSize = 1000
+)
+
+var (
+	// ErrUnsupportedCurrency is returned when listing currency is unknown or can't be settled
+	// by the payment provider or the seller account.
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	// ErrPriceBelowMinimum is returned when listing price is below the minimum of its currency.
+	ErrPriceBelowMinimum = errors.New("price is below the minimum")
+)
+
+// checkPrice checks that the listing is priced in a registered currency which the payment
+// provider can charge and the seller account can be paid out in, and that the price isn't
+// below the currency minimum. The currency code is normalized to upper case.
+func (s *ListingsService) checkPrice(ctx context.Context, listing *dto.Listing) error {
+	price, err := listing.Price()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnsupportedCurrency, err)
+	}
+
+	code := price.Currency.Code
+
+	if !s.provider.SupportsCurrency(price.Currency) {
+		return fmt.Errorf("%w: payments in %s are not supported", ErrUnsupportedCurrency, code)
+	}
+
+	if price.BelowMinimum() {
+		minimum := money.Money{Amount: price.Currency.Minimum, Currency: price.Currency}
+
+		return fmt.Errorf("%w: price must be at least %s", ErrPriceBelowMinimum, minimum)
+	}
+
+	currencies, err := s.sellerCurrencies(ctx, listing.UserID)
+	if err != nil {
+		return err
+	}
+
+	if len(currencies) > 0 && !slices.Contains(currencies, code) {
+		return fmt.Errorf(
+			"%w: seller account can't be paid out in %s",
+			ErrUnsupportedCurrency,
+			code,
+		)
+	}
+
+	listing.Currency = code
+
+	return nil
+}
+
+// sellerCurrencies returns currencies the seller account of the user can be paid out in,
+// nil when the user hasn't linked a seller account yet.
+func (s *ListingsService) sellerCurrencies(ctx context.Context, userID string) ([]string, error) {
+	if currencies, ok := s.currencyCache.Get(userID); ok {
+		return currencies, nil
+	}
+
+	sellerID, err := s.repo.GetSellerAccountID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching seller account: %w", err)
+	}
+
+	if sellerID == nil {
+		return nil, nil
+	}
+
+	currencies, err := s.provider.SellerCurrencies(ctx, *sellerID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching seller account currencies: %w", err)
+	}
+
+	s.currencyCache.Set(userID, currencies)
+
+	return currencies, nil
+}
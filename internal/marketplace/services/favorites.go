@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/internal/marketplace/notifications"
+	"golang-connect-marketplace/internal/marketplace/repos"
+	"slices"
+)
+
+// AddFavorite handles logic for adding an open listing to user's favorites.
+func (s *ListingsService) AddFavorite(ctx context.Context, req *dto.FavoriteRequest) error {
+	listing, err := s.repo.GetListingByID(ctx, req.ListingID)
+	if err != nil {
+		return fmt.Errorf("fetching listing: %w", err)
+	}
+
+	if listing.Status != dto.ListingStatusOpen {
+		return ErrListingIsNotOpen
+	}
+
+	err = s.repo.AddFavorite(ctx, req)
+	if err != nil {
+		return fmt.Errorf("adding favorite: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveFavorite handles logic for removing a listing from user's favorites.
+func (s *ListingsService) RemoveFavorite(ctx context.Context, req *dto.FavoriteRequest) error {
+	err := s.repo.RemoveFavorite(ctx, req)
+	if err != nil {
+		return fmt.Errorf("removing favorite: %w", err)
+	}
+
+	return nil
+}
+
+// GetFavorites handles logic for fetching favorite listings of a user, most recently
+// added first.
+func (s *ListingsService) GetFavorites(
+	ctx context.Context,
+	req *dto.GetFavoritesRequest,
+) (*dto.GetListingsResponse, error) {
+	if req.Limit <= 0 {
+		req.Limit = 10
+	}
+
+	listings, err := s.repo.GetFavoriteListings(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching favorite listings: %w", err)
+	}
+
+	for i := range listings {
+		listings[i].IsFavorited = true
+
+		err = s.withImageURLs(ctx, &listings[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp := &dto.GetListingsResponse{
+		Meta: dto.PaginationMeta{
+			Limit:    req.Limit,
+			Page:     req.Page,
+			Category: nil,
+			Keyword:  nil,
+			Total:    len(listings),
+		},
+		Facets:   nil,
+		Listings: listings,
+	}
+
+	return resp, nil
+}
+
+// withFavorites marks listings favorited by the user, anonymous users have no favorites.
+func (s *ListingsService) withFavorites(
+	ctx context.Context,
+	userID string,
+	listings []dto.Listing,
+) error {
+	if userID == "" || len(listings) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(listings))
+	for _, l := range listings {
+		ids = append(ids, l.ID)
+	}
+
+	favorited, err := s.repo.GetFavoritedListingIDs(ctx, userID, ids)
+	if err != nil {
+		return fmt.Errorf("fetching favorited listings: %w", err)
+	}
+
+	for i := range listings {
+		listings[i].IsFavorited = slices.Contains(favorited, listings[i].ID)
+	}
+
+	return nil
+}
+
+// notifyWatchers sends a notification built by newNotification to every user watching
+// the listing, except the user with skipUserID.
+func notifyWatchers(
+	ctx context.Context,
+	repo repos.ListingsRepo,
+	notifier notifications.Notifier,
+	listingID, skipUserID string,
+	newNotification func(w dto.Watcher) *dto.Notification,
+) error {
+	watchers, err := repo.GetListingWatchers(ctx, listingID)
+	if err != nil {
+		return fmt.Errorf("fetching listing watchers: %w", err)
+	}
+
+	for _, w := range watchers {
+		if w.UserID == skipUserID {
+			continue
+		}
+
+		_ = notifier.Notify(ctx, newNotification(w))
+	}
+
+	return nil
+}
+
+// notifyPriceDrop notifies watchers of a listing whose price was lowered.
+func (s *ListingsService) notifyPriceDrop(ctx context.Context, before, after *dto.Listing) {
+	if after.Currency != before.Currency || after.PriceInCents >= before.PriceInCents {
+		return
+	}
+
+	afterPrice, err := after.Price()
+	if err != nil {
+		return
+	}
+
+	beforePrice, err := before.Price()
+	if err != nil {
+		return
+	}
+
+	_ = notifyWatchers(ctx, s.repo, s.notifier, after.ID, after.UserID,
+		func(w dto.Watcher) *dto.Notification {
+			return &dto.Notification{
+				UserID: w.UserID,
+				Email:  w.Email,
+				Type:   dto.NotificationTypeFavoritePriceDropped,
+				Title:  "Price dropped on a listing you watch",
+				Body: fmt.Sprintf(
+					"%q is now %s, down from %s.",
+					after.Title,
+					afterPrice,
+					beforePrice,
+				),
+				Data: map[string]string{"listing_id": after.ID},
+			}
+		},
+	)
+}
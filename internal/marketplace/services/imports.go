@@ -0,0 +1,353 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	authDto "golang-connect-marketplace/internal/auth/dto"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/internal/marketplace/imports"
+	"golang-connect-marketplace/internal/marketplace/storage"
+	"golang-connect-marketplace/pkg/validation"
+	"io"
+	"mime/multipart"
+)
+
+var (
+	// ErrInvalidImportFile is returned when an uploaded import file can't be read.
+	ErrInvalidImportFile = errors.New("invalid import file")
+	// ErrTooManyImportRows is returned when an import file has more rows than allowed.
+	ErrTooManyImportRows = errors.New("import file has too many rows")
+	// ErrImportNotFound is returned when import doesn't exist or belongs to other user.
+	ErrImportNotFound = errors.New("import not found")
+)
+
+// CreateImport handles logic for uploading a bulk listing import. The file is checked and
+// stored right away, listings are created later by ProcessImports.
+func (s *ListingsService) CreateImport(
+	ctx context.Context,
+	req *dto.CreateImportRequest,
+) (*dto.ImportJob, error) {
+	var err error
+
+	if req.Format == "" {
+		req.Format, err = imports.DetectFormat(req.FileHeader.Filename)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidImportFile, err)
+		}
+	}
+
+	source, err := readUpload(req.FileHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := imports.Parse(req.Format, bytes.NewReader(source))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidImportFile, err)
+	}
+
+	if len(rows) > s.listingsCfg.ImportMaxRows {
+		return nil, ErrTooManyImportRows
+	}
+
+	var images []byte
+
+	if req.ImagesHeader != nil {
+		images, err = readUpload(req.ImagesHeader)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = imports.OpenImages(images, s.cfg.MaxImageFileSize)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidImportFile, err)
+		}
+	}
+
+	job, err := s.repo.CreateImport(ctx, &dto.ImportJob{ //nolint:exhaustruct
+		UserID:    req.UserID,
+		Format:    req.Format,
+		Source:    source,
+		Images:    images,
+		TotalRows: len(rows),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating import: %w", err)
+	}
+
+	return job, nil
+}
+
+// GetImport handles logic for fetching status and row results of an import of the user.
+func (s *ListingsService) GetImport(
+	ctx context.Context,
+	importID, userID string,
+) (*dto.ImportJob, error) {
+	job, err := s.repo.GetImport(ctx, importID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrImportNotFound
+		}
+
+		return nil, fmt.Errorf("fetching import: %w", err)
+	}
+
+	return job, nil
+}
+
+// ProcessImports runs pending imports one by one until none are left. Every row is validated
+// and created like a listing posted through the API, results are stored per row. Imports
+// left running by a stopped worker are resumed after their last recorded row.
+func (s *ListingsService) ProcessImports(ctx context.Context) error {
+	for {
+		job, err := s.repo.ClaimImport(ctx, s.listingsCfg.ImportStaleAfter)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil
+			}
+
+			return fmt.Errorf("claiming import: %w", err)
+		}
+
+		s.runImport(ctx, job)
+
+		// the import is finished even if the job is stopped half way
+		err = s.repo.FinishImport(context.WithoutCancel(ctx), job)
+		if err != nil {
+			return fmt.Errorf("finishing import: %w", err)
+		}
+
+		if ctx.Err() != nil {
+			return fmt.Errorf("processing imports: %w", ctx.Err())
+		}
+	}
+}
+
+// ExportListings handles logic for exporting all listings of the user in the format
+// of imports.
+func (s *ListingsService) ExportListings(
+	ctx context.Context,
+	req *dto.ExportListingsRequest,
+) ([]dto.ExportRow, error) {
+	listings, err := s.repo.GetUserListings(ctx, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching user listings: %w", err)
+	}
+
+	rows := make([]dto.ExportRow, 0, len(listings))
+
+	for i := range listings {
+		listing := &listings[i]
+
+		err = s.withImageURLs(ctx, listing)
+		if err != nil {
+			return nil, err
+		}
+
+		urls := make([]string, 0, len(listing.Images))
+		for _, img := range listing.Images {
+			urls = append(urls, img.URL)
+		}
+
+		rows = append(rows, dto.ExportRow{
+			ID:     listing.ID,
+			Status: listing.Status,
+			ImportRow: dto.ImportRow{
+				CategoryID:   listing.CategoryID,
+				Title:        listing.Title,
+				Description:  listing.Description,
+				PriceInCents: listing.PriceInCents,
+				Currency:     listing.Currency,
+				Attributes:   listing.Attributes,
+				Images:       nil,
+			},
+			ImageURLs: urls,
+		})
+	}
+
+	return rows, nil
+}
+
+// runImport creates listings from the rows of the import and records the outcome in the job.
+func (s *ListingsService) runImport(ctx context.Context, job *dto.ImportJob) {
+	job.Status = dto.ImportStatusFailed
+
+	rows, err := imports.Parse(job.Format, bytes.NewReader(job.Source))
+	if err != nil {
+		job.Error = errorMessage(err)
+
+		return
+	}
+
+	var images *imports.Images
+
+	if job.Images != nil {
+		images, err = imports.OpenImages(job.Images, s.cfg.MaxImageFileSize)
+		if err != nil {
+			job.Error = errorMessage(err)
+
+			return
+		}
+	}
+
+	user := &authDto.UserClaims{ID: job.UserID, Role: authDto.UserRoleCustomer}
+
+	// rows recorded by a worker which stopped half way are not imported again
+	done := min(len(job.Results), len(rows))
+	job.Results = job.Results[:done]
+
+	for _, row := range rows[done:] {
+		if ctx.Err() != nil {
+			job.Error = errorMessage(ctx.Err())
+
+			return
+		}
+
+		result := s.importRow(ctx, job.ID, user, &row, images)
+		if result.ListingID != nil {
+			job.CreatedCount++
+		}
+
+		if len(result.Errors) > 0 {
+			job.FailedCount++
+		}
+
+		job.Results = append(job.Results, result)
+
+		err = s.repo.SaveImportProgress(context.WithoutCancel(ctx), job)
+		if err != nil {
+			job.Error = errorMessage(err)
+
+			return
+		}
+	}
+
+	job.Status = dto.ImportStatusCompleted
+}
+
+// importRow creates the listing of one import row. A listing whose images fail is kept,
+// the row reports both its id and the errors. A row which already created a listing before
+// the import was resumed reports that listing.
+func (s *ListingsService) importRow(
+	ctx context.Context,
+	importID string,
+	user *authDto.UserClaims,
+	row *imports.Row,
+	images *imports.Images,
+) dto.ImportRowResult {
+	result := dto.ImportRowResult{Row: row.Line, ListingID: nil, Errors: nil}
+
+	if row.Err != nil {
+		result.Errors = []string{row.Err.Error()}
+
+		return result
+	}
+
+	listingID, err := s.repo.GetImportedListingID(ctx, importID, row.Line)
+	if err == nil {
+		result.ListingID = &listingID
+
+		return result
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		result.Errors = []string{err.Error()}
+
+		return result
+	}
+
+	listing := &dto.Listing{ //nolint:exhaustruct
+		CategoryID:   row.Listing.CategoryID,
+		Title:        row.Listing.Title,
+		Description:  row.Listing.Description,
+		PriceInCents: row.Listing.PriceInCents,
+		Currency:     row.Listing.Currency,
+		Attributes:   row.Listing.Attributes,
+		ImportID:     &importID,
+		ImportRow:    &row.Line,
+	}
+
+	err = validation.ValidateStruct(listing)
+	if err != nil {
+		result.Errors = validation.FieldErrors(err)
+
+		return result
+	}
+
+	uploads, err := rowImages(row, images, s.cfg.MaxImagesPerListing)
+	if err != nil {
+		result.Errors = []string{err.Error()}
+
+		return result
+	}
+
+	created, err := s.CreateListing(ctx, user, listing)
+	if err != nil {
+		result.Errors = []string{err.Error()}
+
+		return result
+	}
+
+	result.ListingID = &created.ID
+
+	if len(uploads) > 0 {
+		_, err = s.storeListingImages(ctx, created.ID, uploads)
+		if err != nil {
+			result.Errors = []string{err.Error()}
+		}
+	}
+
+	return result
+}
+
+// rowImages looks up images of the row in the archive uploaded with the import.
+func rowImages(
+	row *imports.Row,
+	images *imports.Images,
+	maxImages int,
+) ([]storage.Upload, error) {
+	if len(row.Listing.Images) > maxImages {
+		return nil, ErrTooManyImages
+	}
+
+	uploads := make([]storage.Upload, 0, len(row.Listing.Images))
+
+	for _, name := range row.Listing.Images {
+		if images == nil {
+			return nil, fmt.Errorf("%w: %s", imports.ErrImageNotFound, name)
+		}
+
+		upload, err := images.Upload(name)
+		if err != nil {
+			return nil, fmt.Errorf("looking up image: %w", err)
+		}
+
+		uploads = append(uploads, upload)
+	}
+
+	return uploads, nil
+}
+
+func readUpload(fh *multipart.FileHeader) ([]byte, error) {
+	file, err := fh.Open()
+	if err != nil {
+		return nil, fmt.Errorf("opening uploaded file: %w", err)
+	}
+	defer file.Close() //nolint:errcheck
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading uploaded file: %w", err)
+	}
+
+	return data, nil
+}
+
+func errorMessage(err error) *string {
+	msg := err.Error()
+
+	return &msg
+}
@@ -2,14 +2,28 @@
 package services
 
 import (
+	"cmp"
 	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"golang-connect-marketplace/config"
 	authDto "golang-connect-marketplace/internal/auth/dto"
 	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/internal/marketplace/notifications"
+	"golang-connect-marketplace/internal/marketplace/paymentproviders"
 	"golang-connect-marketplace/internal/marketplace/repos"
+	"golang-connect-marketplace/internal/marketplace/screening"
 	"golang-connect-marketplace/internal/marketplace/storage"
+	"golang-connect-marketplace/pkg/cache"
+	"slices"
+	"time"
+)
+
+const (
+	hoursInDay                   = 24
+	defaultPriceHistogramBuckets = 10
 )
 
 var (
@@ -23,69 +37,146 @@ var (
 	ErrListingIsNotOpen = errors.New("listing is not open")
 	// ErrUserIsNotSeller is returned user doesn't have seller account linked.
 	ErrUserIsNotSeller = errors.New("user doesn't have seller account linked")
+	// ErrImageOrderMismatch is returned when reordered image ids don't match listing images.
+	ErrImageOrderMismatch = errors.New("image ids must contain every listing image exactly once")
+	// ErrListingIsExpired is returned when trying to pay for a listing that has expired.
+	ErrListingIsExpired = errors.New("listing has expired")
+	// ErrListingCantBeRenewed is returned when renewing a listing that is neither open nor expired.
+	ErrListingCantBeRenewed = errors.New("only open or expired listings can be renewed")
+	// ErrCategoryNotFound is returned when category doesn't exist or was deleted.
+	ErrCategoryNotFound = errors.New("category not found")
+	// ErrInvalidCategoryParent is returned when parent category doesn't exist or creates a cycle.
+	ErrInvalidCategoryParent = errors.New("invalid parent category")
+	// ErrCategoryTooDeep is returned when category tree would exceed configured max depth.
+	ErrCategoryTooDeep = errors.New("category tree is too deep")
+	// ErrCategoryHasChildren is returned when deleting a category that still has subcategories.
+	ErrCategoryHasChildren = errors.New("category has subcategories")
+	// ErrCategoryConflict is returned when an active category already has the slug, or the
+	// title under the same parent.
+	ErrCategoryConflict = errors.New("category with this slug or title already exists")
+	// ErrEmptyCategorySlug is returned when the given slug, or the title it is derived from,
+	// has no letters or digits.
+	ErrEmptyCategorySlug = errors.New("category slug must contain letters or digits")
+	// ErrInvalidAttributeSchema is returned when category attribute definitions are invalid.
+	ErrInvalidAttributeSchema = errors.New("invalid category attribute schema")
+	// ErrInvalidAttributes is returned when listing attribute values don't match category schema.
+	ErrInvalidAttributes = errors.New("invalid listing attributes")
+	// ErrSavedSearchNotFound is returned when saved search doesn't exist or belongs to other user.
+	ErrSavedSearchNotFound = errors.New("saved search not found")
+	// ErrTooManySavedSearches is returned when user already has too many saved searches.
+	ErrTooManySavedSearches = errors.New("user has too many saved searches")
+	// ErrEmptySavedSearch is returned when saving a search without any filters.
+	ErrEmptySavedSearch = errors.New("saved search must have at least one filter")
+	// ErrListingNotFound is returned when listing doesn't exist or is not visible to the user.
+	ErrListingNotFound = errors.New("listing not found")
+	// ErrUserSuspended is returned when a suspended seller tries to create a listing.
+	ErrUserSuspended = errors.New("user is suspended")
+	// ErrListingRejected is returned when automated screening blocks a listing.
+	ErrListingRejected = errors.New("listing was rejected")
 )
 
 // ListingsService provides listing related operations bussines logic.
 type ListingsService struct {
-	repo    repos.ListingsRepo
-	storage storage.Storage
-	cfg     *config.StorageConfig
+	repo          repos.ListingsRepo
+	storage       storage.Storage
+	notifier      notifications.Notifier
+	screener      screening.ListingScreener
+	provider      paymentproviders.PaymentProvider
+	cfg           *config.StorageConfig
+	listingsCfg   *config.ListingsConfig
+	searchCache   *cache.Cache[string, *dto.GetListingsResponse]
+	currencyCache *cache.Cache[string, []string]
 }
 
 // NewListingsService creates a new Service instance.
 func NewListingsService(
 	repo repos.ListingsRepo,
 	storage storage.Storage,
+	notifier notifications.Notifier,
+	screener screening.ListingScreener,
+	provider paymentproviders.PaymentProvider,
 	cfg *config.StorageConfig,
+	listingsCfg *config.ListingsConfig,
 ) *ListingsService {
+	var searchCache *cache.Cache[string, *dto.GetListingsResponse]
+	if listingsCfg.SearchCacheTTL > 0 && listingsCfg.SearchCacheSize > 0 {
+		searchCache = cache.New[string, *dto.GetListingsResponse](
+			listingsCfg.SearchCacheTTL,
+			listingsCfg.SearchCacheSize,
+		)
+	}
+
 	return &ListingsService{
-		repo:    repo,
-		storage: storage,
-		cfg:     cfg,
+		repo:          repo,
+		storage:       storage,
+		notifier:      notifier,
+		screener:      screener,
+		provider:      provider,
+		cfg:           cfg,
+		listingsCfg:   listingsCfg,
+		searchCache:   searchCache,
+		currencyCache: cache.New[string, []string](sellerCurrenciesTTL, sellerCurrenciesSize),
 	}
 }
 
-// CreateCategory handles logic for creating new category.
-func (s *ListingsService) CreateCategory(
+// CreateListing handles logic for creating a new listing. Listings flagged by automated
+// screening wait in the moderation queue.
+func (s *ListingsService) CreateListing(
 	ctx context.Context,
-	req *dto.Category,
-) (*dto.Category, error) {
-	path, err := s.storage.StoreImage(ctx, req.FileHeader, "categories")
+	userClaims *authDto.UserClaims,
+	req *dto.Listing,
+) (*dto.Listing, error) {
+	req.UserID = userClaims.ID
+
+	err := s.checkCategory(ctx, req.CategoryID)
 	if err != nil {
-		return nil, fmt.Errorf("storing category image: %w", err)
+		return nil, err
 	}
 
-	req.ImagePath = path
+	expiresAt, err := s.expiryDate(ctx, req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	req.ExpiresAt = &expiresAt
 
-	resp, err := s.repo.CreateCategory(ctx, req)
+	err = s.checkPrice(ctx, req)
 	if err != nil {
-		_ = s.storage.DeleteImage(ctx, path)
+		return nil, err
+	}
 
-		return nil, fmt.Errorf("creating category: %w", err)
+	err = s.checkShippingProfile(ctx, req.ShippingProfileID, req.UserID, req.Currency)
+	if err != nil {
+		return nil, err
 	}
 
-	return resp, nil
-}
+	req.Latitude = coarsenCoordinate(req.Latitude)
+	req.Longitude = coarsenCoordinate(req.Longitude)
 
-// GetCategories handles logic for fetching categories list.
-func (s *ListingsService) GetCategories(ctx context.Context) ([]dto.Category, error) {
-	resp, err := s.repo.GetCategories(ctx)
+	req.Status, err = s.initialListingStatus(ctx, req.UserID)
 	if err != nil {
-		return nil, fmt.Errorf("fetching categories: %w", err)
+		return nil, err
 	}
 
-	return resp, nil
-}
+	if req.Attributes == nil {
+		req.Attributes = dto.ListingAttributes{}
+	}
 
-// CreateListing handles logic for creating a new listing.
-func (s *ListingsService) CreateListing(
-	ctx context.Context,
-	userClaims *authDto.UserClaims,
-	req *dto.Listing,
-) (*dto.Listing, error) {
-	req.UserID = userClaims.ID
+	err = s.validateListingAttributes(ctx, req.CategoryID, req.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	findings, err := s.screenListing(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(findings) > 0 {
+		req.Status = dto.ListingStatusPendingReview
+	}
 
-	resp, err := s.repo.CreateListing(ctx, req)
+	resp, err := s.repo.CreateListing(ctx, req, findings)
 	if err != nil {
 		return nil, fmt.Errorf("creating new listing: %w", err)
 	}
@@ -111,18 +202,21 @@ func (s *ListingsService) AddImages(
 		return nil, ErrTooManyImages
 	}
 
-	for _, fh := range req.FileHeaders {
-		path, err := s.storage.StoreImage(ctx, &fh, req.ListingID)
-		if err != nil {
-			return nil, fmt.Errorf("storing image: %w", err)
-		}
+	uploads := make([]storage.Upload, 0, len(req.FileHeaders))
+	for i := range req.FileHeaders {
+		uploads = append(uploads, &req.FileHeaders[i])
+	}
 
-		listingImage, err := s.repo.AddListingImage(ctx, req.ListingID, path)
-		if err != nil {
-			return nil, fmt.Errorf("inserting image: %w", err)
-		}
+	inserted, err := s.storeListingImages(ctx, req.ListingID, uploads)
+	if err != nil {
+		return nil, err
+	}
+
+	listing.Images = append(listing.Images, inserted...)
 
-		listing.Images = append(listing.Images, *listingImage)
+	err = s.withImageURLs(ctx, listing)
+	if err != nil {
+		return nil, err
 	}
 
 	return listing, nil
@@ -146,48 +240,131 @@ func (s *ListingsService) DeleteImage(
 		return nil, ErrForbidden
 	}
 
-	path := ""
+	var deleted *dto.ListingImage
 
 	for i, img := range listing.Images {
 		if img.ID == req.ImageID {
 			listing.Images = append(listing.Images[:i], listing.Images[i+1:]...)
-			path = img.Path
+			deleted = &img
 
 			break
 		}
 	}
 
-	if path == "" {
+	if deleted == nil {
 		return nil, ErrImageDoesntExist
 	}
 
-	err = s.storage.DeleteImage(ctx, path)
+	err = s.repo.DeleteListingImage(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("deleting image from storage: %w", err)
+		return nil, fmt.Errorf("deleting image from database: %w", err)
 	}
 
-	err = s.repo.DeleteListingImage(ctx, req)
+	// files left behind when this fails are removed by CollectOrphanedImages.
+	s.deleteStoredImage(ctx, deleted.Path, deleted.Variants)
+
+	err = s.withImageURLs(ctx, listing)
 	if err != nil {
-		return nil, fmt.Errorf("deleting image from database: %w", err)
+		return nil, err
 	}
 
 	return listing, nil
 }
 
-// GetListingByID handles logic for fetching listing by id.
+// ReorderImages handles logic for changing the order and primary image of listing images.
+func (s *ListingsService) ReorderImages(
+	ctx context.Context,
+	req *dto.ReorderImagesRequest,
+) (*dto.Listing, error) {
+	listing, err := s.ownedListing(ctx, req.ListingID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.ImageIDs) != len(listing.Images) {
+		return nil, ErrImageOrderMismatch
+	}
+
+	for _, img := range listing.Images {
+		if !slices.Contains(req.ImageIDs, img.ID) {
+			return nil, ErrImageOrderMismatch
+		}
+	}
+
+	if req.PrimaryImageID == "" {
+		req.PrimaryImageID = req.ImageIDs[0]
+	}
+
+	if !slices.Contains(req.ImageIDs, req.PrimaryImageID) {
+		return nil, ErrImageDoesntExist
+	}
+
+	err = s.repo.ReorderListingImages(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("reordering images: %w", err)
+	}
+
+	return s.GetListingByID(ctx, req.ListingID, req.UserID)
+}
+
+// UpdateImage handles logic for updating alt text and primary flag of a listing image.
+func (s *ListingsService) UpdateImage(
+	ctx context.Context,
+	req *dto.UpdateImageRequest,
+) (*dto.Listing, error) {
+	listing, err := s.ownedListing(ctx, req.ListingID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	exists := slices.ContainsFunc(listing.Images, func(img dto.ListingImage) bool {
+		return img.ID == req.ImageID
+	})
+	if !exists {
+		return nil, ErrImageDoesntExist
+	}
+
+	err = s.repo.UpdateListingImage(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("updating image: %w", err)
+	}
+
+	return s.GetListingByID(ctx, req.ListingID, req.UserID)
+}
+
+// GetListingByID handles logic for fetching listing by id. UserID of the caller is used
+// to mark favorited listing and is empty for anonymous requests.
 func (s *ListingsService) GetListingByID(
 	ctx context.Context,
-	listingID string,
+	listingID, userID string,
 ) (*dto.Listing, error) {
 	listing, err := s.repo.GetListingByID(ctx, listingID)
 	if err != nil {
 		return nil, fmt.Errorf("fetching listing: %w", err)
 	}
 
-	return listing, nil
+	if listing.Status.IsModerated() && listing.UserID != userID {
+		return nil, ErrListingNotFound
+	}
+
+	err = s.withImageURLs(ctx, listing)
+	if err != nil {
+		return nil, err
+	}
+
+	listings := []dto.Listing{*listing}
+
+	err = s.withFavorites(ctx, userID, listings)
+	if err != nil {
+		return nil, err
+	}
+
+	return &listings[0], nil
 }
 
-// UpdateListing handles logic for updating a listing.
+// UpdateListing handles logic for updating a listing. Status changes must be allowed by
+// listingTransitions. Changes made by the owner are screened again and flagged listings go
+// back to the moderation queue.
 func (s *ListingsService) UpdateListing(
 	ctx context.Context,
 	req *dto.UpdateListingRequest,
@@ -202,19 +379,123 @@ func (s *ListingsService) UpdateListing(
 		return nil, ErrForbidden
 	}
 
-	if listing.Status != dto.ListingStatusOpen && user.Role != authDto.UserRoleAdmin {
+	actor := actorOwner
+	if user.Role == authDto.UserRoleAdmin {
+		actor = actorAdmin
+	}
+
+	statusChanged := req.Status != nil && *req.Status != listing.Status
+	if statusChanged {
+		err = checkStatusTransition(listing.Status, *req.Status, actor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// owners can edit listings which are not open only when reopening them
+	if listing.Status != dto.ListingStatusOpen && actor == actorOwner && !statusChanged {
 		return nil, ErrListingIsNotOpen
 	}
 
-	updatedListing, err := s.repo.UpdateListing(ctx, req)
+	if req.PriceInCents != 0 || req.Currency != "" {
+		updated := updatedContent(listing, req)
+
+		err = s.checkPrice(ctx, updated)
+		if err != nil {
+			return nil, err
+		}
+
+		if req.Currency != "" {
+			req.Currency = updated.Currency
+
+			err = s.checkShippingProfile(
+				ctx,
+				listing.ShippingProfileID,
+				listing.UserID,
+				req.Currency,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	categoryChanged := req.CategoryID != "" && req.CategoryID != listing.CategoryID
+	if categoryChanged {
+		err = s.checkCategory(ctx, req.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Attributes != nil || categoryChanged {
+		categoryID := cmp.Or(req.CategoryID, listing.CategoryID)
+
+		attributes := req.Attributes
+		if attributes == nil {
+			attributes = listing.Attributes
+		}
+
+		err = s.validateListingAttributes(ctx, categoryID, attributes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// reopened listings get a new expiry date, otherwise an expired listing would stay
+	// hidden from buyers and expire again right away
+	if statusChanged && *req.Status == dto.ListingStatusOpen {
+		expiresAt, err := s.expiryDate(ctx, cmp.Or(req.CategoryID, listing.CategoryID))
+		if err != nil {
+			return nil, err
+		}
+
+		req.ExpiresAt = &expiresAt
+	}
+
+	var findings []dto.ScreeningFinding
+
+	if actor == actorOwner {
+		findings, err = s.screenListing(ctx, updatedContent(listing, req))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(findings) > 0 && (req.Status == nil || *req.Status == dto.ListingStatusOpen) {
+		err = checkStatusTransition(listing.Status, dto.ListingStatusPendingReview, actorScreening)
+		if err != nil {
+			return nil, err
+		}
+
+		status := dto.ListingStatusPendingReview
+		req.Status = &status
+	}
+
+	req.ActorID = user.ID
+	req.ActorRole = dto.RevisionActorOwner
+
+	if actor == actorAdmin {
+		req.ActorRole = dto.RevisionActorAdmin
+	}
+
+	updatedListing, err := s.repo.UpdateListing(ctx, req, findings)
 	if err != nil {
 		return nil, fmt.Errorf("updating listing: %w", err)
 	}
 
+	s.notifyPriceDrop(ctx, listing, updatedListing)
+
+	err = s.withImageURLs(ctx, updatedListing)
+	if err != nil {
+		return nil, err
+	}
+
 	return updatedListing, nil
 }
 
-// GetListings handles logic for fetching a list of listing.
+// GetListings handles logic for fetching a list of listing. Anonymous requests are served
+// from search cache when it's enabled.
 func (s *ListingsService) GetListings(
 	ctx context.Context,
 	req *dto.GetListingsRequest,
@@ -227,11 +508,44 @@ func (s *ListingsService) GetListings(
 		req.Page = 0
 	}
 
+	cacheKey, cacheable := s.searchCacheKey(req)
+	if cacheable {
+		if cached, ok := s.searchCache.Get(cacheKey); ok {
+			return cached, nil
+		}
+	}
+
 	listings, err := s.repo.GetListings(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("fetching listings: %w", err)
 	}
 
+	for i := range listings {
+		err = s.withImageURLs(ctx, &listings[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.withFavorites(ctx, req.UserID, listings)
+	if err != nil {
+		return nil, err
+	}
+
+	var facets *dto.ListingFacets
+
+	if req.Facets {
+		buckets := s.listingsCfg.PriceHistogramBuckets
+		if buckets <= 0 {
+			buckets = defaultPriceHistogramBuckets
+		}
+
+		facets, err = s.repo.GetListingFacets(ctx, req, buckets)
+		if err != nil {
+			return nil, fmt.Errorf("fetching listing facets: %w", err)
+		}
+	}
+
 	resp := &dto.GetListingsResponse{
 		Meta: dto.PaginationMeta{
 			Limit:    req.Limit,
@@ -240,8 +554,365 @@ func (s *ListingsService) GetListings(
 			Keyword:  req.Keyword,
 			Total:    len(listings),
 		},
+		Facets:   facets,
 		Listings: listings,
 	}
 
+	if cacheable {
+		s.searchCache.Set(cacheKey, resp)
+	}
+
 	return resp, nil
 }
+
+// searchCacheKey returns cache key of an anonymous listings request. Cached responses
+// contain signed image urls, so cache ttl should be much shorter than image url ttl.
+func (s *ListingsService) searchCacheKey(req *dto.GetListingsRequest) (string, bool) {
+	if s.searchCache == nil || req.UserID != "" {
+		return "", false
+	}
+
+	key, err := json.Marshal(req)
+	if err != nil {
+		return "", false
+	}
+
+	return string(key), true
+}
+
+// RenewListing handles logic for extending the expiry date of an open or expired listing.
+func (s *ListingsService) RenewListing(
+	ctx context.Context,
+	req *dto.RenewListingRequest,
+) (*dto.Listing, error) {
+	listing, err := s.repo.GetListingByID(ctx, req.ListingID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching listing: %w", err)
+	}
+
+	if listing.UserID != req.UserID {
+		return nil, ErrForbidden
+	}
+
+	if listing.Status != dto.ListingStatusOpen && listing.Status != dto.ListingStatusExpired {
+		return nil, ErrListingCantBeRenewed
+	}
+
+	expiresAt, err := s.expiryDate(ctx, listing.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	renewedListing, err := s.repo.RenewListing(ctx, req.ListingID, expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("renewing listing: %w", err)
+	}
+
+	err = s.withImageURLs(ctx, renewedListing)
+	if err != nil {
+		return nil, err
+	}
+
+	return renewedListing, nil
+}
+
+// ExpireListings moves open listings past their expiry date to expired status and notifies sellers.
+func (s *ListingsService) ExpireListings(ctx context.Context) error {
+	notices, err := s.repo.ExpireListings(
+		ctx,
+		sourceStatuses(dto.ListingStatusExpired, actorExpiry),
+	)
+	if err != nil {
+		return fmt.Errorf("expiring listings: %w", err)
+	}
+
+	for _, notice := range notices {
+		s.notify(ctx, &dto.Notification{
+			UserID: notice.UserID,
+			Email:  notice.SellerEmail,
+			Type:   dto.NotificationTypeListingExpired,
+			Title:  "Your listing has expired",
+			Body: fmt.Sprintf(
+				"Your listing %q has expired. Renew it to make it visible to buyers again.",
+				notice.Title,
+			),
+			Data: map[string]string{"listing_id": notice.ListingID},
+		})
+	}
+
+	return nil
+}
+
+// NotifyExpiringListings notifies sellers whose open listings expire soon.
+func (s *ListingsService) NotifyExpiringListings(ctx context.Context) error {
+	before := time.Now().Add(s.listingsCfg.ExpiryNotifyBefore)
+
+	notices, err := s.repo.MarkListingsExpiringBefore(ctx, before)
+	if err != nil {
+		return fmt.Errorf("fetching listings expiring soon: %w", err)
+	}
+
+	for _, notice := range notices {
+		s.notify(ctx, &dto.Notification{
+			UserID: notice.UserID,
+			Email:  notice.SellerEmail,
+			Type:   dto.NotificationTypeListingExpiringSoon,
+			Title:  "Your listing expires soon",
+			Body: fmt.Sprintf(
+				"Your listing %q expires on %s. Renew it to keep it open.",
+				notice.Title,
+				notice.ExpiresAt.Format(time.RFC1123),
+			),
+			Data: map[string]string{"listing_id": notice.ListingID},
+		})
+	}
+
+	return nil
+}
+
+// ownedListing fetches an open listing and checks that it belongs to the user.
+func (s *ListingsService) ownedListing(
+	ctx context.Context,
+	listingID, userID string,
+) (*dto.Listing, error) {
+	listing, err := s.repo.GetListingByID(ctx, listingID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching listing: %w", err)
+	}
+
+	if listing.UserID != userID {
+		return nil, ErrForbidden
+	}
+
+	if listing.Status != dto.ListingStatusOpen {
+		return nil, ErrListingIsNotOpen
+	}
+
+	return listing, nil
+}
+
+// initialListingStatus returns the status of a new listing of the seller. Suspended sellers
+// can't create listings and new sellers may need approval when it's enabled.
+func (s *ListingsService) initialListingStatus(
+	ctx context.Context,
+	userID string,
+) (dto.ListingStatus, error) {
+	standing, err := s.repo.GetSellerStanding(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("fetching seller standing: %w", err)
+	}
+
+	if standing.Suspended {
+		return "", ErrUserSuspended
+	}
+
+	if s.listingsCfg.ReviewNewSellers && standing.SalesCount < s.listingsCfg.TrustedSellerMinSales {
+		return dto.ListingStatusPendingReview, nil
+	}
+
+	return dto.ListingStatusOpen, nil
+}
+
+// screenListing runs automated screening of the listing, returning ErrListingRejected when
+// any finding blocks it.
+func (s *ListingsService) screenListing(
+	ctx context.Context,
+	listing *dto.Listing,
+) ([]dto.ScreeningFinding, error) {
+	findings, err := s.screener.Screen(ctx, listing)
+	if err != nil {
+		return nil, fmt.Errorf("screening listing: %w", err)
+	}
+
+	blocked := screening.Blocked(findings)
+	if blocked != "" {
+		return nil, fmt.Errorf("%w: %s", ErrListingRejected, blocked)
+	}
+
+	return findings, nil
+}
+
+// updatedContent returns the listing with the content changes of the update applied.
+func updatedContent(listing *dto.Listing, req *dto.UpdateListingRequest) *dto.Listing {
+	updated := *listing
+	updated.CategoryID = cmp.Or(req.CategoryID, listing.CategoryID)
+	updated.Title = cmp.Or(req.Title, listing.Title)
+	updated.Description = cmp.Or(req.Description, listing.Description)
+	updated.PriceInCents = cmp.Or(req.PriceInCents, listing.PriceInCents)
+	updated.Currency = cmp.Or(req.Currency, listing.Currency)
+
+	return &updated
+}
+
+// checkCategory checks that listings can be put in the category.
+func (s *ListingsService) checkCategory(ctx context.Context, categoryID string) error {
+	_, err := s.repo.GetCategoryByID(ctx, categoryID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCategoryNotFound
+		}
+
+		return fmt.Errorf("fetching listing category: %w", err)
+	}
+
+	return nil
+}
+
+// expiryDate returns when a listing in the category expires. Deleted categories still count,
+// so listings left in them can be renewed or reopened.
+func (s *ListingsService) expiryDate(ctx context.Context, categoryID string) (time.Time, error) {
+	duration, err := s.repo.GetCategoryListingDuration(ctx, categoryID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, fmt.Errorf("fetching listing category duration: %w", err)
+	}
+
+	days := s.listingsCfg.DefaultDurationDays
+	if duration != nil {
+		days = *duration
+	}
+
+	return time.Now().Add(time.Duration(days) * hoursInDay * time.Hour), nil
+}
+
+// CollectOrphanedImages deletes stored files which are not referenced by any listing image
+// or category. Files newer than the configured grace period are skipped, so that uploads
+// still in progress are not removed.
+func (s *ListingsService) CollectOrphanedImages(ctx context.Context) error {
+	keys, err := s.storage.ListImages(ctx, time.Now().Add(-s.cfg.OrphanGracePeriod))
+	if err != nil {
+		return fmt.Errorf("listing stored images: %w", err)
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	referenced, err := s.repo.GetReferencedImageKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching referenced images: %w", err)
+	}
+
+	inUse := make(map[string]struct{}, len(referenced))
+	for _, key := range referenced {
+		inUse[key] = struct{}{}
+	}
+
+	var errs []error
+
+	for _, key := range keys {
+		if _, ok := inUse[key]; ok {
+			continue
+		}
+
+		err = s.storage.DeleteImage(ctx, key)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("deleting orphaned image %s: %w", key, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// storeListingImages stores uploaded images and adds them to the listing. If any of them
+// fails, images stored so far are deleted.
+func (s *ListingsService) storeListingImages(
+	ctx context.Context,
+	listingID string,
+	uploads []storage.Upload,
+) ([]dto.ListingImage, error) {
+	stored := make([]*storage.StoredImage, 0, len(uploads))
+
+	for _, upload := range uploads {
+		img, err := s.storage.StoreImage(ctx, upload, listingID)
+		if err != nil {
+			s.deleteStoredImages(ctx, stored)
+
+			return nil, fmt.Errorf("storing image: %w", err)
+		}
+
+		stored = append(stored, img)
+	}
+
+	images := make([]dto.ListingImage, 0, len(stored))
+	for _, img := range stored {
+		images = append(images, dto.ListingImage{ //nolint:exhaustruct
+			Path:     img.Path,
+			Variants: img.Variants,
+		})
+	}
+
+	inserted, err := s.repo.AddListingImages(ctx, listingID, images)
+	if err != nil {
+		s.deleteStoredImages(ctx, stored)
+
+		return nil, fmt.Errorf("inserting images: %w", err)
+	}
+
+	return inserted, nil
+}
+
+func (s *ListingsService) deleteStoredImages(ctx context.Context, images []*storage.StoredImage) {
+	for _, img := range images {
+		s.deleteStoredImage(ctx, img.Path, img.Variants)
+	}
+}
+
+// deleteStoredImage removes an image and its variants from storage, ignoring failures.
+func (s *ListingsService) deleteStoredImage(
+	ctx context.Context,
+	path string,
+	variants dto.ImageVariants,
+) {
+	if path != "" {
+		_ = s.storage.DeleteImage(ctx, path)
+	}
+
+	for _, v := range variants {
+		_ = s.storage.DeleteImage(ctx, v.Path)
+	}
+}
+
+// withImageURLs replaces storage keys of listing images with short-lived URLs.
+func (s *ListingsService) withImageURLs(ctx context.Context, listing *dto.Listing) error {
+	for i := range listing.Images {
+		url, err := s.storage.URL(ctx, listing.Images[i].Path, s.cfg.URLTTL)
+		if err != nil {
+			return fmt.Errorf("creating image url: %w", err)
+		}
+
+		variants, err := s.variantsWithURLs(ctx, listing.Images[i].Variants)
+		if err != nil {
+			return err
+		}
+
+		listing.Images[i].URL = url
+		listing.Images[i].Variants = variants
+	}
+
+	listing.PrimaryImage = listing.Images.Primary()
+
+	return nil
+}
+
+func (s *ListingsService) variantsWithURLs(
+	ctx context.Context,
+	variants dto.ImageVariants,
+) (dto.ImageVariants, error) {
+	withURLs := make(dto.ImageVariants, len(variants))
+
+	for name, v := range variants {
+		url, err := s.storage.URL(ctx, v.Path, s.cfg.URLTTL)
+		if err != nil {
+			return nil, fmt.Errorf("creating %s image variant url: %w", name, err)
+		}
+
+		v.URL = url
+		withURLs[name] = v
+	}
+
+	return withURLs, nil
+}
+
+func (s *ListingsService) notify(ctx context.Context, n *dto.Notification) {
+	_ = s.notifier.Notify(ctx, n)
+}
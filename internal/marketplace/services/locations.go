@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"math"
+)
+
+// locationPrecision is the factor coordinates are rounded with, two decimal places place
+// listings within roughly a kilometer without revealing the seller's address.
+const locationPrecision = 100
+
+// SetListingLocation handles logic for setting or removing the pickup location of a listing.
+func (s *ListingsService) SetListingLocation(
+	ctx context.Context,
+	req *dto.SetListingLocationRequest,
+) (*dto.Listing, error) {
+	listing, err := s.repo.GetListingByID(ctx, req.ListingID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrListingNotFound
+		}
+
+		return nil, fmt.Errorf("fetching listing: %w", err)
+	}
+
+	if listing.UserID != req.UserID {
+		return nil, ErrForbidden
+	}
+
+	req.Latitude = coarsenCoordinate(req.Latitude)
+	req.Longitude = coarsenCoordinate(req.Longitude)
+
+	err = s.repo.SetListingLocation(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("setting listing location: %w", err)
+	}
+
+	listing, err = s.repo.GetListingByID(ctx, req.ListingID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching updated listing: %w", err)
+	}
+
+	err = s.withImageURLs(ctx, listing)
+	if err != nil {
+		return nil, err
+	}
+
+	return listing, nil
+}
+
+// coarsenCoordinate rounds the coordinate to locationPrecision, nil stays nil.
+func coarsenCoordinate(coordinate *float64) *float64 {
+	if coordinate == nil {
+		return nil
+	}
+
+	coarse := math.Round(*coordinate*locationPrecision) / locationPrecision
+
+	return &coarse
+}
@@ -0,0 +1,285 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	authDto "golang-connect-marketplace/internal/auth/dto"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/internal/marketplace/events"
+	"golang-connect-marketplace/internal/marketplace/notifications"
+	"golang-connect-marketplace/internal/marketplace/repos"
+	"time"
+)
+
+var (
+	// ErrThreadNotFound is returned when message thread doesn't exist.
+	ErrThreadNotFound = errors.New("thread not found")
+	// ErrCantMessageOwnListing is returned when seller tries to start a thread on own listing.
+	ErrCantMessageOwnListing = errors.New("can't message about your own listing")
+	// ErrUserBlocked is returned when one of thread participants blocked the other.
+	ErrUserBlocked = errors.New("messaging between these users is blocked")
+	// ErrMessageDoesntExist is returned when reported message doesn't belong to the thread.
+	ErrMessageDoesntExist = errors.New("message doesn't exist in this thread")
+)
+
+// MessagesService provides buyer-seller messaging bussines logic.
+type MessagesService struct {
+	repo         repos.MessagesRepo
+	listingsRepo repos.ListingsRepo
+	notifier     notifications.Notifier
+	publisher    events.Publisher
+}
+
+// NewMessagesService returns an instance of MessagesService.
+func NewMessagesService(
+	repo repos.MessagesRepo,
+	listingsRepo repos.ListingsRepo,
+	notifier notifications.Notifier,
+	publisher events.Publisher,
+) *MessagesService {
+	return &MessagesService{
+		repo:         repo,
+		listingsRepo: listingsRepo,
+		notifier:     notifier,
+		publisher:    publisher,
+	}
+}
+
+// SendMessage handles logic for sending a message. Messages sent to a listing start or
+// continue the thread between the sender as a buyer and the listing seller, messages sent
+// to a thread can only be sent by its participants.
+func (s *MessagesService) SendMessage(
+	ctx context.Context,
+	req *dto.SendMessageRequest,
+) (*dto.Message, error) {
+	threadID := req.ThreadID
+
+	if threadID == "" {
+		listing, err := s.listingsRepo.GetListingByID(ctx, req.ListingID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, ErrListingNotFound
+			}
+
+			return nil, fmt.Errorf("fetching listing: %w", err)
+		}
+
+		// moderated listings are hidden from buyers, a thread would reveal them
+		if listing.Status.IsModerated() {
+			return nil, ErrListingNotFound
+		}
+
+		if listing.UserID == req.SenderID {
+			return nil, ErrCantMessageOwnListing
+		}
+
+		// checked before the thread is created, so blocked users don't leave empty threads
+		blocked, err := s.repo.IsBlocked(ctx, req.SenderID, listing.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("checking blocked users: %w", err)
+		}
+
+		if blocked {
+			return nil, ErrUserBlocked
+		}
+
+		threadID, err = s.repo.GetOrCreateThread(ctx, listing.ID, req.SenderID, listing.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("creating thread: %w", err)
+		}
+	}
+
+	thread, err := s.thread(ctx, threadID, req.SenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !thread.HasParticipant(req.SenderID) {
+		return nil, ErrForbidden
+	}
+
+	recipient := thread.Buyer
+	if recipient.ID == req.SenderID {
+		recipient = thread.Seller
+	}
+
+	blocked, err := s.repo.IsBlocked(ctx, req.SenderID, recipient.ID)
+	if err != nil {
+		return nil, fmt.Errorf("checking blocked users: %w", err)
+	}
+
+	if blocked {
+		return nil, ErrUserBlocked
+	}
+
+	msg, err := s.repo.CreateMessage(ctx, &dto.Message{
+		ID:        "",
+		ThreadID:  thread.ID,
+		SenderID:  req.SenderID,
+		Body:      req.Body,
+		CreatedAt: time.Time{},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating message: %w", err)
+	}
+
+	s.notifyRecipient(ctx, thread, recipient, msg)
+
+	return msg, nil
+}
+
+// GetThreads handles logic for fetching threads of a user, most recently active first.
+func (s *MessagesService) GetThreads(ctx context.Context, userID string) ([]dto.Thread, error) {
+	threads, err := s.repo.GetThreads(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching threads: %w", err)
+	}
+
+	return threads, nil
+}
+
+// GetThread handles logic for fetching messages of a thread, newest first. Only thread
+// participants and admins can read a thread, reading marks it as read for participants.
+func (s *MessagesService) GetThread(
+	ctx context.Context,
+	req *dto.GetThreadRequest,
+	user *authDto.UserClaims,
+) (*dto.GetThreadResponse, error) {
+	if req.Limit <= 0 {
+		req.Limit = 50
+	}
+
+	thread, err := s.readableThread(ctx, req.ThreadID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := s.repo.GetMessages(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching messages: %w", err)
+	}
+
+	if thread.HasParticipant(user.ID) {
+		err = s.repo.MarkThreadRead(ctx, thread.ID, user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("marking thread as read: %w", err)
+		}
+
+		thread.UnreadCount = 0
+	}
+
+	return &dto.GetThreadResponse{Thread: thread, Messages: messages}, nil
+}
+
+// CountUnreadMessages handles logic for counting unread messages across threads of a user.
+func (s *MessagesService) CountUnreadMessages(
+	ctx context.Context,
+	userID string,
+) (*dto.UnreadMessagesResponse, error) {
+	count, err := s.repo.CountUnreadMessages(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("counting unread messages: %w", err)
+	}
+
+	return &dto.UnreadMessagesResponse{Unread: count}, nil
+}
+
+// BlockUser handles logic for blocking messages between two users.
+func (s *MessagesService) BlockUser(ctx context.Context, req *dto.BlockUserRequest) error {
+	err := s.repo.BlockUser(ctx, req)
+	if err != nil {
+		return fmt.Errorf("blocking user: %w", err)
+	}
+
+	return nil
+}
+
+// UnblockUser handles logic for removing a block set by the user.
+func (s *MessagesService) UnblockUser(ctx context.Context, req *dto.BlockUserRequest) error {
+	err := s.repo.UnblockUser(ctx, req)
+	if err != nil {
+		return fmt.Errorf("unblocking user: %w", err)
+	}
+
+	return nil
+}
+
+// ReportAbuse handles logic for reporting abusive messages in a thread the user can read.
+func (s *MessagesService) ReportAbuse(
+	ctx context.Context,
+	req *dto.ReportAbuseRequest,
+	user *authDto.UserClaims,
+) (*dto.AbuseReport, error) {
+	_, err := s.readableThread(ctx, req.ThreadID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.repo.CreateAbuseReport(ctx, req)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrMessageDoesntExist
+		}
+
+		return nil, fmt.Errorf("creating abuse report: %w", err)
+	}
+
+	return report, nil
+}
+
+// readableThread fetches a thread and checks that user is its participant or an admin.
+func (s *MessagesService) readableThread(
+	ctx context.Context,
+	threadID string,
+	user *authDto.UserClaims,
+) (*dto.Thread, error) {
+	thread, err := s.thread(ctx, threadID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !thread.HasParticipant(user.ID) && user.Role != authDto.UserRoleAdmin {
+		return nil, ErrForbidden
+	}
+
+	return thread, nil
+}
+
+func (s *MessagesService) thread(
+	ctx context.Context,
+	threadID, userID string,
+) (*dto.Thread, error) {
+	thread, err := s.repo.GetThreadByID(ctx, threadID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrThreadNotFound
+		}
+
+		return nil, fmt.Errorf("fetching thread: %w", err)
+	}
+
+	return thread, nil
+}
+
+// notifyRecipient tells the other participant about a new message.
+func (s *MessagesService) notifyRecipient(
+	ctx context.Context,
+	thread *dto.Thread,
+	recipient dto.Participant,
+	msg *dto.Message,
+) {
+	_ = s.publisher.Publish(ctx, events.NewEvent(recipient.ID, dto.EventTypeNewMessage,
+		map[string]string{"thread_id": thread.ID, "message_id": msg.ID},
+	))
+
+	_ = s.notifier.Notify(ctx, &dto.Notification{
+		UserID: recipient.ID,
+		Email:  recipient.Email,
+		Type:   dto.NotificationTypeNewMessage,
+		Title:  "You have a new message",
+		Body:   fmt.Sprintf("You have a new message about %q.", thread.ListingTitle),
+		Data:   map[string]string{"thread_id": thread.ID, "listing_id": thread.ListingID},
+	})
+}
@@ -0,0 +1,322 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/internal/marketplace/notifications"
+	"golang-connect-marketplace/internal/marketplace/repos"
+	"time"
+)
+
+var (
+	// ErrCantReportYourself is returned when users report themselves or their own listings.
+	ErrCantReportYourself = errors.New("can't report yourself or your own listing")
+	// ErrUserNotFound is returned when reported user doesn't exist.
+	ErrUserNotFound = errors.New("user not found")
+	// ErrReportNotFound is returned when report doesn't exist.
+	ErrReportNotFound = errors.New("report not found")
+	// ErrReportClosed is returned when acting on a report which is no longer open.
+	ErrReportClosed = errors.New("report is already closed")
+	// ErrInvalidModerationAction is returned when action is missing its target or
+	// doesn't apply to the target in its current state.
+	ErrInvalidModerationAction = errors.New("invalid moderation action")
+)
+
+// ModerationService provides reports and moderation bussines logic.
+type ModerationService struct {
+	repo         repos.ModerationRepo
+	listingsRepo repos.ListingsRepo
+	listingsSvc  *ListingsService
+	notifier     notifications.Notifier
+}
+
+// NewModerationService returns an instance of ModerationService.
+func NewModerationService(
+	repo repos.ModerationRepo,
+	listingsRepo repos.ListingsRepo,
+	listingsSvc *ListingsService,
+	notifier notifications.Notifier,
+) *ModerationService {
+	return &ModerationService{
+		repo:         repo,
+		listingsRepo: listingsRepo,
+		listingsSvc:  listingsSvc,
+		notifier:     notifier,
+	}
+}
+
+// ReportListing handles logic for reporting a listing, the report is filed against
+// the listing owner as well.
+func (s *ModerationService) ReportListing(
+	ctx context.Context,
+	req *dto.CreateReportRequest,
+) (*dto.Report, error) {
+	listing, err := s.listingsRepo.GetListingByID(ctx, *req.ListingID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrListingNotFound
+		}
+
+		return nil, fmt.Errorf("fetching listing: %w", err)
+	}
+
+	if listing.UserID == req.ReporterID {
+		return nil, ErrCantReportYourself
+	}
+
+	req.TargetType = dto.ReportTargetListing
+	req.UserID = listing.UserID
+
+	return s.createReport(ctx, req)
+}
+
+// ReportUser handles logic for reporting a user.
+func (s *ModerationService) ReportUser(
+	ctx context.Context,
+	req *dto.CreateReportRequest,
+) (*dto.Report, error) {
+	req.TargetType = dto.ReportTargetUser
+	req.ListingID = nil
+
+	return s.createReport(ctx, req)
+}
+
+// GetReports handles logic for fetching the moderation queue, oldest reports first.
+func (s *ModerationService) GetReports(
+	ctx context.Context,
+	req *dto.GetReportsRequest,
+) ([]dto.Report, error) {
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+
+	reports, err := s.repo.GetReports(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching reports: %w", err)
+	}
+
+	return reports, nil
+}
+
+// GetPendingListings handles logic for fetching listings waiting for approval, oldest first.
+func (s *ModerationService) GetPendingListings(
+	ctx context.Context,
+	req *dto.GetPendingListingsRequest,
+) ([]dto.Listing, error) {
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+
+	listings, err := s.repo.GetListingsByStatus(
+		ctx,
+		dto.ListingStatusPendingReview,
+		req.Limit,
+		req.Page,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("fetching pending listings: %w", err)
+	}
+
+	for i := range listings {
+		err = s.listingsSvc.withImageURLs(ctx, &listings[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return listings, nil
+}
+
+// ApplyAction handles logic for a moderator acting on a report, a listing or a user.
+// The action is recorded in the audit trail and the affected user is notified.
+func (s *ModerationService) ApplyAction(
+	ctx context.Context,
+	req *dto.ModerationActionRequest,
+) (*dto.ModerationAction, error) {
+	action := &dto.ModerationAction{
+		ID:        "",
+		AdminID:   req.AdminID,
+		Action:    req.Action,
+		ReportID:  req.ReportID,
+		ListingID: req.ListingID,
+		UserID:    req.UserID,
+		UserEmail: nil,
+		Note:      req.Note,
+		CreatedAt: time.Time{},
+	}
+
+	err := s.resolveTargets(ctx, action)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.checkListingTransition(ctx, action)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded, err := s.repo.ApplyAction(ctx, action)
+	if err != nil {
+		if errors.Is(err, repos.ErrListingStatusConflict) {
+			return nil, fmt.Errorf("%w: %w", ErrIllegalStatusTransition, err)
+		}
+
+		return nil, fmt.Errorf("applying moderation action: %w", err)
+	}
+
+	s.notifyActionTarget(ctx, recorded)
+
+	return recorded, nil
+}
+
+// GetActions handles logic for fetching the moderation audit trail, newest first.
+func (s *ModerationService) GetActions(
+	ctx context.Context,
+	req *dto.GetModerationActionsRequest,
+) ([]dto.ModerationAction, error) {
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+
+	actions, err := s.repo.GetActions(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching moderation actions: %w", err)
+	}
+
+	return actions, nil
+}
+
+func (s *ModerationService) createReport(
+	ctx context.Context,
+	req *dto.CreateReportRequest,
+) (*dto.Report, error) {
+	report, err := s.repo.CreateReport(ctx, req)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+
+		return nil, fmt.Errorf("creating report: %w", err)
+	}
+
+	return report, nil
+}
+
+// resolveTargets fills targets missing from the action from its report and the listing,
+// and checks the action has the targets it needs.
+func (s *ModerationService) resolveTargets(
+	ctx context.Context,
+	action *dto.ModerationAction,
+) error {
+	if action.ReportID != nil {
+		report, err := s.repo.GetReportByID(ctx, *action.ReportID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrReportNotFound
+			}
+
+			return fmt.Errorf("fetching report: %w", err)
+		}
+
+		if report.Status != dto.ReportStatusOpen {
+			return ErrReportClosed
+		}
+
+		if action.ListingID == nil {
+			action.ListingID = report.ListingID
+		}
+
+		if action.UserID == nil {
+			action.UserID = &report.UserID
+		}
+	}
+
+	if action.ListingID != nil && action.UserID == nil {
+		listing, err := s.listingsRepo.GetListingByID(ctx, *action.ListingID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrListingNotFound
+			}
+
+			return fmt.Errorf("fetching listing: %w", err)
+		}
+
+		action.UserID = &listing.UserID
+	}
+
+	switch {
+	case action.Action == dto.ModerationActionDismissReport && action.ReportID == nil,
+		action.Action.IsListingAction() && action.ListingID == nil,
+		action.UserID == nil:
+		return ErrInvalidModerationAction
+	default:
+		return nil
+	}
+}
+
+// checkListingTransition checks the listing action is allowed for the current listing status.
+func (s *ModerationService) checkListingTransition(
+	ctx context.Context,
+	action *dto.ModerationAction,
+) error {
+	to, ok := moderationTargetStatus[action.Action]
+	if !ok {
+		return nil
+	}
+
+	listing, err := s.listingsRepo.GetListingByID(ctx, *action.ListingID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrListingNotFound
+		}
+
+		return fmt.Errorf("fetching listing: %w", err)
+	}
+
+	return checkStatusTransition(listing.Status, to, actorModeration)
+}
+
+// notifyActionTarget tells the affected user about the action, dismissals and lifted
+// suspensions are not announced.
+func (s *ModerationService) notifyActionTarget(ctx context.Context, action *dto.ModerationAction) {
+	n := &dto.Notification{
+		UserID: *action.UserID,
+		Email:  "",
+		Type:   "",
+		Title:  "",
+		Body:   action.Note,
+		Data:   map[string]string{"action": string(action.Action)},
+	}
+
+	if action.UserEmail != nil {
+		n.Email = *action.UserEmail
+	}
+
+	if action.ListingID != nil {
+		n.Data["listing_id"] = *action.ListingID
+	}
+
+	switch action.Action {
+	case dto.ModerationActionApproveListing:
+		n.Type = dto.NotificationTypeListingApproved
+		n.Title = "Your listing has been approved"
+	case dto.ModerationActionHideListing, dto.ModerationActionRemoveListing:
+		n.Type = dto.NotificationTypeListingModerated
+		n.Title = "Your listing has been taken down by a moderator"
+	case dto.ModerationActionWarnUser:
+		n.Type = dto.NotificationTypeModerationWarning
+		n.Title = "You have received a warning from a moderator"
+	case dto.ModerationActionSuspendUser:
+		n.Type = dto.NotificationTypeAccountSuspended
+		n.Title = "Your seller account has been suspended"
+	case dto.ModerationActionRestoreListing,
+		dto.ModerationActionUnsuspendUser,
+		dto.ModerationActionDismissReport:
+		return
+	}
+
+	_ = s.notifier.Notify(ctx, n)
+}
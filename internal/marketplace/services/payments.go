@@ -2,17 +2,37 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	authDto "golang-connect-marketplace/internal/auth/dto"
 	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/internal/marketplace/events"
+	"golang-connect-marketplace/internal/marketplace/notifications"
 	"golang-connect-marketplace/internal/marketplace/paymentproviders"
 	"golang-connect-marketplace/internal/marketplace/repos"
+	"golang-connect-marketplace/pkg/money"
 	"net/http"
+	"time"
 )
 
 const (
-	minimumFee     = 100
-	feePercent     = 4
-	percentDivisor = 100
+	feePercent = 4
+	// fixedFeeDivisor makes the fixed part of the fee a tenth of the currency minimum price,
+	// e.g. 1.00 USD.
+	fixedFeeDivisor = 10
+)
+
+var (
+	// ErrShippingOptionRequired is returned when buying a listing with shipping options
+	// without picking one.
+	ErrShippingOptionRequired = errors.New("shipping option is required")
+	// ErrShippingOptionNotFound is returned when picked shipping option isn't offered by
+	// the listing.
+	ErrShippingOptionNotFound = errors.New("shipping option not found")
+	// ErrOrderNotFound is returned when order doesn't exist or the user isn't its buyer
+	// or seller.
+	ErrOrderNotFound = errors.New("order not found")
 )
 
 // PaymentsService provides payments related operations bussines logic.
@@ -20,6 +40,8 @@ type PaymentsService struct {
 	provider     paymentproviders.PaymentProvider
 	paymentsRepo repos.PaymentsRepo
 	listingsRepo repos.ListingsRepo
+	notifier     notifications.Notifier
+	publisher    events.Publisher
 }
 
 // NewPaymentsService returns an instance of PaymentsService.
@@ -27,11 +49,15 @@ func NewPaymentsService(
 	provider paymentproviders.PaymentProvider,
 	paymentsRepo repos.PaymentsRepo,
 	listingsRepo repos.ListingsRepo,
+	notifier notifications.Notifier,
+	publisher events.Publisher,
 ) *PaymentsService {
 	return &PaymentsService{
 		provider:     provider,
 		paymentsRepo: paymentsRepo,
 		listingsRepo: listingsRepo,
+		notifier:     notifier,
+		publisher:    publisher,
 	}
 }
 
@@ -77,6 +103,11 @@ func (s *PaymentsService) CreateCheckoutSession(
 		return nil, fmt.Errorf("fetching listing while creating checkout session: %w", err)
 	}
 
+	if listing.Status == dto.ListingStatusExpired ||
+		(listing.ExpiresAt != nil && listing.ExpiresAt.Before(time.Now())) {
+		return nil, ErrListingIsExpired
+	}
+
 	if listing.Status != dto.ListingStatusOpen {
 		return nil, ErrListingIsNotOpen
 	}
@@ -85,9 +116,26 @@ func (s *PaymentsService) CreateCheckoutSession(
 		return nil, ErrUserIsNotSeller
 	}
 
-	fee := s.calculateFee(listing)
+	shipping, err := checkoutShipping(listing, req.ShippingOptionID)
+	if err != nil {
+		return nil, err
+	}
 
-	resp, err := s.provider.CreateCheckoutSession(ctx, req, listing, fee)
+	price, err := listing.Price()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnsupportedCurrency, err)
+	}
+
+	// listings created before the provider dropped a currency can't be charged anymore
+	if !s.provider.SupportsCurrency(price.Currency) {
+		return nil, fmt.Errorf(
+			"%w: payments in %s are not supported",
+			ErrUnsupportedCurrency,
+			price.Currency.Code,
+		)
+	}
+
+	resp, err := s.provider.CreateCheckoutSession(ctx, req, listing, shipping, calculateFee(price))
 	if err != nil {
 		return nil, fmt.Errorf("creating checkout session: %w", err)
 	}
@@ -106,12 +154,25 @@ func (s *PaymentsService) HandleSuccessWebhook(
 		return nil, fmt.Errorf("verifying payment success webhook: %w", err)
 	}
 
-	_, err = s.paymentsRepo.SavePayment(ctx, payment)
+	// the buyer has been charged already, so the payment is kept even when the listing
+	// can't be sold anymore and waits for a refund or an admin
+	err = s.checkPaymentTransition(ctx, payment.ListingID, dto.ListingStatusSold)
+	if errors.Is(err, ErrIllegalStatusTransition) {
+		payment.NeedsReview = true
+	} else if err != nil {
+		return nil, err
+	}
+
+	saved, created, err := s.paymentsRepo.SavePayment(ctx, payment)
 	if err != nil {
 		return nil, fmt.Errorf("saving payment: %w", err)
 	}
 
-	return payment, nil
+	if created && !saved.NeedsReview {
+		s.notifySold(ctx, saved)
+	}
+
+	return saved, nil
 }
 
 // HandleRefundWebhook handles bussines logic for payment refund webhook.
@@ -125,16 +186,146 @@ func (s *PaymentsService) HandleRefundWebhook(
 		return nil, fmt.Errorf("verifying payment refunded webhook: %w", err)
 	}
 
+	saved, err := s.paymentsRepo.GetPaymentByProviderID(
+		ctx,
+		payment.Provider,
+		payment.ProviderPaymentID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("fetching refunded payment: %w", err)
+	}
+
+	// flagged payments never sold the listing, so refunding them leaves the listing as is
+	if !saved.NeedsReview {
+		err = s.checkPaymentTransition(ctx, payment.ListingID, dto.ListingStatusRefunded)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	ref, err := s.paymentsRepo.RefundPayment(ctx, payment)
 	if err != nil {
 		return nil, fmt.Errorf("refunding payments: %w", err)
 	}
 
+	s.publishRefunded(ctx, ref)
+
 	return ref, nil
 }
 
-func (s *PaymentsService) calculateFee(listing *dto.Listing) int64 {
-	fee := listing.PriceInCents*feePercent/percentDivisor + minimumFee
+// GetOrder handles logic for fetching an order by its buyer or seller. The shipping address
+// is only shown to the seller, who has to deliver the item.
+func (s *PaymentsService) GetOrder(
+	ctx context.Context,
+	orderID string,
+	user *authDto.UserClaims,
+) (*dto.Order, error) {
+	order, err := s.paymentsRepo.GetOrder(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrOrderNotFound
+		}
+
+		return nil, fmt.Errorf("fetching order: %w", err)
+	}
+
+	if order.BuyerID != user.ID && order.SellerID != user.ID {
+		return nil, ErrOrderNotFound
+	}
+
+	if order.SellerID != user.ID {
+		order.ShippingAddress = nil
+	}
+
+	return order, nil
+}
+
+// GetSales handles logic for fetching orders of listings sold by the user, newest first.
+func (s *PaymentsService) GetSales(ctx context.Context, sellerID string) ([]dto.Order, error) {
+	orders, err := s.paymentsRepo.GetSales(ctx, sellerID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching sales: %w", err)
+	}
+
+	return orders, nil
+}
+
+// checkPaymentTransition checks that a payment event can move the paid listing
+// to the status.
+func (s *PaymentsService) checkPaymentTransition(
+	ctx context.Context,
+	listingID string,
+	to dto.ListingStatus,
+) error {
+	listing, err := s.listingsRepo.GetListingByID(ctx, listingID)
+	if err != nil {
+		return fmt.Errorf("fetching paid listing: %w", err)
+	}
+
+	return checkStatusTransition(listing.Status, to, actorPayment)
+}
+
+// checkoutShipping returns the shipping option picked by the buyer. Listings without
+// shipping options are sold without shipping.
+func checkoutShipping(listing *dto.Listing, optionID string) (*dto.ShippingOption, error) {
+	if len(listing.ShippingOptions) == 0 && optionID == "" {
+		return nil, nil //nolint:nilnil
+	}
+
+	if optionID == "" {
+		return nil, ErrShippingOptionRequired
+	}
+
+	option := listing.ShippingOptions.Find(optionID)
+	if option == nil {
+		return nil, ErrShippingOptionNotFound
+	}
+
+	return option, nil
+}
+
+// notifySold tells the seller that their listing has been sold and notifies watchers,
+// other than the buyer.
+func (s *PaymentsService) notifySold(ctx context.Context, payment *dto.Payment) {
+	listing, err := s.listingsRepo.GetListingByID(ctx, payment.ListingID)
+	if err != nil {
+		return
+	}
+
+	_ = s.publisher.Publish(ctx, events.NewEvent(listing.UserID, dto.EventTypeListingSold,
+		map[string]string{"listing_id": listing.ID, "payment_id": payment.ID},
+	))
+
+	_ = notifyWatchers(ctx, s.listingsRepo, s.notifier, listing.ID, payment.BuyerID,
+		func(w dto.Watcher) *dto.Notification {
+			return &dto.Notification{
+				UserID: w.UserID,
+				Email:  w.Email,
+				Type:   dto.NotificationTypeFavoriteSold,
+				Title:  "A listing you watch has been sold",
+				Body:   fmt.Sprintf("%q has been sold.", listing.Title),
+				Data:   map[string]string{"listing_id": listing.ID},
+			}
+		},
+	)
+}
+
+// publishRefunded tells the buyer and the seller that a payment has been refunded.
+func (s *PaymentsService) publishRefunded(ctx context.Context, payment *dto.Payment) {
+	listing, err := s.listingsRepo.GetListingByID(ctx, payment.ListingID)
+	if err != nil {
+		return
+	}
+
+	data := map[string]string{"listing_id": listing.ID, "payment_id": payment.ID}
+
+	for _, userID := range []string{payment.BuyerID, listing.UserID} {
+		_ = s.publisher.Publish(ctx, events.NewEvent(userID, dto.EventTypePaymentRefunded, data))
+	}
+}
 
-	return int64(fee)
+// calculateFee returns the marketplace fee for selling at the price, a percentage of
+// the price plus a fixed part.
+func calculateFee(price money.Money) money.Money {
+	return price.Percent(feePercent).Plus(price.Currency.Minimum / fixedFeeDivisor)
 }
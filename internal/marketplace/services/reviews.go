@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/internal/marketplace/repos"
+	"time"
+)
+
+var (
+	// ErrPaymentNotFound is returned when reviewed payment doesn't exist.
+	ErrPaymentNotFound = errors.New("payment not found")
+	// ErrPaymentRefunded is returned when reviewing a sale which has been refunded.
+	ErrPaymentRefunded = errors.New("refunded sales can't be reviewed")
+	// ErrReviewWindowClosed is returned when the review window of a sale has passed.
+	ErrReviewWindowClosed = errors.New("review window for this sale has closed")
+	// ErrAlreadyReviewed is returned when the user already reviewed this sale.
+	ErrAlreadyReviewed = errors.New("sale has already been reviewed")
+)
+
+// ReviewsService provides post-purchase reviews bussines logic.
+type ReviewsService struct {
+	repo   repos.ReviewsRepo
+	window time.Duration
+}
+
+// NewReviewsService returns an instance of ReviewsService. Sales can be reviewed for
+// window after the payment, zero window means there is no limit.
+func NewReviewsService(repo repos.ReviewsRepo, window time.Duration) *ReviewsService {
+	return &ReviewsService{
+		repo:   repo,
+		window: window,
+	}
+}
+
+// CreateReview handles logic for reviewing the other party of a sale. Only the buyer and
+// the seller of the payment can leave a review, once each.
+func (s *ReviewsService) CreateReview(
+	ctx context.Context,
+	req *dto.CreateReviewRequest,
+) (*dto.Review, error) {
+	payment, err := s.repo.GetReviewablePayment(ctx, req.PaymentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPaymentNotFound
+		}
+
+		return nil, fmt.Errorf("fetching payment: %w", err)
+	}
+
+	review := &dto.Review{
+		ID:               "",
+		PaymentID:        payment.ID,
+		ReviewerID:       req.ReviewerID,
+		ReviewerUsername: "",
+		RevieweeID:       "",
+		ReviewerRole:     "",
+		Rating:           req.Rating,
+		Body:             req.Body,
+		CreatedAt:        time.Time{},
+		UpdatedAt:        time.Time{},
+	}
+
+	switch req.ReviewerID {
+	case payment.BuyerID:
+		review.ReviewerRole = dto.ReviewerRoleBuyer
+		review.RevieweeID = payment.SellerID
+	case payment.SellerID:
+		review.ReviewerRole = dto.ReviewerRoleSeller
+		review.RevieweeID = payment.BuyerID
+	default:
+		return nil, ErrForbidden
+	}
+
+	if payment.RefundedAt != nil {
+		return nil, ErrPaymentRefunded
+	}
+
+	if s.window > 0 && time.Since(payment.CreatedAt) > s.window {
+		return nil, ErrReviewWindowClosed
+	}
+
+	created, err := s.repo.CreateReview(ctx, review)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAlreadyReviewed
+		}
+
+		return nil, fmt.Errorf("creating review: %w", err)
+	}
+
+	return created, nil
+}
+
+// GetReviews handles logic for fetching reviews a user received along with their reputation.
+func (s *ReviewsService) GetReviews(
+	ctx context.Context,
+	req *dto.GetReviewsRequest,
+) (*dto.GetReviewsResponse, error) {
+	if req.Limit <= 0 {
+		req.Limit = 10
+	}
+
+	reputation, err := s.repo.GetReputation(ctx, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching reputation: %w", err)
+	}
+
+	reviews, err := s.repo.GetReviews(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching reviews: %w", err)
+	}
+
+	return &dto.GetReviewsResponse{Reputation: *reputation, Reviews: reviews}, nil
+}
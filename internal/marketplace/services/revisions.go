@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	authDto "golang-connect-marketplace/internal/auth/dto"
+	"golang-connect-marketplace/internal/marketplace/dto"
+)
+
+// GetListingRevisions handles logic for fetching edit history of a listing, newest first.
+// Only the listing owner and admins can see it.
+func (s *ListingsService) GetListingRevisions(
+	ctx context.Context,
+	req *dto.GetListingRevisionsRequest,
+	user *authDto.UserClaims,
+) ([]dto.ListingRevision, error) {
+	if req.Limit <= 0 {
+		req.Limit = 50
+	}
+
+	listing, err := s.repo.GetListingByID(ctx, req.ListingID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching listing: %w", err)
+	}
+
+	if listing.UserID != user.ID && user.Role != authDto.UserRoleAdmin {
+		return nil, ErrForbidden
+	}
+
+	revisions, err := s.repo.GetListingRevisions(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching listing revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// GetPriceHistory handles logic for fetching public price changes of a listing.
+// UserID of the caller is empty for anonymous requests.
+func (s *ListingsService) GetPriceHistory(
+	ctx context.Context,
+	listingID, userID string,
+) ([]dto.PriceChange, error) {
+	listing, err := s.repo.GetListingByID(ctx, listingID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching listing: %w", err)
+	}
+
+	if listing.Status.IsModerated() && listing.UserID != userID {
+		return nil, ErrListingNotFound
+	}
+
+	history, err := s.repo.GetPriceHistory(ctx, listingID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching price history: %w", err)
+	}
+
+	return history, nil
+}
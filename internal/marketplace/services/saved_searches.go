@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/internal/marketplace/repos"
+	"strings"
+)
+
+// CreateSavedSearch handles logic for saving a listings search of a user.
+func (s *ListingsService) CreateSavedSearch(
+	ctx context.Context,
+	req *dto.CreateSavedSearchRequest,
+) (*dto.SavedSearch, error) {
+	if req.Filters.IsEmpty() {
+		return nil, ErrEmptySavedSearch
+	}
+
+	count, err := s.repo.CountSavedSearches(ctx, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("counting saved searches: %w", err)
+	}
+
+	if s.listingsCfg.MaxSavedSearchesPerUser > 0 &&
+		count >= s.listingsCfg.MaxSavedSearchesPerUser {
+		return nil, ErrTooManySavedSearches
+	}
+
+	resp, err := s.repo.CreateSavedSearch(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("creating saved search: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetSavedSearches handles logic for fetching saved searches of a user.
+func (s *ListingsService) GetSavedSearches(
+	ctx context.Context,
+	userID string,
+) ([]dto.SavedSearch, error) {
+	resp, err := s.repo.GetSavedSearches(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching saved searches: %w", err)
+	}
+
+	return resp, nil
+}
+
+// UpdateSavedSearch handles logic for updating a saved search of a user.
+func (s *ListingsService) UpdateSavedSearch(
+	ctx context.Context,
+	req *dto.UpdateSavedSearchRequest,
+) (*dto.SavedSearch, error) {
+	if req.Filters != nil && req.Filters.IsEmpty() {
+		return nil, ErrEmptySavedSearch
+	}
+
+	resp, err := s.repo.UpdateSavedSearch(ctx, req)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSavedSearchNotFound
+		}
+
+		return nil, fmt.Errorf("updating saved search: %w", err)
+	}
+
+	return resp, nil
+}
+
+// DeleteSavedSearch handles logic for deleting a saved search of a user.
+func (s *ListingsService) DeleteSavedSearch(ctx context.Context, searchID, userID string) error {
+	err := s.repo.DeleteSavedSearch(ctx, searchID, userID)
+	if err != nil {
+		if errors.Is(err, repos.ErrNoRowsReturned) {
+			return ErrSavedSearchNotFound
+		}
+
+		return fmt.Errorf("deleting saved search: %w", err)
+	}
+
+	return nil
+}
+
+// MatchSavedSearches evaluates listings created or reopened since the last run against
+// all saved searches. Users with instant saved searches are notified right away, matches
+// of daily saved searches wait for SendSavedSearchDigests. Listings are marked as matched
+// only after every saved search was evaluated, so a failed run is retried in full.
+func (s *ListingsService) MatchSavedSearches(ctx context.Context) error {
+	listingIDs, err := s.repo.GetUnmatchedListingIDs(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching unmatched listings: %w", err)
+	}
+
+	if len(listingIDs) == 0 {
+		return nil
+	}
+
+	searches, err := s.repo.GetAllSavedSearches(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching saved searches: %w", err)
+	}
+
+	for i := range searches {
+		matches, err := s.repo.MatchSavedSearch(ctx, &searches[i], listingIDs)
+		if err != nil {
+			return fmt.Errorf("matching saved search %s: %w", searches[i].ID, err)
+		}
+
+		if len(matches) == 0 || searches[i].Frequency != dto.SavedSearchFrequencyInstant {
+			continue
+		}
+
+		s.notify(ctx, savedSearchNotification(
+			dto.NotificationTypeSavedSearchMatch,
+			"New listings match your saved search",
+			matches,
+		))
+
+		err = s.repo.MarkSavedSearchMatchesNotified(ctx, matches)
+		if err != nil {
+			return fmt.Errorf("marking saved search matches as notified: %w", err)
+		}
+	}
+
+	err = s.repo.MarkListingsSearchMatched(ctx, listingIDs)
+	if err != nil {
+		return fmt.Errorf("marking listings as matched: %w", err)
+	}
+
+	return nil
+}
+
+// SendSavedSearchDigests sends every user a single digest with pending matches of their
+// daily saved searches.
+func (s *ListingsService) SendSavedSearchDigests(ctx context.Context) error {
+	matches, err := s.repo.GetPendingDigestMatches(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching pending digest matches: %w", err)
+	}
+
+	byUser := map[string][]dto.SavedSearchMatch{}
+	for _, m := range matches {
+		byUser[m.UserID] = append(byUser[m.UserID], m)
+	}
+
+	for _, userMatches := range byUser {
+		s.notify(ctx, savedSearchNotification(
+			dto.NotificationTypeSavedSearchDigest,
+			"Your daily saved search digest",
+			userMatches,
+		))
+
+		err = s.repo.MarkSavedSearchMatchesNotified(ctx, userMatches)
+		if err != nil {
+			return fmt.Errorf("marking digest matches as notified: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// savedSearchNotification lists matched listings grouped by saved search. All matches
+// must belong to the same user.
+func savedSearchNotification(
+	notificationType dto.NotificationType,
+	title string,
+	matches []dto.SavedSearchMatch,
+) *dto.Notification {
+	var body strings.Builder
+
+	listingIDs := make([]string, 0, len(matches))
+	searchIDs := []string{}
+
+	for i, m := range matches {
+		if i == 0 || matches[i-1].SavedSearchID != m.SavedSearchID {
+			searchIDs = append(searchIDs, m.SavedSearchID)
+			fmt.Fprintf(&body, "%q:\n", m.SearchName)
+		}
+
+		fmt.Fprintf(&body, "- %s\n", m.Title)
+
+		listingIDs = append(listingIDs, m.ListingID)
+	}
+
+	return &dto.Notification{
+		UserID: matches[0].UserID,
+		Email:  matches[0].Email,
+		Type:   notificationType,
+		Title:  title,
+		Body:   body.String(),
+		Data: map[string]string{
+			"saved_search_ids": strings.Join(searchIDs, ","),
+			"listing_ids":      strings.Join(listingIDs, ","),
+		},
+	}
+}
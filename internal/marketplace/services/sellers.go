@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+)
+
+const avatarsImageFolder = "avatars"
+
+// ErrSellerNotFound is returned when seller with provided username doesn't exist.
+var ErrSellerNotFound = errors.New("seller not found")
+
+// GetSellerProfile handles logic for fetching a seller storefront, their public profile
+// along with their open listings.
+func (s *ListingsService) GetSellerProfile(
+	ctx context.Context,
+	req *dto.GetSellerProfileRequest,
+) (*dto.GetSellerProfileResponse, error) {
+	profile, err := s.repo.GetSellerProfile(ctx, req.Username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSellerNotFound
+		}
+
+		return nil, fmt.Errorf("fetching seller profile: %w", err)
+	}
+
+	err = s.withAvatarURLs(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	listings, err := s.GetListings(ctx, &dto.GetListingsRequest{
+		Limit:           req.Limit,
+		Page:            req.Page,
+		Category:        nil,
+		Keyword:         nil,
+		SellerID:        &profile.ID,
+		Attributes:      nil,
+		AttributeRanges: nil,
+		Facets:          false,
+		Near:            "",
+		NearLatitude:    nil,
+		NearLongitude:   nil,
+		RadiusKm:        nil,
+		UserID:          req.UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.GetSellerProfileResponse{Profile: *profile, Listings: *listings}, nil
+}
+
+// UpdateSellerProfile handles logic for sellers editing their bio and avatar.
+func (s *ListingsService) UpdateSellerProfile(
+	ctx context.Context,
+	req *dto.UpdateSellerProfileRequest,
+) (*dto.SellerProfile, error) {
+	current, err := s.repo.GetSellerProfileByID(ctx, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching seller profile: %w", err)
+	}
+
+	if req.FileHeader != nil {
+		stored, err := s.storage.StoreImage(ctx, req.FileHeader, avatarsImageFolder)
+		if err != nil {
+			return nil, fmt.Errorf("storing avatar: %w", err)
+		}
+
+		req.AvatarPath = &stored.Path
+		req.AvatarVariants = stored.Variants
+	}
+
+	err = s.repo.UpdateSellerProfile(ctx, req)
+	if err != nil {
+		if req.AvatarPath != nil {
+			s.deleteStoredImage(ctx, *req.AvatarPath, req.AvatarVariants)
+		}
+
+		return nil, fmt.Errorf("updating seller profile: %w", err)
+	}
+
+	if req.AvatarPath != nil && current.AvatarPath != nil {
+		s.deleteStoredImage(ctx, *current.AvatarPath, current.AvatarVariants)
+	}
+
+	updated, err := s.repo.GetSellerProfileByID(ctx, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching updated seller profile: %w", err)
+	}
+
+	err = s.withAvatarURLs(ctx, updated)
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (s *ListingsService) withAvatarURLs(ctx context.Context, profile *dto.SellerProfile) error {
+	if profile.AvatarPath == nil {
+		return nil
+	}
+
+	url, err := s.storage.URL(ctx, *profile.AvatarPath, s.cfg.URLTTL)
+	if err != nil {
+		return fmt.Errorf("creating avatar url: %w", err)
+	}
+
+	variants, err := s.variantsWithURLs(ctx, profile.AvatarVariants)
+	if err != nil {
+		return err
+	}
+
+	profile.AvatarURL = url
+	profile.AvatarVariants = variants
+
+	return nil
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	authDto "golang-connect-marketplace/internal/auth/dto"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/internal/marketplace/events"
+	"golang-connect-marketplace/internal/marketplace/notifications"
+	"golang-connect-marketplace/internal/marketplace/repos"
+	"golang-connect-marketplace/internal/marketplace/tracking"
+)
+
+const trackShipmentsBatchSize = 100
+
+var (
+	// ErrOrderRefunded is returned when shipping an order which has been refunded.
+	ErrOrderRefunded = errors.New("refunded orders can't be shipped")
+	// ErrOrderNotShippable is returned when shipping an order the buyer picks up locally.
+	ErrOrderNotShippable = errors.New("order is picked up locally and can't be shipped")
+	// ErrOrderDelivered is returned when shipping an order which has already been delivered.
+	ErrOrderDelivered = errors.New("order has already been delivered")
+	// ErrShipmentNotFound is returned when order hasn't been shipped yet.
+	ErrShipmentNotFound = errors.New("shipment not found")
+)
+
+// ShipmentsService provides shipment tracking bussines logic.
+type ShipmentsService struct {
+	repo      repos.ShipmentsRepo
+	provider  tracking.TrackingProvider
+	notifier  notifications.Notifier
+	publisher events.Publisher
+}
+
+// NewShipmentsService returns an instance of ShipmentsService.
+func NewShipmentsService(
+	repo repos.ShipmentsRepo,
+	provider tracking.TrackingProvider,
+	notifier notifications.Notifier,
+	publisher events.Publisher,
+) *ShipmentsService {
+	return &ShipmentsService{
+		repo:      repo,
+		provider:  provider,
+		notifier:  notifier,
+		publisher: publisher,
+	}
+}
+
+// CreateShipment handles logic for sellers attaching carrier and tracking number to an
+// order. The buyer is notified that the order is on its way.
+func (s *ShipmentsService) CreateShipment(
+	ctx context.Context,
+	req *dto.CreateShipmentRequest,
+) (*dto.Shipment, error) {
+	order, err := s.order(ctx, req.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.SellerID != req.SellerID {
+		return nil, ErrForbidden
+	}
+
+	switch {
+	case order.RefundedAt != nil:
+		return nil, ErrOrderRefunded
+	case order.ShippingMethod != nil && *order.ShippingMethod == dto.ShippingMethodLocalPickup:
+		return nil, ErrOrderNotShippable
+	case order.ShipmentStatus != nil && *order.ShipmentStatus == dto.ShipmentStatusDelivered:
+		return nil, ErrOrderDelivered
+	}
+
+	shipment, err := s.repo.ShipOrder(ctx, req)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrOrderDelivered
+		}
+
+		return nil, fmt.Errorf("shipping order: %w", err)
+	}
+
+	data := map[string]string{"order_id": order.ID, "shipment_id": shipment.ID}
+
+	_ = s.publisher.Publish(ctx, events.NewEvent(order.BuyerID, dto.EventTypeOrderShipped, data))
+
+	_ = s.notifier.Notify(ctx, &dto.Notification{
+		UserID: order.BuyerID,
+		Email:  order.BuyerEmail,
+		Type:   dto.NotificationTypeOrderShipped,
+		Title:  "Your order has been shipped",
+		Body: fmt.Sprintf("%q has been shipped with %s, tracking number %s.",
+			order.ListingTitle, shipment.Carrier, shipment.TrackingNumber,
+		),
+		Data: data,
+	})
+
+	return shipment, nil
+}
+
+// GetShipment handles logic for fetching the shipment of an order along with its events.
+// Only the buyer and the seller of the order can see it.
+func (s *ShipmentsService) GetShipment(
+	ctx context.Context,
+	orderID string,
+	user *authDto.UserClaims,
+) (*dto.Shipment, error) {
+	order, err := s.order(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.BuyerID != user.ID && order.SellerID != user.ID {
+		return nil, ErrOrderNotFound
+	}
+
+	shipment, err := s.repo.GetShipment(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrShipmentNotFound
+		}
+
+		return nil, fmt.Errorf("fetching shipment: %w", err)
+	}
+
+	return shipment, nil
+}
+
+// TrackShipments fetches progress of undelivered shipments from the tracking provider.
+// Buyers and sellers are notified once an order is delivered. Shipments the provider fails
+// to track are retried after all other shipments were checked.
+func (s *ShipmentsService) TrackShipments(ctx context.Context) error {
+	shipments, err := s.repo.GetShipmentsToTrack(ctx, trackShipmentsBatchSize)
+	if err != nil {
+		return fmt.Errorf("fetching shipments to track: %w", err)
+	}
+
+	var errs []error
+
+	for i := range shipments {
+		shipment := &shipments[i]
+
+		trackingEvents, err := s.provider.Track(ctx, shipment)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("tracking shipment %s: %w", shipment.ID, err))
+		}
+
+		delivered := applyShipmentEvents(shipment, trackingEvents)
+
+		err = s.repo.RecordShipmentEvents(ctx, shipment, trackingEvents)
+		if err != nil {
+			return fmt.Errorf("recording shipment events: %w", err)
+		}
+
+		if delivered {
+			s.notifyDelivered(ctx, shipment)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *ShipmentsService) order(ctx context.Context, orderID string) (*dto.ShippableOrder, error) {
+	order, err := s.repo.GetShippableOrder(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrOrderNotFound
+		}
+
+		return nil, fmt.Errorf("fetching order: %w", err)
+	}
+
+	return order, nil
+}
+
+// notifyDelivered tells the buyer and the seller that an order has been delivered.
+func (s *ShipmentsService) notifyDelivered(ctx context.Context, shipment *dto.Shipment) {
+	order, err := s.repo.GetShippableOrder(ctx, shipment.OrderID)
+	if err != nil {
+		return
+	}
+
+	data := map[string]string{"order_id": order.ID, "shipment_id": shipment.ID}
+	recipients := map[string]string{
+		order.BuyerID:  order.BuyerEmail,
+		order.SellerID: order.SellerEmail,
+	}
+
+	for userID, email := range recipients {
+		_ = s.publisher.Publish(ctx, events.NewEvent(userID, dto.EventTypeOrderDelivered, data))
+
+		_ = s.notifier.Notify(ctx, &dto.Notification{
+			UserID: userID,
+			Email:  email,
+			Type:   dto.NotificationTypeOrderDelivered,
+			Title:  "Order has been delivered",
+			Body:   fmt.Sprintf("%q has been delivered.", order.ListingTitle),
+			Data:   data,
+		})
+	}
+}
+
+// applyShipmentEvents sets status of the shipment from the events reported by the carrier
+// and reports whether the shipment has just been delivered. Delivery is final, otherwise
+// the most recent event wins.
+func applyShipmentEvents(shipment *dto.Shipment, trackingEvents []dto.ShipmentEvent) bool {
+	if shipment.Status == dto.ShipmentStatusDelivered {
+		return false
+	}
+
+	var latest *dto.ShipmentEvent
+
+	for i := range trackingEvents {
+		event := &trackingEvents[i]
+
+		if event.Status == dto.ShipmentStatusDelivered {
+			shipment.Status = dto.ShipmentStatusDelivered
+			shipment.DeliveredAt = &event.OccurredAt
+
+			return true
+		}
+
+		if latest == nil || event.OccurredAt.After(latest.OccurredAt) {
+			latest = event
+		}
+	}
+
+	if latest != nil {
+		shipment.Status = latest.Status
+	}
+
+	return false
+}
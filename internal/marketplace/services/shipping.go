@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/internal/marketplace/repos"
+	"golang-connect-marketplace/pkg/generate"
+	"golang-connect-marketplace/pkg/money"
+	"strings"
+)
+
+var (
+	// ErrShippingProfileNotFound is returned when shipping profile doesn't exist or belongs to
+	// other user.
+	ErrShippingProfileNotFound = errors.New("shipping profile not found")
+	// ErrShippingCurrencyMismatch is returned when shipping profile is priced in other currency
+	// than the listing.
+	ErrShippingCurrencyMismatch = errors.New("shipping profile currency doesn't match listing")
+)
+
+// CreateShippingProfile handles logic for creating a shipping profile of a seller.
+func (s *ListingsService) CreateShippingProfile(
+	ctx context.Context,
+	req *dto.CreateShippingProfileRequest,
+) (*dto.ShippingProfile, error) {
+	currency, err := money.Lookup(req.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnsupportedCurrency, err)
+	}
+
+	req.Currency = currency.Code
+
+	assignShippingOptionIDs(req.Options, nil)
+
+	resp, err := s.repo.CreateShippingProfile(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("creating shipping profile: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetShippingProfiles handles logic for fetching shipping profiles of a seller.
+func (s *ListingsService) GetShippingProfiles(
+	ctx context.Context,
+	userID string,
+) ([]dto.ShippingProfile, error) {
+	resp, err := s.repo.GetShippingProfiles(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching shipping profiles: %w", err)
+	}
+
+	return resp, nil
+}
+
+// UpdateShippingProfile handles logic for updating a shipping profile of a seller. Options
+// which keep the id of an existing option keep referring to it.
+func (s *ListingsService) UpdateShippingProfile(
+	ctx context.Context,
+	req *dto.UpdateShippingProfileRequest,
+) (*dto.ShippingProfile, error) {
+	if req.Options != nil {
+		current, err := s.shippingProfile(ctx, req.ID, req.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		assignShippingOptionIDs(*req.Options, current.Options)
+	}
+
+	resp, err := s.repo.UpdateShippingProfile(ctx, req)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrShippingProfileNotFound
+		}
+
+		return nil, fmt.Errorf("updating shipping profile: %w", err)
+	}
+
+	return resp, nil
+}
+
+// DeleteShippingProfile handles logic for deleting a shipping profile of a seller.
+func (s *ListingsService) DeleteShippingProfile(
+	ctx context.Context,
+	profileID, userID string,
+) error {
+	err := s.repo.DeleteShippingProfile(ctx, profileID, userID)
+	if err != nil {
+		if errors.Is(err, repos.ErrNoRowsReturned) {
+			return ErrShippingProfileNotFound
+		}
+
+		return fmt.Errorf("deleting shipping profile: %w", err)
+	}
+
+	return nil
+}
+
+// SetListingShipping handles logic for choosing the shipping profile buyers pick shipping
+// options from when buying the listing.
+func (s *ListingsService) SetListingShipping(
+	ctx context.Context,
+	req *dto.SetListingShippingRequest,
+) (*dto.Listing, error) {
+	listing, err := s.repo.GetListingByID(ctx, req.ListingID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching listing: %w", err)
+	}
+
+	if listing.UserID != req.UserID {
+		return nil, ErrForbidden
+	}
+
+	err = s.checkShippingProfile(ctx, req.ShippingProfileID, req.UserID, listing.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.SetListingShippingProfile(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("setting listing shipping profile: %w", err)
+	}
+
+	listing, err = s.repo.GetListingByID(ctx, req.ListingID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching updated listing: %w", err)
+	}
+
+	err = s.withImageURLs(ctx, listing)
+	if err != nil {
+		return nil, err
+	}
+
+	return listing, nil
+}
+
+// checkShippingProfile checks that the shipping profile, if any, belongs to the user and is
+// priced in the listing currency.
+func (s *ListingsService) checkShippingProfile(
+	ctx context.Context,
+	profileID *string,
+	userID, currency string,
+) error {
+	if profileID == nil {
+		return nil
+	}
+
+	profile, err := s.shippingProfile(ctx, *profileID, userID)
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(profile.Currency, currency) {
+		return fmt.Errorf(
+			"%w: profile is priced in %s, listing in %s",
+			ErrShippingCurrencyMismatch,
+			profile.Currency,
+			currency,
+		)
+	}
+
+	return nil
+}
+
+func (s *ListingsService) shippingProfile(
+	ctx context.Context,
+	profileID, userID string,
+) (*dto.ShippingProfile, error) {
+	profile, err := s.repo.GetShippingProfile(ctx, profileID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrShippingProfileNotFound
+		}
+
+		return nil, fmt.Errorf("fetching shipping profile: %w", err)
+	}
+
+	return profile, nil
+}
+
+// assignShippingOptionIDs generates ids for options which don't refer to a current option,
+// or refer to one already kept by a previous option.
+func assignShippingOptionIDs(options, current dto.ShippingOptions) {
+	kept := map[string]bool{}
+
+	for i := range options {
+		id := options[i].ID
+		if id == "" || kept[id] || current.Find(id) == nil {
+			options[i].ID = generate.ID("ship")
+		}
+
+		kept[options[i].ID] = true
+	}
+}
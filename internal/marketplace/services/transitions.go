@@ -0,0 +1,111 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"slices"
+)
+
+// ErrIllegalStatusTransition is returned when a listing can't change to the requested status.
+var ErrIllegalStatusTransition = errors.New("illegal listing status transition")
+
+// StatusTransitionError describes a listing status change that is not allowed
+// for the actor who tried to make it.
+type StatusTransitionError struct {
+	From  dto.ListingStatus
+	To    dto.ListingStatus
+	Actor string
+}
+
+func (e *StatusTransitionError) Error() string {
+	return fmt.Sprintf("%s can't change listing status from %s to %s", e.Actor, e.From, e.To)
+}
+
+// Unwrap makes StatusTransitionError match ErrIllegalStatusTransition.
+func (e *StatusTransitionError) Unwrap() error {
+	return ErrIllegalStatusTransition
+}
+
+// listingActor identifies who or what changes the status of a listing.
+type listingActor string
+
+const (
+	actorOwner      listingActor = "owner"
+	actorAdmin      listingActor = "admin"
+	actorScreening  listingActor = "screening"
+	actorModeration listingActor = "moderation"
+	actorPayment    listingActor = "payment"
+	actorExpiry     listingActor = "expiry"
+)
+
+type statusTransition struct {
+	from dto.ListingStatus
+	to   dto.ListingStatus
+}
+
+// listingTransitions lists every allowed listing status change and who can make it.
+// Owners and admins can only cancel and reopen listings, sold and refunded statuses are
+// reachable only through payment events and moderated statuses only through moderation.
+var listingTransitions = map[statusTransition][]listingActor{
+	{dto.ListingStatusOpen, dto.ListingStatusCanceled}: {actorOwner, actorAdmin},
+	{dto.ListingStatusCanceled, dto.ListingStatusOpen}: {actorOwner, actorAdmin},
+
+	{dto.ListingStatusOpen, dto.ListingStatusExpired}: {actorExpiry},
+	{dto.ListingStatusExpired, dto.ListingStatusOpen}: {actorOwner},
+
+	{dto.ListingStatusOpen, dto.ListingStatusSold}:     {actorPayment},
+	{dto.ListingStatusSold, dto.ListingStatusRefunded}: {actorPayment},
+
+	{dto.ListingStatusOpen, dto.ListingStatusPendingReview}:     {actorScreening},
+	{dto.ListingStatusCanceled, dto.ListingStatusPendingReview}: {actorScreening},
+
+	{dto.ListingStatusPendingReview, dto.ListingStatusOpen}:   {actorModeration},
+	{dto.ListingStatusOpen, dto.ListingStatusHidden}:          {actorModeration},
+	{dto.ListingStatusPendingReview, dto.ListingStatusHidden}: {actorModeration},
+	{dto.ListingStatusHidden, dto.ListingStatusOpen}:          {actorModeration},
+
+	{dto.ListingStatusOpen, dto.ListingStatusRemoved}:          {actorModeration},
+	{dto.ListingStatusCanceled, dto.ListingStatusRemoved}:      {actorModeration},
+	{dto.ListingStatusExpired, dto.ListingStatusRemoved}:       {actorModeration},
+	{dto.ListingStatusPendingReview, dto.ListingStatusRemoved}: {actorModeration},
+	{dto.ListingStatusHidden, dto.ListingStatusRemoved}:        {actorModeration},
+}
+
+// moderationTargetStatus maps moderation actions to the listing status they lead to.
+var moderationTargetStatus = map[dto.ModerationActionType]dto.ListingStatus{
+	dto.ModerationActionApproveListing: dto.ListingStatusOpen,
+	dto.ModerationActionHideListing:    dto.ListingStatusHidden,
+	dto.ModerationActionRestoreListing: dto.ListingStatusOpen,
+	dto.ModerationActionRemoveListing:  dto.ListingStatusRemoved,
+}
+
+// checkStatusTransition returns StatusTransitionError when the actor is not allowed
+// to change listing status from one status to the other.
+func checkStatusTransition(from, to dto.ListingStatus, actor listingActor) error {
+	if from == to {
+		return nil
+	}
+
+	if slices.Contains(listingTransitions[statusTransition{from, to}], actor) {
+		return nil
+	}
+
+	return &StatusTransitionError{From: from, To: to, Actor: string(actor)}
+}
+
+// sourceStatuses returns the statuses from which the actor can change listing status
+// to the status, for status changes made to many listings at once.
+func sourceStatuses(to dto.ListingStatus, actor listingActor) []dto.ListingStatus {
+	var statuses []dto.ListingStatus
+
+	for transition, actors := range listingTransitions {
+		if transition.to == to && slices.Contains(actors, actor) {
+			statuses = append(statuses, transition.from)
+		}
+	}
+
+	slices.Sort(statuses)
+
+	return statuses
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckStatusTransition(t *testing.T) {
+	t.Parallel()
+
+	const (
+		open     = dto.ListingStatusOpen
+		canceled = dto.ListingStatusCanceled
+		expired  = dto.ListingStatusExpired
+		sold     = dto.ListingStatusSold
+		refunded = dto.ListingStatusRefunded
+		pending  = dto.ListingStatusPendingReview
+		hidden   = dto.ListingStatusHidden
+		removed  = dto.ListingStatusRemoved
+	)
+
+	testCases := []struct {
+		desc    string
+		from    dto.ListingStatus
+		to      dto.ListingStatus
+		actor   listingActor
+		allowed bool
+	}{
+		{"owner cancels", open, canceled, actorOwner, true},
+		{"admin reopens", canceled, open, actorAdmin, true},
+		{"owner reopens expired", expired, open, actorOwner, true},
+		{"admin can't reopen expired", expired, open, actorAdmin, false},
+		{"owner can't sell", open, sold, actorOwner, false},
+		{"owner can't unhide", hidden, open, actorOwner, false},
+		{"owner can't remove", open, removed, actorOwner, false},
+		{"same status", sold, sold, actorOwner, true},
+		{"same moderated status", removed, removed, actorPayment, true},
+
+		{"payment sells", open, sold, actorPayment, true},
+		{"payment refunds", sold, refunded, actorPayment, true},
+		{"payment can't sell expired", expired, sold, actorPayment, false},
+		{"payment can't sell hidden", hidden, sold, actorPayment, false},
+		{"payment can't refund open", open, refunded, actorPayment, false},
+
+		{"expiry expires open", open, expired, actorExpiry, true},
+		{"expiry can't expire sold", sold, expired, actorExpiry, false},
+		{"expiry can't reopen", expired, open, actorExpiry, false},
+
+		{"screening flags open", open, pending, actorScreening, true},
+		{"screening can't approve", pending, open, actorScreening, false},
+
+		{"moderation approves", pending, open, actorModeration, true},
+		{"moderation hides", open, hidden, actorModeration, true},
+		{"moderation removes hidden", hidden, removed, actorModeration, true},
+		{"moderation can't remove sold", sold, removed, actorModeration, false},
+		{"moderation can't restore removed", removed, open, actorModeration, false},
+		{"moderation can't cancel", open, canceled, actorModeration, false},
+	}
+
+	for _, tc := range testCases {
+		err := checkStatusTransition(tc.from, tc.to, tc.actor)
+		if tc.allowed {
+			require.NoError(t, err, tc.desc)
+
+			continue
+		}
+
+		require.ErrorIs(t, err, ErrIllegalStatusTransition, tc.desc)
+
+		var transitionErr *StatusTransitionError
+
+		require.ErrorAs(t, err, &transitionErr, tc.desc)
+		require.Equal(t, string(tc.actor), transitionErr.Actor, tc.desc)
+	}
+}
+
+func TestSourceStatuses(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc  string
+		to    dto.ListingStatus
+		actor listingActor
+		want  []dto.ListingStatus
+	}{
+		{"expiry", dto.ListingStatusExpired, actorExpiry, []dto.ListingStatus{dto.ListingStatusOpen}},
+		{"payment sale", dto.ListingStatusSold, actorPayment, []dto.ListingStatus{dto.ListingStatusOpen}},
+		{
+			"moderation removal",
+			dto.ListingStatusRemoved,
+			actorModeration,
+			[]dto.ListingStatus{
+				dto.ListingStatusCanceled,
+				dto.ListingStatusExpired,
+				dto.ListingStatusHidden,
+				dto.ListingStatusOpen,
+				dto.ListingStatusPendingReview,
+			},
+		},
+		{"no allowed source", dto.ListingStatusSold, actorOwner, nil},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.want, sourceStatuses(tc.to, tc.actor), tc.desc)
+	}
+}
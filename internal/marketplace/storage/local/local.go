@@ -3,84 +3,103 @@ package local
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
 	"fmt"
-	"golang-connect-marketplace/pkg/generate"
-	"io"
-	"mime/multipart"
-	"net/http"
+	"golang-connect-marketplace/internal/marketplace/storage"
+	"golang-connect-marketplace/pkg/imaging"
+	"io/fs"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 )
 
 var (
-	errPathIsEmpty         = errors.New("path is empty")
-	errUnsupportedFileType = errors.New("unsupported file type")
+	errPathIsEmpty = errors.New("path is empty")
+	errInvalidKey  = errors.New("invalid storage key")
 )
 
 const (
-	uploadDirPerm          = 0o750
-	checkFileTypeReadBytes = 512
+	uploadDirPerm = 0o750
+	filePerm      = 0o640
+
+	queryParamExpires   = "expires"
+	queryParamSignature = "signature"
 )
 
 type localStorage struct {
-	uploadsDir string
+	uploadsDir    string
+	baseURL       string
+	signingSecret []byte
+	processor     *imaging.Processor
 }
 
 // NewLocalStorage creates a new local storage with upload directory.
-func NewLocalStorage(uploadsDir string) *localStorage { //nolint:revive
+// Images are served through signed URLs under baseURL, signed with signingSecret.
+func NewLocalStorage( //nolint:revive
+	uploadsDir, baseURL, signingSecret string,
+	processor *imaging.Processor,
+) *localStorage {
+	if signingSecret == "" {
+		panic("signingSecret is required for localStorage")
+	}
+
 	return &localStorage{
-		uploadsDir: uploadsDir,
+		uploadsDir:    uploadsDir,
+		baseURL:       strings.TrimSuffix(baseURL, "/"),
+		signingSecret: []byte(signingSecret),
+		processor:     processor,
 	}
 }
 
-// StoreImage stores an image file and returns its local path.
+// StoreImage processes an image file, stores it with its variants and returns their keys.
 func (s *localStorage) StoreImage(
-	_ context.Context,
-	fileHeader *multipart.FileHeader,
+	ctx context.Context,
+	upload storage.Upload,
 	folder string,
-) (string, error) {
-	file, err := fileHeader.Open()
-	if err != nil {
-		return "", fmt.Errorf("failed to open file from header: %w", err)
-	}
-	defer file.Close() //nolint:errcheck
-
-	suffix, err := s.detectImageType(file)
+) (*storage.StoredImage, error) {
+	stored, err := storage.Store(ctx, s.processor, s, upload, folder)
 	if err != nil {
-		return "", fmt.Errorf("failed to detect image type: %w", err)
+		return nil, fmt.Errorf("storing image locally: %w", err)
 	}
 
-	folderPath := filepath.Join(s.uploadsDir, folder)
-	fileName := generate.ID("") + suffix
-	imagePath := filepath.Join(folderPath, fileName)
+	return stored, nil
+}
 
-	err = os.MkdirAll(folderPath, uploadDirPerm)
+// PutObject writes data to the file the key resolves to.
+func (s *localStorage) PutObject(_ context.Context, key string, data []byte, _ string) error {
+	filePath, err := s.resolve(key)
 	if err != nil {
-		return "", fmt.Errorf("failed to create upload folder: %w", err)
+		return err
 	}
 
-	out, err := os.Create(imagePath) //nolint:gosec
+	err = os.MkdirAll(filepath.Dir(filePath), uploadDirPerm)
 	if err != nil {
-		return "", fmt.Errorf("failed to store file locally: %w", err)
+		return fmt.Errorf("failed to create upload folder: %w", err)
 	}
-	defer out.Close() //nolint:errcheck
 
-	_, err = io.Copy(out, file)
+	err = os.WriteFile(filePath, data, filePerm)
 	if err != nil {
-		return "", fmt.Errorf("failed to copy file: %w", err)
+		return fmt.Errorf("failed to store file locally: %w", err)
 	}
 
-	return imagePath, nil
+	return nil
 }
 
-// DeleteImage deletes an image file from local path.
-func (s *localStorage) DeleteImage(_ context.Context, path string) error {
-	if path == "" {
-		return errPathIsEmpty
+// DeleteImage deletes an image file by its key.
+func (s *localStorage) DeleteImage(_ context.Context, key string) error {
+	filePath, err := s.resolve(key)
+	if err != nil {
+		return err
 	}
 
-	err := os.Remove(path)
+	err = os.Remove(filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil
@@ -92,27 +111,103 @@ func (s *localStorage) DeleteImage(_ context.Context, path string) error {
 	return nil
 }
 
-func (s *localStorage) detectImageType(file multipart.File) (string, error) {
-	buf := make([]byte, checkFileTypeReadBytes)
+// ListImages returns keys of all stored files last modified before modifiedBefore.
+func (s *localStorage) ListImages(ctx context.Context, modifiedBefore time.Time) ([]string, error) {
+	keys := []string{}
+
+	err := filepath.WalkDir(s.uploadsDir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
 
-	n, err := file.Read(buf)
-	if err != nil && !errors.Is(err, io.EOF) {
-		return "", fmt.Errorf("failed to read file for type detection: %w", err)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return fmt.Errorf("reading file info: %w", err)
+		}
+
+		if !info.ModTime().Before(modifiedBefore) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.uploadsDir, filePath)
+		if err != nil {
+			return fmt.Errorf("resolving storage key: %w", err)
+		}
+
+		keys = append(keys, filepath.ToSlash(rel))
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing stored files: %w", err)
 	}
 
-	mimeType := http.DetectContentType(buf[:n])
+	return keys, nil
+}
+
+// URL returns an HMAC-signed URL for the key which is valid for ttl.
+func (s *localStorage) URL(_ context.Context, key string, ttl time.Duration) (string, error) {
+	if key == "" {
+		return "", errPathIsEmpty
+	}
 
-	_, err = file.Seek(0, io.SeekStart)
+	expires := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+
+	query := url.Values{}
+	query.Set(queryParamExpires, expires)
+	query.Set(queryParamSignature, s.sign(key, expires))
+
+	return s.baseURL + "/" + strings.TrimPrefix(key, "/") + "?" + query.Encode(), nil
+}
+
+// VerifySignedURL checks the signature and expiry of a signed URL and returns the file path
+// of the key.
+func (s *localStorage) VerifySignedURL(key, expires, signature string) (string, error) {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
 	if err != nil {
-		return "", fmt.Errorf("failed to reset file pointer: %w", err)
+		return "", fmt.Errorf("%w: malformed expiry", storage.ErrInvalidSignature)
+	}
+
+	if time.Now().Unix() > expiresAt {
+		return "", fmt.Errorf("%w: url has expired", storage.ErrInvalidSignature)
 	}
 
-	switch mimeType {
-	case "image/png":
-		return ".png", nil
-	case "image/jpeg":
-		return ".jpg", nil
-	default:
-		return "", fmt.Errorf("%w: %s", errUnsupportedFileType, mimeType)
+	if !hmac.Equal([]byte(signature), []byte(s.sign(key, expires))) {
+		return "", storage.ErrInvalidSignature
 	}
+
+	return s.resolve(key)
+}
+
+func (s *localStorage) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, s.signingSecret)
+	mac.Write([]byte(key + "\n" + expires))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// resolve maps a key to a file path inside the upload directory.
+func (s *localStorage) resolve(key string) (string, error) {
+	if key == "" {
+		return "", errPathIsEmpty
+	}
+
+	cleaned := path.Clean("/" + key)
+	if strings.Contains(key, "..") || cleaned == "/" {
+		return "", fmt.Errorf("%w: %s", errInvalidKey, key)
+	}
+
+	return filepath.Join(s.uploadsDir, filepath.FromSlash(cleaned)), nil
 }
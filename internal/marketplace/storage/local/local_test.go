@@ -0,0 +1,114 @@
+package local
+
+import (
+	"context"
+	"golang-connect-marketplace/internal/marketplace/storage"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signedURLParts(t *testing.T, raw string) (string, string, string) {
+	t.Helper()
+
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+
+	key := strings.TrimPrefix(u.Path, "/api/v1/images/")
+
+	return key, u.Query().Get(queryParamExpires), u.Query().Get(queryParamSignature)
+}
+
+func TestLocalStorage_SignedURL(t *testing.T) {
+	t.Parallel()
+
+	s := NewLocalStorage("uploads/images/", "/api/v1/images/", "secret", nil)
+
+	raw, err := s.URL(context.Background(), "item_1/abc.jpg", time.Minute)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(raw, "/api/v1/images/item_1/abc.jpg?"), raw)
+
+	key, expires, signature := signedURLParts(t, raw)
+
+	path, err := s.VerifySignedURL(key, expires, signature)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("uploads", "images", "item_1", "abc.jpg"), path)
+}
+
+func TestLocalStorage_VerifySignedURL_Rejects(t *testing.T) {
+	t.Parallel()
+
+	s := NewLocalStorage("uploads/images", "/api/v1/images", "secret", nil)
+	other := NewLocalStorage("uploads/images", "/api/v1/images", "other-secret", nil)
+
+	valid, err := s.URL(context.Background(), "item_1/abc.jpg", time.Minute)
+	require.NoError(t, err)
+
+	expired, err := s.URL(context.Background(), "item_1/abc.jpg", -time.Minute)
+	require.NoError(t, err)
+
+	foreign, err := other.URL(context.Background(), "item_1/abc.jpg", time.Minute)
+	require.NoError(t, err)
+
+	key, expires, signature := signedURLParts(t, valid)
+
+	testCases := []struct {
+		desc                    string
+		key, expires, signature string
+	}{
+		{"tampered key", "item_2/abc.jpg", expires, signature},
+		{"tampered expiry", key, "99999999999", signature},
+		{"malformed expiry", key, "tomorrow", signature},
+		{"missing signature", key, expires, ""},
+	}
+
+	for _, tc := range testCases {
+		_, err := s.VerifySignedURL(tc.key, tc.expires, tc.signature)
+		require.ErrorIs(t, err, storage.ErrInvalidSignature, tc.desc)
+	}
+
+	for desc, raw := range map[string]string{"expired": expired, "signed by other": foreign} {
+		k, e, sig := signedURLParts(t, raw)
+
+		_, err := s.VerifySignedURL(k, e, sig)
+		require.ErrorIs(t, err, storage.ErrInvalidSignature, desc)
+	}
+}
+
+func TestLocalStorage_ListImages(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	s := NewLocalStorage(dir, "/api/v1/images", "secret", nil)
+	ctx := context.Background()
+
+	require.NoError(t, s.PutObject(ctx, "item_1/a.jpg", []byte("a"), "image/jpeg"))
+	require.NoError(t, s.PutObject(ctx, "categories/b.png", []byte("b"), "image/png"))
+
+	keys, err := s.ListImages(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"item_1/a.jpg", "categories/b.png"}, keys)
+
+	keys, err = s.ListImages(ctx, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, keys, "files within the grace period must not be listed")
+
+	missing := NewLocalStorage(filepath.Join(dir, "missing"), "/api/v1/images", "secret", nil)
+
+	keys, err = missing.ListImages(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
+func TestNewLocalStorage_RequiresSigningSecret(t *testing.T) {
+	t.Parallel()
+
+	require.Panics(t, func() {
+		NewLocalStorage("uploads/images", "/api/v1/images", "", nil)
+	})
+}
@@ -0,0 +1,163 @@
+// Package s3 handles storage of images in S3-compatible object storage.
+package s3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"golang-connect-marketplace/config"
+	"golang-connect-marketplace/internal/marketplace/storage"
+	"golang-connect-marketplace/pkg/imaging"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+var (
+	errKeyIsEmpty     = errors.New("key is empty")
+	errBucketIsNotSet = errors.New("bucket is not set")
+)
+
+type s3Storage struct {
+	client    *minio.Client
+	bucket    string
+	prefix    string
+	processor *imaging.Processor
+}
+
+// NewS3Storage creates a new S3-compatible storage for the configured bucket.
+func NewS3Storage( //nolint:revive
+	cfg *config.S3Config,
+	processor *imaging.Processor,
+) (*s3Storage, error) {
+	if cfg.Bucket == "" {
+		return nil, errBucketIsNotSet
+	}
+
+	lookup := minio.BucketLookupDNS
+	if cfg.UsePathStyle {
+		lookup = minio.BucketLookupPath
+	}
+
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:        credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		Secure:       cfg.UseSSL,
+		Region:       cfg.Region,
+		BucketLookup: lookup,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating s3 client: %w", err)
+	}
+
+	return &s3Storage{
+		client:    client,
+		bucket:    cfg.Bucket,
+		prefix:    strings.Trim(cfg.Prefix, "/"),
+		processor: processor,
+	}, nil
+}
+
+// StoreImage processes an image file, uploads it with its variants and returns their keys.
+func (s *s3Storage) StoreImage(
+	ctx context.Context,
+	upload storage.Upload,
+	folder string,
+) (*storage.StoredImage, error) {
+	stored, err := storage.Store(ctx, s.processor, s, upload, folder)
+	if err != nil {
+		return nil, fmt.Errorf("storing image in s3: %w", err)
+	}
+
+	return stored, nil
+}
+
+// PutObject uploads data under the given key.
+func (s *s3Storage) PutObject(
+	ctx context.Context,
+	key string,
+	data []byte,
+	contentType string,
+) error {
+	if key == "" {
+		return errKeyIsEmpty
+	}
+
+	_, err := s.client.PutObject(
+		ctx,
+		s.bucket,
+		s.objectName(key),
+		bytes.NewReader(data),
+		int64(len(data)),
+		minio.PutObjectOptions{ContentType: contentType},
+	)
+	if err != nil {
+		return fmt.Errorf("uploading object to s3: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteImage deletes an object by its key. Deleting a missing object is not an error.
+func (s *s3Storage) DeleteImage(ctx context.Context, key string) error {
+	if key == "" {
+		return errKeyIsEmpty
+	}
+
+	err := s.client.RemoveObject(ctx, s.bucket, s.objectName(key), minio.RemoveObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("deleting object from s3: %w", err)
+	}
+
+	return nil
+}
+
+// ListImages returns keys of all objects under the prefix last modified before modifiedBefore.
+func (s *s3Storage) ListImages(ctx context.Context, modifiedBefore time.Time) ([]string, error) {
+	keys := []string{}
+
+	objectPrefix := ""
+	if s.prefix != "" {
+		objectPrefix = s.prefix + "/"
+	}
+
+	objects := s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{ //nolint:exhaustruct
+		Prefix:    objectPrefix,
+		Recursive: true,
+	})
+
+	for obj := range objects {
+		if obj.Err != nil {
+			return nil, fmt.Errorf("listing s3 objects: %w", obj.Err)
+		}
+
+		if !obj.LastModified.Before(modifiedBefore) {
+			continue
+		}
+
+		keys = append(keys, strings.TrimPrefix(obj.Key, objectPrefix))
+	}
+
+	return keys, nil
+}
+
+// URL returns a presigned GET URL for the object which is valid for ttl.
+func (s *s3Storage) URL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	if key == "" {
+		return "", errKeyIsEmpty
+	}
+
+	u, err := s.client.PresignedGetObject(ctx, s.bucket, s.objectName(key), ttl, nil)
+	if err != nil {
+		return "", fmt.Errorf("presigning s3 object url: %w", err)
+	}
+
+	return u.String(), nil
+}
+
+func (s *s3Storage) objectName(key string) string {
+	return path.Join(s.prefix, strings.TrimPrefix(key, "/"))
+}
@@ -0,0 +1,142 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"golang-connect-marketplace/config"
+	"golang-connect-marketplace/pkg/imaging"
+	"image"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeObject struct {
+	data        []byte
+	contentType string
+}
+
+// fakeS3 is an in-memory stand-in for a path-style S3 API supporting PUT and DELETE object.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]fakeObject
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	name := strings.TrimPrefix(r.URL.Path, "/")
+
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		f.objects[name] = fakeObject{data: data, contentType: r.Header.Get("Content-Type")}
+		w.Header().Set("ETag", `"etag"`)
+		w.WriteHeader(http.StatusOK)
+	case http.MethodDelete:
+		delete(f.objects, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+func newTestStorage(t *testing.T) (*s3Storage, *fakeS3) {
+	t.Helper()
+
+	fake := &fakeS3{mu: sync.Mutex{}, objects: map[string]fakeObject{}}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	srvURL, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	processor := imaging.NewProcessor(imaging.Options{
+		Variants: []imaging.Variant{{Name: "thumb", MaxSize: 4}},
+	})
+
+	s, err := NewS3Storage(&config.S3Config{
+		Endpoint:        srvURL.Host,
+		Region:          "us-east-1",
+		Bucket:          "market",
+		Prefix:          "/images/",
+		AccessKeyID:     "key",
+		SecretAccessKey: "secret",
+		UseSSL:          false,
+		UsePathStyle:    true,
+	}, processor)
+	require.NoError(t, err)
+
+	return s, fake
+}
+
+func pngFileHeader(t *testing.T) *multipart.FileHeader {
+	t.Helper()
+
+	var body bytes.Buffer
+
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("image", "image.png")
+	require.NoError(t, err)
+	require.NoError(t, png.Encode(part, image.NewRGBA(image.Rect(0, 0, 8, 8))))
+	require.NoError(t, mw.Close())
+
+	form, err := multipart.NewReader(&body, mw.Boundary()).ReadForm(1 << 20)
+	require.NoError(t, err)
+
+	return form.File["image"][0]
+}
+
+func TestS3Storage_StoreAndDeleteImage(t *testing.T) {
+	t.Parallel()
+
+	s, fake := newTestStorage(t)
+	ctx := context.Background()
+
+	stored, err := s.StoreImage(ctx, pngFileHeader(t), "item_1")
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(stored.Path, "item_1/"), "keys must not contain the prefix")
+	require.Contains(t, stored.Variants, "thumb")
+
+	obj, ok := fake.objects["market/images/"+stored.Path]
+	require.True(t, ok, "object must be uploaded under bucket and prefix")
+	assert.Equal(t, "image/png", obj.contentType)
+	assert.Contains(t, fake.objects, "market/images/"+stored.Variants["thumb"].Path)
+
+	for _, key := range stored.Paths() {
+		require.NoError(t, s.DeleteImage(ctx, key))
+	}
+
+	assert.Empty(t, fake.objects)
+}
+
+func TestS3Storage_URLIsPresigned(t *testing.T) {
+	t.Parallel()
+
+	s, _ := newTestStorage(t)
+
+	raw, err := s.URL(context.Background(), "item_1/abc.jpg", time.Hour)
+	require.NoError(t, err)
+
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+
+	assert.Equal(t, "/market/images/item_1/abc.jpg", u.Path)
+	assert.Equal(t, "3600", u.Query().Get("X-Amz-Expires"))
+	assert.NotEmpty(t, u.Query().Get("X-Amz-Signature"))
+
+	_, err = s.URL(context.Background(), "", time.Hour)
+	require.Error(t, err)
+}
@@ -1,13 +1,129 @@
-// Package storage handles local storage of listing images.
+// Package storage handles storage of listing images.
 package storage
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"golang-connect-marketplace/pkg/generate"
+	"golang-connect-marketplace/pkg/imaging"
 	"mime/multipart"
+	"path"
+	"time"
 )
 
+// ErrInvalidSignature is returned when a signed URL is malformed, tampered with or expired.
+var ErrInvalidSignature = errors.New("invalid or expired url signature")
+
+// Upload is an uploaded file, implemented by *multipart.FileHeader.
+type Upload interface {
+	Open() (multipart.File, error)
+}
+
 // Storage defines methods for storing and deleting listing images.
+//
+// Images are addressed by backend-neutral keys such as "item_x/abc.jpg",
+// each backend decides where the key physically lives.
 type Storage interface {
-	StoreImage(ctx context.Context, fileHeader *multipart.FileHeader, path string) (string, error)
-	DeleteImage(ctx context.Context, path string) error
+	StoreImage(ctx context.Context, upload Upload, folder string) (*StoredImage, error)
+	DeleteImage(ctx context.Context, key string) error
+	URL(ctx context.Context, key string, ttl time.Duration) (string, error)
+	ListImages(ctx context.Context, modifiedBefore time.Time) ([]string, error)
+}
+
+// SignedURLVerifier verifies signed URLs issued by a storage backend and resolves them
+// to a local file path.
+type SignedURLVerifier interface {
+	VerifySignedURL(key, expires, signature string) (string, error)
+}
+
+// ObjectWriter writes and deletes raw objects by key.
+type ObjectWriter interface {
+	PutObject(ctx context.Context, key string, data []byte, contentType string) error
+	DeleteImage(ctx context.Context, key string) error
+}
+
+// StoredImage represents a processed image and its variants saved in storage.
+type StoredImage struct {
+	Path     string
+	Variants dto.ImageVariants
+}
+
+// Paths returns keys of the image and all its variants.
+func (s *StoredImage) Paths() []string {
+	paths := make([]string, 0, len(s.Variants)+1)
+	paths = append(paths, s.Path)
+
+	for _, v := range s.Variants {
+		paths = append(paths, v.Path)
+	}
+
+	return paths
+}
+
+// Store processes an uploaded image and writes it together with its variants into folder.
+// If any write fails, objects written so far are deleted.
+func Store(
+	ctx context.Context,
+	processor *imaging.Processor,
+	writer ObjectWriter,
+	upload Upload,
+	folder string,
+) (*StoredImage, error) {
+	processed, err := processUpload(processor, upload)
+	if err != nil {
+		return nil, err
+	}
+
+	id := generate.ID("")
+
+	stored := &StoredImage{
+		Path:     path.Join(folder, id+processed.Original.Ext),
+		Variants: make(dto.ImageVariants, len(processed.Variants)),
+	}
+
+	original := processed.Original
+
+	err = writer.PutObject(ctx, stored.Path, original.Data, original.ContentType)
+	if err != nil {
+		return nil, fmt.Errorf("writing image: %w", err)
+	}
+
+	for name, variant := range processed.Variants {
+		key := path.Join(folder, fmt.Sprintf("%s_%s%s", id, name, variant.Ext))
+
+		err = writer.PutObject(ctx, key, variant.Data, variant.ContentType)
+		if err != nil {
+			for _, written := range stored.Paths() {
+				_ = writer.DeleteImage(ctx, written)
+			}
+
+			return nil, fmt.Errorf("writing %s image variant: %w", name, err)
+		}
+
+		stored.Variants[name] = dto.ImageVariant{
+			Path:   key,
+			URL:    "",
+			Width:  variant.Width,
+			Height: variant.Height,
+		}
+	}
+
+	return stored, nil
+}
+
+func processUpload(processor *imaging.Processor, upload Upload) (*imaging.Result, error) {
+	file, err := upload.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open uploaded file: %w", err)
+	}
+	defer file.Close() //nolint:errcheck
+
+	result, err := processor.Process(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process image: %w", err)
+	}
+
+	return result, nil
 }
@@ -0,0 +1,60 @@
+// Package fake implements a tracking provider which pretends every shipment is delivered
+// after a fixed delay, for development and tests.
+package fake
+
+import (
+	"context"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"time"
+)
+
+const transitShare = 2
+
+type fakeTrackingProvider struct {
+	deliveryDelay time.Duration
+}
+
+// NewFakeTrackingProvider creates a tracking provider which reports shipments in transit
+// halfway through deliveryDelay and delivered once it has passed since shipping.
+func NewFakeTrackingProvider(deliveryDelay time.Duration) *fakeTrackingProvider { //nolint:revive
+	return &fakeTrackingProvider{
+		deliveryDelay: deliveryDelay,
+	}
+}
+
+// Track returns events which would have happened by now.
+func (p *fakeTrackingProvider) Track(
+	_ context.Context,
+	shipment *dto.Shipment,
+) ([]dto.ShipmentEvent, error) {
+	steps := []struct {
+		status      dto.ShipmentStatus
+		description string
+		after       time.Duration
+	}{
+		{dto.ShipmentStatusInTransit, "Package is in transit", p.deliveryDelay / transitShare},
+		{dto.ShipmentStatusDelivered, "Package was delivered", p.deliveryDelay},
+	}
+
+	now := time.Now()
+	events := []dto.ShipmentEvent{}
+
+	for _, step := range steps {
+		occurredAt := shipment.ShippedAt.Add(step.after)
+		if occurredAt.After(now) {
+			break
+		}
+
+		events = append(events, dto.ShipmentEvent{
+			ID:          "",
+			ShipmentID:  shipment.ID,
+			Status:      step.status,
+			Description: step.description,
+			Location:    "",
+			OccurredAt:  occurredAt,
+			CreatedAt:   time.Time{},
+		})
+	}
+
+	return events, nil
+}
@@ -0,0 +1,56 @@
+package fake
+
+import (
+	"context"
+	"golang-connect-marketplace/internal/marketplace/dto"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func shipmentEventStatuses(t *testing.T, shippedAgo time.Duration) []dto.ShipmentStatus {
+	t.Helper()
+
+	p := NewFakeTrackingProvider(time.Hour)
+
+	events, err := p.Track(context.Background(), &dto.Shipment{ //nolint:exhaustruct
+		ID:        "shipment_1",
+		ShippedAt: time.Now().Add(-shippedAgo),
+	})
+	require.NoError(t, err)
+
+	statuses := make([]dto.ShipmentStatus, 0, len(events))
+	for _, e := range events {
+		assert.Equal(t, "shipment_1", e.ShipmentID)
+
+		statuses = append(statuses, e.Status)
+	}
+
+	return statuses
+}
+
+func TestFakeTrackingProvider_NoEventsRightAfterShipping(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, shipmentEventStatuses(t, time.Minute))
+}
+
+func TestFakeTrackingProvider_InTransitHalfwayThroughDelay(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t,
+		[]dto.ShipmentStatus{dto.ShipmentStatusInTransit},
+		shipmentEventStatuses(t, 45*time.Minute),
+	)
+}
+
+func TestFakeTrackingProvider_DeliveredAfterDelay(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t,
+		[]dto.ShipmentStatus{dto.ShipmentStatusInTransit, dto.ShipmentStatusDelivered},
+		shipmentEventStatuses(t, 2*time.Hour),
+	)
+}
@@ -0,0 +1,13 @@
+// Package tracking handles following shipments with carriers.
+package tracking
+
+import (
+	"context"
+	"golang-connect-marketplace/internal/marketplace/dto"
+)
+
+// TrackingProvider defines methods for fetching shipment progress from carriers.
+type TrackingProvider interface { //nolint:revive
+	// Track returns all known events of the shipment, in any order.
+	Track(ctx context.Context, shipment *dto.Shipment) ([]dto.ShipmentEvent, error)
+}
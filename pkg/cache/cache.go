@@ -0,0 +1,95 @@
+// Package cache provides a small in-memory cache with expiring entries.
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// Cache stores values for a fixed time to live. When full, expired entries are removed first
+// and then the entry closest to expiry.
+type Cache[K comparable, V any] struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[K]entry[V]
+	now        func() time.Time
+}
+
+// New creates a cache keeping values for ttl and holding at most maxEntries values.
+func New[K comparable, V any](ttl time.Duration, maxEntries int) *Cache[K, V] {
+	return &Cache[K, V]{
+		mu:         sync.Mutex{},
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[K]entry[V]),
+		now:        time.Now,
+	}
+}
+
+// Get returns value stored under key if it hasn't expired yet.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || !c.now().Before(e.expiresAt) {
+		delete(c.entries, key)
+
+		var zero V
+
+		return zero, false
+	}
+
+	return e.value, true
+}
+
+// Set stores value under key, replacing any previous value.
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		c.evict(now)
+	}
+
+	c.entries[key] = entry[V]{value: value, expiresAt: now.Add(c.ttl)}
+}
+
+// Len returns number of stored entries, including expired ones not removed yet.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.entries)
+}
+
+func (c *Cache[K, V]) evict(now time.Time) {
+	var (
+		oldestKey K
+		oldest    time.Time
+	)
+
+	for key, e := range c.entries {
+		if !now.Before(e.expiresAt) {
+			delete(c.entries, key)
+
+			continue
+		}
+
+		if oldest.IsZero() || e.expiresAt.Before(oldest) {
+			oldestKey, oldest = key, e.expiresAt
+		}
+	}
+
+	if len(c.entries) >= c.maxEntries && !oldest.IsZero() {
+		delete(c.entries, oldestKey)
+	}
+}
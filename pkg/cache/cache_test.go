@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	return f.now
+}
+
+func newTestCache(ttl time.Duration, maxEntries int) (*Cache[string, int], *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	c := New[string, int](ttl, maxEntries)
+	c.now = clock.Now
+
+	return c, clock
+}
+
+func TestCache_GetReturnsStoredValue(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newTestCache(time.Minute, 10)
+
+	c.Set("a", 1)
+
+	value, ok := c.Get("a")
+	require.True(t, ok)
+	require.Equal(t, 1, value)
+
+	_, ok = c.Get("missing")
+	require.False(t, ok)
+}
+
+func TestCache_GetSkipsExpiredValues(t *testing.T) {
+	t.Parallel()
+
+	c, clock := newTestCache(time.Minute, 10)
+
+	c.Set("a", 1)
+	clock.now = clock.now.Add(time.Minute)
+
+	_, ok := c.Get("a")
+	require.False(t, ok)
+	require.Equal(t, 0, c.Len())
+}
+
+func TestCache_SetEvictsExpiredThenOldest(t *testing.T) {
+	t.Parallel()
+
+	c, clock := newTestCache(time.Minute, 2)
+
+	c.Set("a", 1)
+	clock.now = clock.now.Add(10 * time.Second)
+	c.Set("b", 2)
+	clock.now = clock.now.Add(10 * time.Second)
+	c.Set("c", 3)
+
+	_, ok := c.Get("a")
+	require.False(t, ok)
+
+	value, ok := c.Get("b")
+	require.True(t, ok)
+	require.Equal(t, 2, value)
+
+	clock.now = clock.now.Add(45 * time.Second)
+	c.Set("d", 4)
+
+	_, ok = c.Get("b")
+	require.False(t, ok)
+
+	value, ok = c.Get("c")
+	require.True(t, ok)
+	require.Equal(t, 3, value)
+	require.Equal(t, 2, c.Len())
+}
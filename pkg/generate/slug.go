@@ -0,0 +1,35 @@
+package generate
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Slug generates a lowercase, URL-safe slug from s, e.g. "Cars & Bikes" becomes "cars-bikes".
+// Accented letters are reduced to their base letter.
+func Slug(s string) string {
+	var b strings.Builder
+
+	dash := false
+
+	for _, r := range norm.NFD.String(s) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+
+			b.WriteRune(unicode.ToLower(r))
+
+			dash = false
+		default:
+			dash = true
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,27 @@
+package generate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlug(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		in   string
+		want string
+	}{
+		{"Cars", "cars"},
+		{"Cars & Bikes", "cars-bikes"},
+		{"  T-Shirts  ", "t-shirts"},
+		{"Électronique Été", "electronique-ete"},
+		{"PS5 / Xbox", "ps5-xbox"},
+		{"---", ""},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.want, Slug(tc.in), tc.in)
+	}
+}
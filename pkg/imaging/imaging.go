@@ -0,0 +1,294 @@
+// Package imaging decodes uploaded images, normalizes their orientation, strips metadata
+// and generates resized variants.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // registers GIF decoder, only the first frame is decoded.
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // registers WebP decoder.
+)
+
+var (
+	// ErrInvalidImage is the base error for images rejected by validation.
+	ErrInvalidImage = errors.New("invalid image")
+	// ErrUnsupportedFormat is returned when image format can't be processed.
+	ErrUnsupportedFormat = fmt.Errorf(
+		"%w: unsupported image format, allowed formats are JPEG, PNG, WebP and GIF",
+		ErrInvalidImage,
+	)
+	// ErrHEICNotSupported is returned when a HEIC/HEIF image is uploaded.
+	ErrHEICNotSupported = fmt.Errorf(
+		"%w: HEIC/HEIF images are not supported, export the photo as JPEG and try again",
+		ErrInvalidImage,
+	)
+	// ErrFileTooLarge is returned when image file exceeds the configured byte limit.
+	ErrFileTooLarge = fmt.Errorf("%w: file is too large", ErrInvalidImage)
+	// ErrDimensionsTooSmall is returned when image is smaller than the configured minimum.
+	ErrDimensionsTooSmall = fmt.Errorf("%w: image dimensions are too small", ErrInvalidImage)
+	// ErrDimensionsTooLarge is returned when image is larger than the configured maximum.
+	ErrDimensionsTooLarge = fmt.Errorf("%w: image dimensions are too large", ErrInvalidImage)
+)
+
+const (
+	formatJPEG = "jpeg"
+	formatPNG  = "png"
+	formatGIF  = "gif"
+	formatWebP = "webp"
+
+	defaultJPEGQuality = 85
+	maxJPEGQuality     = 100
+
+	// defaultMaxSourceDimension keeps the decompression bomb guard when no limit is set.
+	defaultMaxSourceDimension = 12000
+)
+
+// Variant describes a named resized copy of an image that fits into a MaxSize x MaxSize box.
+type Variant struct {
+	Name    string
+	MaxSize int
+}
+
+// Options configures image processing.
+//
+// MaxBytes, MinDimension and MaxSourceDimension are validation limits applied to the upload,
+// zero disables a limit except MaxSourceDimension, which falls back to 12000px so that huge
+// images are rejected before being decoded. MaxDimension caps the size of the stored original.
+type Options struct {
+	MaxBytes           int64
+	MinDimension       int
+	MaxSourceDimension int
+	MaxDimension       int
+	JPEGQuality        int
+	Variants           []Variant
+}
+
+// Image is an encoded image ready to be stored.
+type Image struct {
+	Data        []byte
+	Ext         string
+	ContentType string
+	Width       int
+	Height      int
+}
+
+// Result holds the processed original image and its variants keyed by variant name.
+type Result struct {
+	Original Image
+	Variants map[string]Image
+}
+
+// Processor processes uploaded images.
+type Processor struct {
+	opts Options
+}
+
+// NewProcessor creates a new image processor.
+func NewProcessor(opts Options) *Processor {
+	if opts.JPEGQuality <= 0 || opts.JPEGQuality > maxJPEGQuality {
+		opts.JPEGQuality = defaultJPEGQuality
+	}
+
+	if opts.MaxSourceDimension <= 0 {
+		opts.MaxSourceDimension = defaultMaxSourceDimension
+	}
+
+	return &Processor{opts: opts}
+}
+
+// Process validates and decodes an image, applies its EXIF orientation, caps its dimensions
+// and re-encodes it together with all configured variants. Re-encoding drops all metadata.
+// Animated GIFs are reduced to their first frame.
+func (p *Processor) Process(r io.Reader) (*Result, error) {
+	data, err := p.read(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if isHEIC(data) {
+		return nil, ErrHEICNotSupported
+	}
+
+	format, err := p.checkConfig(data)
+	if err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnsupportedFormat, err)
+	}
+
+	if format == formatJPEG {
+		img = applyOrientation(img, exifOrientation(data))
+	}
+
+	img = fit(img, p.opts.MaxDimension)
+	format = outputFormat(format, img)
+
+	original, err := p.encode(img, format)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Original: *original,
+		Variants: make(map[string]Image, len(p.opts.Variants)),
+	}
+
+	for _, v := range p.opts.Variants {
+		encoded, err := p.encode(fit(img, v.MaxSize), format)
+		if err != nil {
+			return nil, fmt.Errorf("encoding %s variant: %w", v.Name, err)
+		}
+
+		result.Variants[v.Name] = *encoded
+	}
+
+	return result, nil
+}
+
+func (p *Processor) read(r io.Reader) ([]byte, error) {
+	if p.opts.MaxBytes > 0 {
+		r = io.LimitReader(r, p.opts.MaxBytes+1)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading image: %w", err)
+	}
+
+	if p.opts.MaxBytes > 0 && int64(len(data)) > p.opts.MaxBytes {
+		return nil, fmt.Errorf("%w: limit is %d bytes", ErrFileTooLarge, p.opts.MaxBytes)
+	}
+
+	return data, nil
+}
+
+// checkConfig reads only the image header, so that decompression bombs are rejected
+// before any pixel data is allocated.
+func (p *Processor) checkConfig(data []byte) (string, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrUnsupportedFormat, err)
+	}
+
+	switch format {
+	case formatJPEG, formatPNG, formatGIF, formatWebP:
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+
+	if p.opts.MinDimension > 0 &&
+		(cfg.Width < p.opts.MinDimension || cfg.Height < p.opts.MinDimension) {
+		return "", fmt.Errorf(
+			"%w: got %dx%d, minimum is %dpx per side",
+			ErrDimensionsTooSmall, cfg.Width, cfg.Height, p.opts.MinDimension,
+		)
+	}
+
+	if cfg.Width > p.opts.MaxSourceDimension || cfg.Height > p.opts.MaxSourceDimension {
+		return "", fmt.Errorf(
+			"%w: got %dx%d, maximum is %dpx per side",
+			ErrDimensionsTooLarge, cfg.Width, cfg.Height, p.opts.MaxSourceDimension,
+		)
+	}
+
+	return format, nil
+}
+
+// outputFormat picks the format images are re-encoded to. JPEG and PNG keep their format,
+// GIF becomes PNG and WebP becomes JPEG unless it has transparency.
+func outputFormat(format string, img image.Image) string {
+	switch format {
+	case formatJPEG, formatPNG:
+		return format
+	case formatWebP:
+		if o, ok := img.(interface{ Opaque() bool }); ok && o.Opaque() {
+			return formatJPEG
+		}
+
+		return formatPNG
+	default:
+		return formatPNG
+	}
+}
+
+// isHEIC reports whether data starts with an ISO BMFF ftyp box of a HEIC/HEIF brand.
+func isHEIC(data []byte) bool {
+	const brandEnd = 12
+
+	if len(data) < brandEnd || string(data[4:8]) != "ftyp" {
+		return false
+	}
+
+	switch string(data[8:brandEnd]) {
+	case "heic", "heix", "hevc", "hevx", "heim", "heis", "mif1", "msf1":
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *Processor) encode(img image.Image, format string) (*Image, error) {
+	var buf bytes.Buffer
+
+	out := &Image{
+		Data:        nil,
+		Ext:         ".jpg",
+		ContentType: "image/jpeg",
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+	}
+
+	switch format {
+	case formatPNG:
+		out.Ext = ".png"
+		out.ContentType = "image/png"
+
+		err := png.Encode(&buf, img)
+		if err != nil {
+			return nil, fmt.Errorf("encoding png: %w", err)
+		}
+	default:
+		err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: p.opts.JPEGQuality})
+		if err != nil {
+			return nil, fmt.Errorf("encoding jpeg: %w", err)
+		}
+	}
+
+	out.Data = buf.Bytes()
+
+	return out, nil
+}
+
+// fit downscales img so that neither side exceeds maxSize, preserving aspect ratio.
+// Images already within bounds are returned unchanged.
+func fit(img image.Image, maxSize int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if maxSize <= 0 || (width <= maxSize && height <= maxSize) {
+		return img
+	}
+
+	newWidth, newHeight := maxSize, maxSize
+
+	if width > height {
+		newHeight = max(1, height*maxSize/width)
+	} else {
+		newWidth = max(1, width*maxSize/height)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+
+	return dst
+}
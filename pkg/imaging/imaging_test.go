@@ -0,0 +1,275 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testImage(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := range height {
+		for x := range width {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 0, A: 255}) //nolint:gosec
+		}
+	}
+
+	return img
+}
+
+func encodeJPEG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	err := jpeg.Encode(&buf, img, nil)
+	require.NoError(t, err)
+
+	return buf.Bytes()
+}
+
+// withExifOrientation inserts an APP1 EXIF segment with the given orientation right after SOI.
+func withExifOrientation(jpg []byte, orientation uint16) []byte {
+	tiff := make([]byte, 0, 26)
+	tiff = append(tiff, 'M', 'M', 0, 42, 0, 0, 0, 8)
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, exifOrientationTag)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3)
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+
+	payload := append(append([]byte{}, exifHeader...), tiff...)
+
+	segment := []byte{0xFF, 0xE1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(payload)+2)) //nolint:gosec
+	segment = append(segment, payload...)
+
+	out := append([]byte{}, jpg[:2]...)
+	out = append(out, segment...)
+
+	return append(out, jpg[2:]...)
+}
+
+func TestProcess_CapsDimensionsAndCreatesVariants(t *testing.T) {
+	t.Parallel()
+
+	p := NewProcessor(Options{
+		MaxDimension: 400,
+		JPEGQuality:  80,
+		Variants: []Variant{
+			{Name: "thumb", MaxSize: 50},
+			{Name: "medium", MaxSize: 200},
+			{Name: "large", MaxSize: 1000},
+		},
+	})
+
+	res, err := p.Process(bytes.NewReader(encodeJPEG(t, testImage(800, 400))))
+	require.NoError(t, err)
+
+	assert.Equal(t, 400, res.Original.Width)
+	assert.Equal(t, 200, res.Original.Height)
+	assert.Equal(t, ".jpg", res.Original.Ext)
+	assert.Equal(t, "image/jpeg", res.Original.ContentType)
+
+	require.Len(t, res.Variants, 3)
+	assert.Equal(t, 50, res.Variants["thumb"].Width)
+	assert.Equal(t, 25, res.Variants["thumb"].Height)
+	assert.Equal(t, 200, res.Variants["medium"].Width)
+	assert.Equal(t, 400, res.Variants["large"].Width, "variants must never upscale")
+
+	decoded, err := jpeg.Decode(bytes.NewReader(res.Variants["medium"].Data))
+	require.NoError(t, err)
+	assert.Equal(t, 200, decoded.Bounds().Dx())
+}
+
+func TestProcess_KeepsPNGFormat(t *testing.T) {
+	t.Parallel()
+
+	res, err := NewProcessor(Options{}).Process(bytes.NewReader(encodePNG(t, testImage(10, 20))))
+	require.NoError(t, err)
+
+	assert.Equal(t, ".png", res.Original.Ext)
+	assert.Equal(t, "image/png", res.Original.ContentType)
+	assert.Equal(t, 10, res.Original.Width)
+	assert.Equal(t, 20, res.Original.Height)
+}
+
+func TestProcess_AppliesOrientationAndStripsExif(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		orientation uint16
+		wantWidth   int
+		wantHeight  int
+	}{
+		{orientationNormal, 40, 20},
+		{orientationRotate180, 40, 20},
+		{orientationRotate90, 20, 40},
+		{orientationRotate270, 20, 40},
+		{orientationTranspose, 20, 40},
+	}
+
+	for _, tc := range testCases {
+		data := withExifOrientation(encodeJPEG(t, testImage(40, 20)), tc.orientation)
+		require.Equal(t, int(tc.orientation), exifOrientation(data))
+
+		res, err := NewProcessor(Options{}).Process(bytes.NewReader(data))
+		require.NoError(t, err)
+
+		assert.Equal(t, tc.wantWidth, res.Original.Width, "orientation %d", tc.orientation)
+		assert.Equal(t, tc.wantHeight, res.Original.Height, "orientation %d", tc.orientation)
+		assert.NotContains(t, string(res.Original.Data), "Exif", "metadata must be stripped")
+	}
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	err := png.Encode(&buf, img)
+	require.NoError(t, err)
+
+	return buf.Bytes()
+}
+
+// withPNGDimensions rewrites the IHDR chunk of a PNG so that it claims different dimensions.
+func withPNGDimensions(data []byte, width, height uint32) []byte {
+	const (
+		ihdrDataStart = 16
+		ihdrDataEnd   = 29
+	)
+
+	out := append([]byte{}, data...)
+	binary.BigEndian.PutUint32(out[ihdrDataStart:], width)
+	binary.BigEndian.PutUint32(out[ihdrDataStart+4:], height)
+	binary.BigEndian.PutUint32(out[ihdrDataEnd:], crc32.ChecksumIEEE(out[12:ihdrDataEnd]))
+
+	return out
+}
+
+func TestProcess_GIFUsesFirstFrame(t *testing.T) {
+	t.Parallel()
+
+	frame := func(c color.Color) *image.Paletted {
+		img := image.NewPaletted(image.Rect(0, 0, 8, 6), palette.Plan9)
+		for i := range img.Pix {
+			img.Pix[i] = uint8(img.Palette.Index(c)) //nolint:gosec
+		}
+
+		return img
+	}
+
+	var buf bytes.Buffer
+
+	err := gif.EncodeAll(&buf, &gif.GIF{
+		Image: []*image.Paletted{frame(color.White), frame(color.Black)},
+		Delay: []int{10, 10},
+	})
+	require.NoError(t, err)
+
+	res, err := NewProcessor(Options{}).Process(&buf)
+	require.NoError(t, err)
+
+	assert.Equal(t, ".png", res.Original.Ext)
+
+	decoded, err := png.Decode(bytes.NewReader(res.Original.Data))
+	require.NoError(t, err)
+
+	r, g, b, _ := decoded.At(0, 0).RGBA()
+	assert.Equal(t, []uint32{0xffff, 0xffff, 0xffff}, []uint32{r, g, b})
+}
+
+func TestProcess_WebP(t *testing.T) {
+	t.Parallel()
+
+	// 1x1 lossless WebP.
+	data, err := base64.StdEncoding.DecodeString(
+		"UklGRhoAAABXRUJQVlA4TA0AAAAvAAAAEAcQERGIiP4HAA==",
+	)
+	require.NoError(t, err)
+
+	res, err := NewProcessor(Options{}).Process(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, res.Original.Width)
+	assert.Contains(t, []string{".jpg", ".png"}, res.Original.Ext)
+}
+
+func TestProcess_RejectsHEIC(t *testing.T) {
+	t.Parallel()
+
+	heic := append([]byte{0, 0, 0, 24}, []byte("ftypheic\x00\x00\x00\x00mif1heic")...)
+
+	_, err := NewProcessor(Options{}).Process(bytes.NewReader(heic))
+	require.ErrorIs(t, err, ErrHEICNotSupported)
+	require.ErrorIs(t, err, ErrInvalidImage)
+}
+
+func TestProcess_Limits(t *testing.T) {
+	t.Parallel()
+
+	small := encodePNG(t, testImage(30, 30))
+
+	testCases := []struct {
+		desc    string
+		opts    Options
+		data    []byte
+		wantErr error
+	}{
+		{"file too large", Options{MaxBytes: 10}, small, ErrFileTooLarge},
+		{"too small", Options{MinDimension: 50}, small, ErrDimensionsTooSmall},
+		{"too large", Options{MaxSourceDimension: 20}, small, ErrDimensionsTooLarge},
+		{
+			"decompression bomb is rejected from header",
+			Options{MaxSourceDimension: 10000},
+			withPNGDimensions(small, 100000, 100000),
+			ErrDimensionsTooLarge,
+		},
+		{
+			"decompression bomb is rejected without configured limit",
+			Options{},
+			withPNGDimensions(small, 100000, 100000),
+			ErrDimensionsTooLarge,
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := NewProcessor(tc.opts).Process(bytes.NewReader(tc.data))
+		require.ErrorIs(t, err, tc.wantErr, tc.desc)
+	}
+
+	_, err := NewProcessor(Options{MaxBytes: 1 << 20, MinDimension: 30, MaxSourceDimension: 30}).
+		Process(bytes.NewReader(small))
+	require.NoError(t, err)
+}
+
+func TestProcess_UnsupportedFormat(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewProcessor(Options{}).Process(strings.NewReader("definitely not an image"))
+	require.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestOrientedPoint_Rotate90(t *testing.T) {
+	t.Parallel()
+
+	// top-left pixel of a 4x2 image ends up in the top-right corner of the 2x4 result.
+	x, y := orientedPoint(orientationRotate90, 0, 0, 4, 2)
+	assert.Equal(t, 1, x)
+	assert.Equal(t, 0, y)
+}
@@ -0,0 +1,170 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/draw"
+)
+
+const (
+	orientationNormal     = 1
+	orientationFlipH      = 2
+	orientationRotate180  = 3
+	orientationFlipV      = 4
+	orientationTranspose  = 5
+	orientationRotate90   = 6
+	orientationTransverse = 7
+	orientationRotate270  = 8
+
+	exifOrientationTag = 0x0112
+	ifdEntrySize       = 12
+	tiffHeaderSize     = 8
+	tiffMagic          = 42
+)
+
+var exifHeader = []byte("Exif\x00\x00")
+
+// exifOrientation returns the EXIF orientation of a JPEG image or orientationNormal when
+// it is missing or can't be parsed.
+func exifOrientation(data []byte) int {
+	const (
+		markerPrefix = 0xFF
+		markerSOI    = 0xD8
+		markerSOS    = 0xDA
+		markerAPP1   = 0xE1
+	)
+
+	if len(data) < 4 || data[0] != markerPrefix || data[1] != markerSOI {
+		return orientationNormal
+	}
+
+	pos := 2
+
+	for pos+4 <= len(data) {
+		if data[pos] != markerPrefix {
+			return orientationNormal
+		}
+
+		marker := data[pos+1]
+		if marker == markerSOS {
+			return orientationNormal
+		}
+
+		segmentLen := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		segmentEnd := pos + 2 + segmentLen
+
+		if segmentLen < 2 || segmentEnd > len(data) {
+			return orientationNormal
+		}
+
+		if marker == markerAPP1 {
+			segment := data[pos+4 : segmentEnd]
+			if bytes.HasPrefix(segment, exifHeader) {
+				return tiffOrientation(segment[len(exifHeader):])
+			}
+		}
+
+		pos = segmentEnd
+	}
+
+	return orientationNormal
+}
+
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < tiffHeaderSize {
+		return orientationNormal
+	}
+
+	var order binary.ByteOrder
+
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return orientationNormal
+	}
+
+	if order.Uint16(tiff[2:4]) != tiffMagic {
+		return orientationNormal
+	}
+
+	ifdOffset := int(order.Uint32(tiff[4:8]))
+	if ifdOffset+2 > len(tiff) {
+		return orientationNormal
+	}
+
+	entries := int(order.Uint16(tiff[ifdOffset : ifdOffset+2]))
+
+	for i := range entries {
+		entry := ifdOffset + 2 + i*ifdEntrySize
+		if entry+ifdEntrySize > len(tiff) {
+			return orientationNormal
+		}
+
+		if order.Uint16(tiff[entry:entry+2]) != exifOrientationTag {
+			continue
+		}
+
+		value := int(order.Uint16(tiff[entry+8 : entry+10]))
+		if value < orientationNormal || value > orientationRotate270 {
+			return orientationNormal
+		}
+
+		return value
+	}
+
+	return orientationNormal
+}
+
+// applyOrientation transforms img so that it is displayed upright.
+func applyOrientation(img image.Image, orientation int) image.Image {
+	if orientation <= orientationNormal || orientation > orientationRotate270 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	src := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+
+	dstWidth, dstHeight := width, height
+	if orientation >= orientationTranspose {
+		dstWidth, dstHeight = height, width
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+
+	for y := range height {
+		for x := range width {
+			dx, dy := orientedPoint(orientation, x, y, width, height)
+			dst.Set(dx, dy, src.At(x, y))
+		}
+	}
+
+	return dst
+}
+
+func orientedPoint(orientation, x, y, width, height int) (int, int) {
+	switch orientation {
+	case orientationFlipH:
+		return width - 1 - x, y
+	case orientationRotate180:
+		return width - 1 - x, height - 1 - y
+	case orientationFlipV:
+		return x, height - 1 - y
+	case orientationTranspose:
+		return y, x
+	case orientationRotate90:
+		return height - 1 - y, x
+	case orientationTransverse:
+		return height - 1 - y, width - 1 - x
+	case orientationRotate270:
+		return y, width - 1 - x
+	default:
+		return x, y
+	}
+}
@@ -0,0 +1,40 @@
+package money
+
+// currencies lists supported ISO 4217 currencies. Minimums are roughly 10 US dollars,
+// the smallest price worth selling for once payment processing fees are paid.
+var currencies = map[string]Currency{
+	"AED": {Code: "AED", Exponent: 2, Minimum: 4000},
+	"AUD": {Code: "AUD", Exponent: 2, Minimum: 1500},
+	"BGN": {Code: "BGN", Exponent: 2, Minimum: 2000},
+	"BHD": {Code: "BHD", Exponent: 3, Minimum: 4000},
+	"BRL": {Code: "BRL", Exponent: 2, Minimum: 5000},
+	"CAD": {Code: "CAD", Exponent: 2, Minimum: 1500},
+	"CHF": {Code: "CHF", Exponent: 2, Minimum: 1000},
+	"CLP": {Code: "CLP", Exponent: 0, Minimum: 10000},
+	"CZK": {Code: "CZK", Exponent: 2, Minimum: 25000},
+	"DKK": {Code: "DKK", Exponent: 2, Minimum: 7000},
+	"EUR": {Code: "EUR", Exponent: 2, Minimum: 1000},
+	"GBP": {Code: "GBP", Exponent: 2, Minimum: 1000},
+	"HKD": {Code: "HKD", Exponent: 2, Minimum: 8000},
+	"HUF": {Code: "HUF", Exponent: 2, Minimum: 400000},
+	"ILS": {Code: "ILS", Exponent: 2, Minimum: 4000},
+	"INR": {Code: "INR", Exponent: 2, Minimum: 80000},
+	"ISK": {Code: "ISK", Exponent: 0, Minimum: 1500},
+	"JOD": {Code: "JOD", Exponent: 3, Minimum: 7000},
+	"JPY": {Code: "JPY", Exponent: 0, Minimum: 1500},
+	"KRW": {Code: "KRW", Exponent: 0, Minimum: 15000},
+	"KWD": {Code: "KWD", Exponent: 3, Minimum: 3000},
+	"MXN": {Code: "MXN", Exponent: 2, Minimum: 20000},
+	"NOK": {Code: "NOK", Exponent: 2, Minimum: 10000},
+	"NZD": {Code: "NZD", Exponent: 2, Minimum: 1500},
+	"OMR": {Code: "OMR", Exponent: 3, Minimum: 4000},
+	"PLN": {Code: "PLN", Exponent: 2, Minimum: 4000},
+	"RON": {Code: "RON", Exponent: 2, Minimum: 5000},
+	"SEK": {Code: "SEK", Exponent: 2, Minimum: 10000},
+	"SGD": {Code: "SGD", Exponent: 2, Minimum: 1500},
+	"TND": {Code: "TND", Exponent: 3, Minimum: 30000},
+	"TRY": {Code: "TRY", Exponent: 2, Minimum: 30000},
+	"USD": {Code: "USD", Exponent: 2, Minimum: 1000},
+	"VND": {Code: "VND", Exponent: 0, Minimum: 250000},
+	"ZAR": {Code: "ZAR", Exponent: 2, Minimum: 20000},
+}
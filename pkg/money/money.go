@@ -0,0 +1,96 @@
+// Package money provides ISO 4217 currencies and amounts of money kept in minor units,
+// e.g. cents for USD and yen for JPY, which has no minor unit.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	decimalBase    = 10
+	percentDivisor = 100
+)
+
+// ErrUnknownCurrency is returned when a currency code isn't in the registry.
+var ErrUnknownCurrency = errors.New("unknown currency")
+
+// Currency describes an ISO 4217 currency. Exponent is the number of decimal places of
+// the minor unit and Minimum is the smallest accepted price in minor units.
+type Currency struct {
+	Code     string
+	Exponent int
+	Minimum  int64
+}
+
+// Lookup returns the registered currency with the code, which is case insensitive.
+func Lookup(code string) (Currency, error) {
+	currency, ok := currencies[strings.ToUpper(code)]
+	if !ok {
+		return Currency{}, fmt.Errorf("%w: %q", ErrUnknownCurrency, code)
+	}
+
+	return currency, nil
+}
+
+// Money is an amount in minor units of its currency.
+type Money struct {
+	Amount   int64
+	Currency Currency
+}
+
+// New returns amount of minor units of the currency with the code.
+func New(amount int64, code string) (Money, error) {
+	currency, err := Lookup(code)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// Percent returns percent of m, rounded down to a whole minor unit.
+func (m Money) Percent(percent int64) Money {
+	return Money{Amount: m.Amount * percent / percentDivisor, Currency: m.Currency}
+}
+
+// Plus returns m increased by amount minor units.
+func (m Money) Plus(amount int64) Money {
+	return Money{Amount: m.Amount + amount, Currency: m.Currency}
+}
+
+// BelowMinimum reports whether m is less than the minimum price of its currency.
+func (m Money) BelowMinimum() bool {
+	return m.Amount < m.Currency.Minimum
+}
+
+// String formats m in major units followed by the currency code, e.g. "12.50 USD",
+// "1500 JPY" or "3.000 KWD".
+func (m Money) String() string {
+	sign := ""
+	amount := m.Amount
+
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	if m.Currency.Exponent == 0 {
+		return fmt.Sprintf("%s%d %s", sign, amount, m.Currency.Code)
+	}
+
+	unit := int64(1)
+	for range m.Currency.Exponent {
+		unit *= decimalBase
+	}
+
+	return fmt.Sprintf(
+		"%s%d.%0*d %s",
+		sign,
+		amount/unit,
+		m.Currency.Exponent,
+		amount%unit,
+		m.Currency.Code,
+	)
+}
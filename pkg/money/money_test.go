@@ -0,0 +1,64 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func mustNew(t *testing.T, amount int64, code string) Money {
+	t.Helper()
+
+	m, err := New(amount, code)
+	require.NoError(t, err)
+
+	return m
+}
+
+func TestLookup_IsCaseInsensitive(t *testing.T) {
+	t.Parallel()
+
+	currency, err := Lookup("jpy")
+	require.NoError(t, err)
+	require.Equal(t, "JPY", currency.Code)
+	require.Equal(t, 0, currency.Exponent)
+}
+
+func TestLookup_RejectsUnknownCurrency(t *testing.T) {
+	t.Parallel()
+
+	_, err := Lookup("abc")
+	require.ErrorIs(t, err, ErrUnknownCurrency)
+}
+
+func TestNew_RejectsUnknownCurrency(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(1000, "XYZ")
+	require.ErrorIs(t, err, ErrUnknownCurrency)
+}
+
+func TestMoney_PercentRoundsDown(t *testing.T) {
+	t.Parallel()
+
+	m := mustNew(t, 1999, "USD")
+
+	require.Equal(t, int64(79), m.Percent(4).Amount)
+	require.Equal(t, int64(179), m.Percent(4).Plus(100).Amount)
+}
+
+func TestMoney_BelowMinimumUsesCurrencyMinimum(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, mustNew(t, 999, "USD").BelowMinimum())
+	require.False(t, mustNew(t, 1500, "JPY").BelowMinimum())
+}
+
+func TestMoney_StringUsesCurrencyExponent(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "12.05 USD", mustNew(t, 1205, "USD").String())
+	require.Equal(t, "1500 JPY", mustNew(t, 1500, "JPY").String())
+	require.Equal(t, "3.005 KWD", mustNew(t, 3005, "KWD").String())
+	require.Equal(t, "-0.50 EUR", mustNew(t, -50, "EUR").String())
+}
@@ -26,10 +26,38 @@ func ValidateDto(ctx echo.Context, dto any) error {
 		return fmt.Errorf("dto binding failed: %w", err)
 	}
 
-	err = validator.New().Struct(dto)
+	return ValidateStruct(dto)
+}
+
+// ValidateStruct validates the given struct by its validate tags, for data which doesn't come
+// from a request body, e.g. rows of an uploaded file.
+func ValidateStruct(dto any) error {
+	err := validator.New().Struct(dto)
 	if err != nil {
 		return fmt.Errorf("dto validation failed: %w", err)
 	}
 
 	return nil
 }
+
+// FieldErrors lists failed validation rules of err as "Field: rule" messages.
+// Errors which are not validation errors are returned as a single message.
+func FieldErrors(err error) []string {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return []string{err.Error()}
+	}
+
+	messages := make([]string, 0, len(validationErrs))
+
+	for _, fe := range validationErrs {
+		rule := fe.Tag()
+		if fe.Param() != "" {
+			rule += "=" + fe.Param()
+		}
+
+		messages = append(messages, fe.Field()+": "+rule)
+	}
+
+	return messages
+}
@@ -119,3 +119,23 @@ func TestValidateDto_PassingNonPointerDto(t *testing.T) {
 	require.Error(t, err)
 	require.ErrorContains(t, err, "ValidateDto requires a non-nil pointer")
 }
+
+func TestValidateStruct_FieldErrors(t *testing.T) {
+	t.Parallel()
+
+	err := ValidateStruct(&TestDto{Name: "", Email: "sim@email.com", Password: "short"})
+	require.Error(t, err)
+	assert.Equal(t, []string{"Name: required", "Password: min=8"}, FieldErrors(err))
+
+	require.NoError(t, ValidateStruct(&TestDto{
+		Name:     "sim",
+		Email:    "sim@email.com",
+		Password: "password123",
+	}))
+}
+
+func TestFieldErrors_OtherErrors(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"boom"}, FieldErrors(errors.New("boom")))
+}